@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/aws-payment-gateway/internal/common/db"
+)
+
+// bootstrapTablesCommand is the subcommand that creates the DynamoDB tables
+const bootstrapTablesCommand = "bootstrap-tables"
+
+// bootstrapTables creates the auth and audit tables (with their indexes) if
+// they do not exist. Safe to run repeatedly.
+func bootstrapTables(ctx context.Context, config *Config) error {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(config.AWSRegion))
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := dynamodb.NewFromConfig(cfg, db.WithEndpoint(config.DynamoDBEndpoint))
+
+	specs := []*dynamodb.CreateTableInput{
+		db.AuthTableDefinition(config.DynamoDBTable),
+		db.AuditTableDefinition(config.AuditLogsTable),
+	}
+	for _, spec := range specs {
+		created, err := db.EnsureTable(ctx, client, spec)
+		if err != nil {
+			return err
+		}
+		if created {
+			log.Printf("Created table %s", *spec.TableName)
+		} else {
+			log.Printf("Table %s already exists", *spec.TableName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,778 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/aws-payment-gateway/internal/common/featureflags"
+	"github.com/aws-payment-gateway/internal/common/secrets"
+)
+
+// Config represents the application configuration
+type Config struct {
+	// Environment names the deployment, e.g. development, staging or production;
+	// unset means production so debug-only settings are refused by default
+	Environment    string
+	Port           string
+	AWSRegion      string
+	DynamoDBTable  string
+	AuditLogsTable string
+	// DynamoDBEndpoint overrides the DynamoDB endpoint (e.g. DynamoDB Local)
+	DynamoDBEndpoint string
+	// PostgreSQL configuration
+	PostgreSQLHost     string
+	PostgreSQLPort     string
+	PostgreSQLUser     string
+	PostgreSQLPassword string
+	PostgreSQLDBName   string
+	// DBSecretARN, when set, overrides the PostgreSQL settings from Secrets Manager
+	DBSecretARN string
+	// WebhookSigningSecretARN names the Secrets Manager secret used to sign webhooks
+	WebhookSigningSecretARN string
+	// WebhookSigningSecret is loaded from WebhookSigningSecretARN; empty leaves webhooks unsigned
+	WebhookSigningSecret string
+	// APIKeyCookieName enables cookie-based API key extraction (opt-in)
+	APIKeyCookieName string
+	// APIKeyQueryParam enables query parameter API key extraction (opt-in)
+	APIKeyQueryParam string
+	// ClientCertHeader carries the proxy-verified client certificate fingerprint
+	ClientCertHeader string
+	// RequireUserAgent rejects public endpoint requests that send no User-Agent
+	RequireUserAgent bool
+	// DebugResponseHeaders echoes the caller's account and API key IDs on responses (never in production)
+	DebugResponseHeaders bool
+	// JSONFieldNaming is the key style of JSON responses: snake_case or camelCase
+	JSONFieldNaming string
+	// RequireHTTPSWebhooks rejects http:// webhook URLs (enable in production)
+	RequireHTTPSWebhooks bool
+	// AccountNamePattern, when set, is a regular expression new account names must match in full
+	AccountNamePattern string
+	// AllowedWebhookSchemes are the URL schemes webhook URLs may use
+	AllowedWebhookSchemes []string
+	// AllowedWebhookPorts are the only ports webhook URLs may target
+	AllowedWebhookPorts []int
+	// MaxWebhookURLLength is the longest webhook URL accepted
+	MaxWebhookURLLength int
+	// Audit configuration
+	AuditEventTypes      []string
+	AuditSkipAuthSuccess bool
+	// AuditAuthSuccessSampleRate is the fraction of successful authentications audited
+	AuditAuthSuccessSampleRate float64
+	// AuditSpilloverFile keeps audit events the audit table rejected; empty drops them
+	AuditSpilloverFile          string
+	AuditSpilloverMaxBytes      int
+	AuditSpilloverDrainInterval time.Duration
+	// AuditDetailLevel is how much request detail handler audit events keep: none, summary or full
+	AuditDetailLevel string
+	// AuditDetailRoutes overrides AuditDetailLevel per route, keyed by "[METHOD ]path"
+	AuditDetailRoutes map[string]string
+	// Expiry warning job configuration
+	ExpiryWarningEnabled  bool
+	ExpiryWarningInterval time.Duration
+	ExpiryWarningLeadTime time.Duration
+	// WebhookDisableAfterFailures clears an account's webhook URL after this many
+	// consecutive failed deliveries; 0 never clears it
+	WebhookDisableAfterFailures int
+	// Expired key sweep: marks expired keys that are still active as inactive
+	ExpiredKeySweepEnabled  bool
+	ExpiredKeySweepInterval time.Duration
+	// AllowUnauthenticatedKeyIssuance keeps the legacy public POST /api-keys route
+	AllowUnauthenticatedKeyIssuance bool
+	// DefaultKeyPermissionsEnabled grants DefaultKeyPermissions to keys issued
+	// without permissions instead of rejecting the request
+	DefaultKeyPermissionsEnabled bool
+	DefaultKeyPermissions        []string
+	// SuspendedAllowedPermissions remain usable by keys of suspended accounts; empty disables
+	SuspendedAllowedPermissions []string
+	// MaxActiveKeysPerAccount caps live API keys per account; 0 is unlimited
+	MaxActiveKeysPerAccount int
+	// KeyNameUniqueness is where live key names must be unique: none, account or global
+	KeyNameUniqueness string
+	// ReconcileKeyCountersOnStartup recounts every account's live key counter when the service starts
+	ReconcileKeyCountersOnStartup bool
+	// PermissionAliases map alternative permission names to canonical permissions
+	PermissionAliases map[string]string
+	// ValidatePermissionScope limits validate responses to checks for these permissions; empty returns the full list
+	ValidatePermissionScope []string
+	// ValidateAnonymizeExpired returns a bare valid:false for expired keys instead of identifying them
+	ValidateAnonymizeExpired bool
+	// APIKeyExpiryGracePeriod keeps expired keys validating this long after expiry; 0 disables
+	APIKeyExpiryGracePeriod time.Duration
+	// APIKeyPrefix is prepended to newly generated API keys
+	APIKeyPrefix string
+	// Registration rate limiting (per client IP); 0 disables
+	RegisterRateLimit       int
+	RegisterRateLimitWindow time.Duration
+	// PermissionRateLimits caps requests per API key per window on routes that
+	// require each permission; permissions not listed are not limited
+	PermissionRateLimits      map[string]int
+	PermissionRateLimitWindow time.Duration
+	// RateLimitStore is where rate limit counters are kept: "memory" (per
+	// replica) or "dynamodb" (shared by all replicas)
+	RateLimitStore string
+	// Registration challenge: "none" or "pow"
+	RegistrationChallenge     string
+	RegistrationPoWDifficulty int
+	// IdempotencyHeaders are the headers the idempotency key is read from, in order
+	IdempotencyHeaders []string
+	// IdempotencyMethods are the HTTP methods idempotency keys apply to
+	IdempotencyMethods []string
+	// IdempotencyMaxResponseBytes caps responses stored with idempotency keys
+	IdempotencyMaxResponseBytes int
+	// MaxIdempotencyKeysPerAccount caps unexpired idempotency keys per account; 0 disables it
+	MaxIdempotencyKeysPerAccount int
+	// MaintenanceMode starts the service rejecting writes; toggle at runtime with SIGUSR1
+	MaintenanceMode bool
+	// LenientPagination clamps bad limit/offset values instead of returning 400
+	LenientPagination bool
+	// ShutdownDrainDelay is how long /health/ready reports 503 before the server stops
+	ShutdownDrainDelay time.Duration
+	// SlowRequestThreshold logs a warning for requests taking longer; 0 disables it
+	SlowRequestThreshold time.Duration
+	// AccountStatsCacheTTL is how long account stats are cached; 0 disables caching
+	AccountStatsCacheTTL time.Duration
+	// FeatureFlags are the names of the enabled feature flags (see featureflags.Known)
+	FeatureFlags []string
+
+	// loadErrors holds values that could not be parsed; reported by Validate
+	loadErrors []error
+}
+
+// loadConfig loads configuration from environment variables.
+// Parse failures are recorded and reported by Validate.
+func loadConfig() *Config {
+	env := &envReader{}
+
+	config := &Config{
+		Environment:      env.String("ENVIRONMENT", "production"),
+		Port:             env.String("PORT", "8080"),
+		AWSRegion:        env.String("AWS_REGION", "us-west-2"),
+		DynamoDBTable:    env.String("DYNAMODB_TABLE", "auth-service"),
+		AuditLogsTable:   env.String("AUDIT_LOGS_TABLE", "audit_logs"),
+		DynamoDBEndpoint: env.String("DYNAMODB_ENDPOINT", ""),
+		// PostgreSQL configuration
+		PostgreSQLHost:       env.String("POSTGRES_HOST", "localhost"),
+		PostgreSQLPort:       env.String("POSTGRES_PORT", "5432"),
+		PostgreSQLUser:       env.String("POSTGRES_USER", "postgres"),
+		PostgreSQLPassword:   env.String("POSTGRES_PASSWORD", "password"),
+		PostgreSQLDBName:     env.String("POSTGRES_DB", "payment_gateway"),
+		DBSecretARN:          env.String("DB_SECRET_ARN", ""),
+		APIKeyCookieName:     env.String("API_KEY_COOKIE_NAME", ""),
+		APIKeyQueryParam:     env.String("API_KEY_QUERY_PARAM", ""),
+		ClientCertHeader:     env.String("CLIENT_CERT_FINGERPRINT_HEADER", "X-Client-Cert-Fingerprint"),
+		RequireUserAgent:     env.Bool("REQUIRE_USER_AGENT", false),
+		DebugResponseHeaders: env.Bool("DEBUG_RESPONSE_HEADERS", false),
+		JSONFieldNaming:      env.String("JSON_FIELD_NAMING", http.JSONNamingSnakeCase),
+		// Webhook configuration
+		RequireHTTPSWebhooks:    env.Bool("REQUIRE_HTTPS_WEBHOOKS", false),
+		WebhookSigningSecretARN: env.String("WEBHOOK_SIGNING_SECRET_ARN", ""),
+		AccountNamePattern:      env.String("ACCOUNT_NAME_PATTERN", ""),
+		AllowedWebhookSchemes:   env.List("ALLOWED_WEBHOOK_SCHEMES", domain.DefaultWebhookSchemes),
+		AllowedWebhookPorts:     env.IntList("ALLOWED_WEBHOOK_PORTS", []int{80, 443}),
+		MaxWebhookURLLength:     env.Int("MAX_WEBHOOK_URL_LENGTH", 2048),
+		// Audit configuration
+		AuditEventTypes:             env.List("AUDIT_EVENT_TYPES", nil),
+		AuditSkipAuthSuccess:        env.Bool("AUDIT_SKIP_AUTH_SUCCESS", false),
+		AuditAuthSuccessSampleRate:  env.Float("AUDIT_AUTH_SUCCESS_SAMPLE_RATE", 1.0),
+		AuditSpilloverFile:          env.String("AUDIT_SPILLOVER_FILE", ""),
+		AuditSpilloverMaxBytes:      env.Int("AUDIT_SPILLOVER_MAX_BYTES", 100*1024*1024),
+		AuditSpilloverDrainInterval: env.Duration("AUDIT_SPILLOVER_DRAIN_INTERVAL", 30*time.Second),
+		AuditDetailLevel:            env.String("AUDIT_DETAIL_LEVEL", string(audit.DetailFull)),
+		AuditDetailRoutes:           env.Map("AUDIT_DETAIL_ROUTES"),
+		// Expiry warning job configuration
+		ExpiryWarningEnabled:            env.Bool("EXPIRY_WARNING_ENABLED", false),
+		ExpiryWarningInterval:           env.Duration("EXPIRY_WARNING_INTERVAL", time.Hour),
+		ExpiryWarningLeadTime:           env.Duration("EXPIRY_WARNING_LEAD_TIME", 7*24*time.Hour),
+		WebhookDisableAfterFailures:     env.Int("WEBHOOK_DISABLE_AFTER_FAILURES", 0),
+		ExpiredKeySweepEnabled:          env.Bool("EXPIRED_KEY_SWEEP_ENABLED", false),
+		ExpiredKeySweepInterval:         env.Duration("EXPIRED_KEY_SWEEP_INTERVAL", time.Hour),
+		AllowUnauthenticatedKeyIssuance: env.Bool("ALLOW_UNAUTHENTICATED_KEY_ISSUANCE", false),
+		// Default key permissions
+		DefaultKeyPermissionsEnabled:  env.Bool("DEFAULT_KEY_PERMISSIONS_ENABLED", false),
+		DefaultKeyPermissions:         env.List("DEFAULT_KEY_PERMISSIONS", []string{domain.PermissionReadAccounts}),
+		APIKeyPrefix:                  env.String("API_KEY_PREFIX", ""),
+		SuspendedAllowedPermissions:   env.List("SUSPENDED_ALLOWED_PERMISSIONS", nil),
+		APIKeyExpiryGracePeriod:       env.Duration("API_KEY_EXPIRY_GRACE_PERIOD", 0),
+		PermissionAliases:             env.Map("PERMISSION_ALIASES"),
+		ValidatePermissionScope:       env.List("VALIDATE_PERMISSION_SCOPE", nil),
+		ValidateAnonymizeExpired:      env.Bool("VALIDATE_ANONYMIZE_EXPIRED", false),
+		MaxActiveKeysPerAccount:       env.Int("MAX_ACTIVE_KEYS_PER_ACCOUNT", 0),
+		KeyNameUniqueness:             env.String("API_KEY_NAME_UNIQUENESS", string(domain.KeyNameScopeNone)),
+		ReconcileKeyCountersOnStartup: env.Bool("RECONCILE_KEY_COUNTERS_ON_STARTUP", false),
+		// Registration rate limiting
+		RegisterRateLimit:       env.Int("REGISTER_RATE_LIMIT", 5),
+		RegisterRateLimitWindow: env.Duration("REGISTER_RATE_LIMIT_WINDOW", time.Minute),
+		// Per-permission rate limiting
+		PermissionRateLimits:      env.IntMap("PERMISSION_RATE_LIMITS"),
+		PermissionRateLimitWindow: env.Duration("PERMISSION_RATE_LIMIT_WINDOW", time.Minute),
+		RateLimitStore:            env.String("RATE_LIMIT_STORE", "memory"),
+		// Registration challenge
+		RegistrationChallenge:     env.String("REGISTRATION_CHALLENGE", "none"),
+		RegistrationPoWDifficulty: env.Int("REGISTRATION_POW_DIFFICULTY", 20),
+		// Idempotency
+		IdempotencyHeaders:           env.List("IDEMPOTENCY_HEADER", []string{"Idempotency-Key"}),
+		IdempotencyMethods:           env.List("IDEMPOTENCY_METHODS", []string{"POST", "PUT", "PATCH"}),
+		IdempotencyMaxResponseBytes:  env.Int("IDEMPOTENCY_MAX_RESPONSE_BYTES", 350*1024),
+		MaxIdempotencyKeysPerAccount: env.Int("MAX_IDEMPOTENCY_KEYS_PER_ACCOUNT", 0),
+		// Maintenance mode
+		MaintenanceMode: env.Bool("MAINTENANCE_MODE", false),
+		// Pagination
+		LenientPagination: env.Bool("LENIENT_PAGINATION", false),
+		// Account stats
+		ShutdownDrainDelay:   env.Duration("SHUTDOWN_DRAIN_DELAY", 0),
+		SlowRequestThreshold: env.Duration("SLOW_REQUEST_THRESHOLD", 2*time.Second),
+		AccountStatsCacheTTL: env.Duration("ACCOUNT_STATS_CACHE_TTL", 30*time.Second),
+		// Feature flags
+		FeatureFlags: env.List("FEATURE_FLAGS", nil),
+	}
+	config.loadErrors = env.errs
+
+	return config
+}
+
+// applyDatabaseSecret overrides the PostgreSQL settings with credentials from
+// Secrets Manager. Fields missing from the secret keep their environment values.
+func (c *Config) applyDatabaseSecret(ctx context.Context, client *secrets.SecretsManagerClient) error {
+	creds, err := client.GetDatabaseCredentials(ctx, c.DBSecretARN)
+	if err != nil {
+		return err
+	}
+
+	c.PostgreSQLUser = creds.Username
+	c.PostgreSQLPassword = creds.Password
+	if creds.Host != "" {
+		c.PostgreSQLHost = creds.Host
+	}
+	if creds.Port != "" {
+		c.PostgreSQLPort = creds.Port.String()
+	}
+	if creds.DBName != "" {
+		c.PostgreSQLDBName = creds.DBName
+	}
+
+	return nil
+}
+
+// usesSecretsManager reports whether any setting has to be loaded from Secrets Manager
+func (c *Config) usesSecretsManager() bool {
+	return c.DBSecretARN != "" || c.WebhookSigningSecretARN != ""
+}
+
+// applySecrets loads every setting configured by a secret ARN
+func (c *Config) applySecrets(ctx context.Context, client *secrets.SecretsManagerClient) error {
+	if c.DBSecretARN != "" {
+		if err := c.applyDatabaseSecret(ctx, client); err != nil {
+			return fmt.Errorf("failed to load database credentials: %w", err)
+		}
+	}
+
+	if c.WebhookSigningSecretARN != "" {
+		secret, err := client.GetString(ctx, c.WebhookSigningSecretARN)
+		if err != nil {
+			return fmt.Errorf("failed to load webhook signing secret: %w", err)
+		}
+		c.WebhookSigningSecret = secret
+	}
+
+	return nil
+}
+
+// Validate checks required fields and value ranges, returning every problem at once
+func (c *Config) Validate() error {
+	errs := append([]error{}, c.loadErrors...)
+
+	// Required values
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"AWS_REGION", c.AWSRegion},
+		{"DYNAMODB_TABLE", c.DynamoDBTable},
+		{"AUDIT_LOGS_TABLE", c.AuditLogsTable},
+		{"POSTGRES_HOST", c.PostgreSQLHost},
+		{"POSTGRES_USER", c.PostgreSQLUser},
+		{"POSTGRES_DB", c.PostgreSQLDBName},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			errs = append(errs, fmt.Errorf("%s is required", r.key))
+		}
+	}
+
+	// Ports
+	if err := validatePort(c.Port); err != nil {
+		errs = append(errs, fmt.Errorf("PORT %w", err))
+	}
+	if err := validatePort(c.PostgreSQLPort); err != nil {
+		errs = append(errs, fmt.Errorf("POSTGRES_PORT %w", err))
+	}
+
+	// Debug headers expose internal IDs and must never reach production
+	if c.DebugResponseHeaders && c.Environment == "production" {
+		errs = append(errs, fmt.Errorf("DEBUG_RESPONSE_HEADERS must not be enabled when ENVIRONMENT is production"))
+	}
+
+	if c.JSONFieldNaming != http.JSONNamingSnakeCase && c.JSONFieldNaming != http.JSONNamingCamelCase {
+		errs = append(errs, fmt.Errorf("JSON_FIELD_NAMING must be %s or %s, got '%s'", http.JSONNamingSnakeCase, http.JSONNamingCamelCase, c.JSONFieldNaming))
+	}
+
+	// Prefixes only make keys recognizable, so they are kept short
+	if len(c.APIKeyPrefix) > domain.MaxKeyPrefixLength {
+		errs = append(errs, fmt.Errorf("API_KEY_PREFIX must be at most %d bytes, got '%s'", domain.MaxKeyPrefixLength, c.APIKeyPrefix))
+	}
+
+	// Account names
+	if c.AccountNamePattern != "" {
+		if _, err := regexp.Compile(c.AccountNamePattern); err != nil {
+			errs = append(errs, fmt.Errorf("ACCOUNT_NAME_PATTERN is not a valid regular expression: %w", err))
+		}
+	}
+
+	// Webhook schemes and ports
+	for _, scheme := range c.AllowedWebhookSchemes {
+		if !webhookSchemePattern.MatchString(scheme) {
+			errs = append(errs, fmt.Errorf("ALLOWED_WEBHOOK_SCHEMES contains invalid scheme '%s'", scheme))
+		}
+	}
+	if len(c.AllowedWebhookPorts) == 0 {
+		errs = append(errs, fmt.Errorf("ALLOWED_WEBHOOK_PORTS must not be empty"))
+	}
+	for _, port := range c.AllowedWebhookPorts {
+		if port < 1 || port > 65535 {
+			errs = append(errs, fmt.Errorf("ALLOWED_WEBHOOK_PORTS entries must be between 1 and 65535, got %d", port))
+		}
+	}
+	if c.MaxWebhookURLLength < 1 {
+		errs = append(errs, fmt.Errorf("MAX_WEBHOOK_URL_LENGTH must be positive, got %d", c.MaxWebhookURLLength))
+	}
+
+	// Audit
+	if c.AuditAuthSuccessSampleRate <= 0 || c.AuditAuthSuccessSampleRate > 1 {
+		errs = append(errs, fmt.Errorf("AUDIT_AUTH_SUCCESS_SAMPLE_RATE must be greater than 0 and at most 1 (use AUDIT_SKIP_AUTH_SUCCESS to drop all), got %v", c.AuditAuthSuccessSampleRate))
+	}
+	if c.AuditSpilloverFile != "" {
+		if c.AuditSpilloverMaxBytes < 0 {
+			errs = append(errs, fmt.Errorf("AUDIT_SPILLOVER_MAX_BYTES must not be negative, got %d", c.AuditSpilloverMaxBytes))
+		}
+		if c.AuditSpilloverDrainInterval <= 0 {
+			errs = append(errs, fmt.Errorf("AUDIT_SPILLOVER_DRAIN_INTERVAL must be positive, got %s", c.AuditSpilloverDrainInterval))
+		}
+	}
+	if !audit.DetailLevel(c.AuditDetailLevel).IsValid() {
+		errs = append(errs, fmt.Errorf("AUDIT_DETAIL_LEVEL must be 'none', 'summary' or 'full', got '%s'", c.AuditDetailLevel))
+	}
+	for route, level := range c.AuditDetailRoutes {
+		if !audit.DetailLevel(level).IsValid() {
+			errs = append(errs, fmt.Errorf("AUDIT_DETAIL_ROUTES level for '%s' must be 'none', 'summary' or 'full', got '%s'", route, level))
+		}
+	}
+
+	// Expiry warning job
+	if c.WebhookDisableAfterFailures < 0 {
+		errs = append(errs, fmt.Errorf("WEBHOOK_DISABLE_AFTER_FAILURES must not be negative, got %d", c.WebhookDisableAfterFailures))
+	}
+	if c.ExpiryWarningEnabled {
+		if c.ExpiryWarningInterval <= 0 {
+			errs = append(errs, fmt.Errorf("EXPIRY_WARNING_INTERVAL must be positive, got %s", c.ExpiryWarningInterval))
+		}
+		if c.ExpiryWarningLeadTime <= 0 {
+			errs = append(errs, fmt.Errorf("EXPIRY_WARNING_LEAD_TIME must be positive, got %s", c.ExpiryWarningLeadTime))
+		}
+	}
+
+	// Expired key sweep
+	if c.ExpiredKeySweepEnabled && c.ExpiredKeySweepInterval <= 0 {
+		errs = append(errs, fmt.Errorf("EXPIRED_KEY_SWEEP_INTERVAL must be positive, got %s", c.ExpiredKeySweepInterval))
+	}
+
+	// Default key permissions; admin permissions must always be granted explicitly
+	if c.DefaultKeyPermissionsEnabled {
+		if len(c.DefaultKeyPermissions) == 0 {
+			errs = append(errs, fmt.Errorf("DEFAULT_KEY_PERMISSIONS must not be empty when DEFAULT_KEY_PERMISSIONS_ENABLED is set"))
+		}
+		for _, perm := range c.DefaultKeyPermissions {
+			switch {
+			case !domain.IsValidPermission(perm):
+				errs = append(errs, fmt.Errorf("DEFAULT_KEY_PERMISSIONS contains unknown permission '%s'", perm))
+			case perm == domain.PermissionAdminKeys || perm == domain.PermissionAdminAccounts || perm == domain.PermissionReadAudit:
+				errs = append(errs, fmt.Errorf("DEFAULT_KEY_PERMISSIONS must not contain admin permission '%s'", perm))
+			}
+		}
+	}
+
+	// Aliases must point at real permissions and must not shadow one
+	for alias, canonical := range c.PermissionAliases {
+		if domain.IsValidPermission(alias) {
+			errs = append(errs, fmt.Errorf("PERMISSION_ALIASES must not redefine permission '%s'", alias))
+		}
+		if !domain.IsValidPermission(canonical) {
+			errs = append(errs, fmt.Errorf("PERMISSION_ALIASES maps '%s' to unknown permission '%s'", alias, canonical))
+		}
+	}
+
+	// Suspended accounts may only keep read access
+	for _, perm := range c.SuspendedAllowedPermissions {
+		switch {
+		case !domain.IsValidPermission(perm):
+			errs = append(errs, fmt.Errorf("SUSPENDED_ALLOWED_PERMISSIONS contains unknown permission '%s'", perm))
+		case !strings.HasPrefix(perm, "read:"):
+			errs = append(errs, fmt.Errorf("SUSPENDED_ALLOWED_PERMISSIONS must only contain read permissions, got '%s'", perm))
+		}
+	}
+
+	if c.MaxActiveKeysPerAccount < 0 {
+		errs = append(errs, fmt.Errorf("MAX_ACTIVE_KEYS_PER_ACCOUNT must not be negative, got %d", c.MaxActiveKeysPerAccount))
+	}
+
+	switch domain.KeyNameScope(c.KeyNameUniqueness) {
+	case domain.KeyNameScopeNone, domain.KeyNameScopeAccount, domain.KeyNameScopeGlobal:
+	default:
+		errs = append(errs, fmt.Errorf("API_KEY_NAME_UNIQUENESS must be 'none', 'account' or 'global', got '%s'", c.KeyNameUniqueness))
+	}
+
+	for _, perm := range c.ValidatePermissionScope {
+		if !domain.IsValidPermission(perm) {
+			errs = append(errs, fmt.Errorf("VALIDATE_PERMISSION_SCOPE contains unknown permission '%s'", perm))
+		}
+	}
+
+	// Expired keys are only kept in DynamoDB for the retention window
+	if c.APIKeyExpiryGracePeriod < 0 || c.APIKeyExpiryGracePeriod > repository.ExpiredKeyRetention {
+		errs = append(errs, fmt.Errorf("API_KEY_EXPIRY_GRACE_PERIOD must be between 0 and %s, got %s", repository.ExpiredKeyRetention, c.APIKeyExpiryGracePeriod))
+	}
+
+	// Registration rate limiting
+	if c.RegisterRateLimit < 0 {
+		errs = append(errs, fmt.Errorf("REGISTER_RATE_LIMIT must not be negative, got %d", c.RegisterRateLimit))
+	}
+	if c.RegisterRateLimit > 0 && c.RegisterRateLimitWindow <= 0 {
+		errs = append(errs, fmt.Errorf("REGISTER_RATE_LIMIT_WINDOW must be positive, got %s", c.RegisterRateLimitWindow))
+	}
+	for perm, limit := range c.PermissionRateLimits {
+		if !domain.IsValidPermission(perm) {
+			errs = append(errs, fmt.Errorf("PERMISSION_RATE_LIMITS contains unknown permission '%s'", perm))
+		}
+		if limit <= 0 {
+			errs = append(errs, fmt.Errorf("PERMISSION_RATE_LIMITS limit for '%s' must be positive, got %d", perm, limit))
+		}
+	}
+	if len(c.PermissionRateLimits) > 0 && c.PermissionRateLimitWindow <= 0 {
+		errs = append(errs, fmt.Errorf("PERMISSION_RATE_LIMIT_WINDOW must be positive, got %s", c.PermissionRateLimitWindow))
+	}
+	if c.RateLimitStore != "memory" && c.RateLimitStore != "dynamodb" {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_STORE must be 'memory' or 'dynamodb', got '%s'", c.RateLimitStore))
+	}
+
+	// Idempotency; DynamoDB items are limited to 400KB
+	for _, method := range c.IdempotencyMethods {
+		switch strings.ToUpper(method) {
+		case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			errs = append(errs, fmt.Errorf("IDEMPOTENCY_METHODS contains unsupported method '%s'", method))
+		}
+	}
+	if c.IdempotencyMaxResponseBytes < 1 || c.IdempotencyMaxResponseBytes > 400*1024 {
+		errs = append(errs, fmt.Errorf("IDEMPOTENCY_MAX_RESPONSE_BYTES must be between 1 and %d, got %d", 400*1024, c.IdempotencyMaxResponseBytes))
+	}
+	if c.MaxIdempotencyKeysPerAccount < 0 {
+		errs = append(errs, fmt.Errorf("MAX_IDEMPOTENCY_KEYS_PER_ACCOUNT must not be negative, got %d", c.MaxIdempotencyKeysPerAccount))
+	}
+
+	// Account stats
+	if c.ShutdownDrainDelay < 0 {
+		errs = append(errs, fmt.Errorf("SHUTDOWN_DRAIN_DELAY must not be negative, got %s", c.ShutdownDrainDelay))
+	}
+	if c.SlowRequestThreshold < 0 {
+		errs = append(errs, fmt.Errorf("SLOW_REQUEST_THRESHOLD must not be negative, got %s", c.SlowRequestThreshold))
+	}
+	if c.AccountStatsCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("ACCOUNT_STATS_CACHE_TTL must not be negative, got %s", c.AccountStatsCacheTTL))
+	}
+
+	// Feature flags
+	if _, err := featureflags.New(c.FeatureFlags...); err != nil {
+		errs = append(errs, fmt.Errorf("FEATURE_FLAGS %w (known: %s)", err, strings.Join(featureflags.Known(), ", ")))
+	}
+
+	// Registration challenge
+	switch c.RegistrationChallenge {
+	case "none":
+	case "pow":
+		if c.RegistrationPoWDifficulty < 1 || c.RegistrationPoWDifficulty > 32 {
+			errs = append(errs, fmt.Errorf("REGISTRATION_POW_DIFFICULTY must be between 1 and 32, got %d", c.RegistrationPoWDifficulty))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("REGISTRATION_CHALLENGE must be 'none' or 'pow', got '%s'", c.RegistrationChallenge))
+	}
+
+	return errors.Join(errs...)
+}
+
+// webhookSchemePattern matches a URL scheme as defined by RFC 3986
+var webhookSchemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*$`)
+
+// validatePort checks that a port is numeric and within range
+func validatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("must be a number, got '%s'", port)
+	}
+	if n < 1 || n > 65535 {
+		return fmt.Errorf("must be between 1 and 65535, got %d", n)
+	}
+	return nil
+}
+
+// envReader reads typed environment variables and records parse failures
+type envReader struct {
+	errs []error
+}
+
+// String gets an environment variable with default value
+func (e *envReader) String(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Bool gets a boolean environment variable with default value
+func (e *envReader) Bool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be a boolean, got '%s'", key, value))
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+// Int gets an integer environment variable with default value
+func (e *envReader) Int(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be an integer, got '%s'", key, value))
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+// Float gets a float environment variable with default value
+func (e *envReader) Float(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be a number, got '%s'", key, value))
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+// Duration gets a duration environment variable (e.g. "30s", "24h") with default value
+func (e *envReader) Duration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be a duration (e.g. 30s, 1h), got '%s'", key, value))
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+// List gets a comma-separated environment variable with default value
+func (e *envReader) List(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IntList gets a comma-separated list of integers with default value
+func (e *envReader) IntList(key string, defaultValue []int) []int {
+	items := e.List(key, nil)
+	if items == nil {
+		return defaultValue
+	}
+
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		parsed, err := strconv.Atoi(item)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s must be a comma-separated list of integers, got '%s'", key, item))
+			return defaultValue
+		}
+		values = append(values, parsed)
+	}
+	return values
+}
+
+// Map gets a comma-separated list of key=value pairs, e.g. "a=b,c=d"
+func (e *envReader) Map(key string) map[string]string {
+	items := e.List(key, nil)
+	if items == nil {
+		return nil
+	}
+
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			e.errs = append(e.errs, fmt.Errorf("%s entries must be key=value, got '%s'", key, item))
+			return nil
+		}
+		values[k] = v
+	}
+	return values
+}
+
+// IntMap gets a comma-separated list of key=integer pairs, e.g. "a=1,b=2"
+func (e *envReader) IntMap(key string) map[string]int {
+	items := e.Map(key)
+	if items == nil {
+		return nil
+	}
+
+	values := make(map[string]int, len(items))
+	for k, v := range items {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s values must be integers, got '%s=%s'", key, k, v))
+			return nil
+		}
+		values[k] = parsed
+	}
+	return values
+}
+
+// accountNamePolicy builds the name policy, anchoring the pattern so it must
+// match the whole name. Validate has already checked that it compiles.
+func (c *Config) accountNamePolicy() domain.AccountNamePolicy {
+	if c.AccountNamePattern == "" {
+		return domain.AccountNamePolicy{}
+	}
+	return domain.AccountNamePolicy{Pattern: regexp.MustCompile("^(?:" + c.AccountNamePattern + ")$")}
+}
+
+// auditDetail builds the per-route audit detail levels. Validate has already
+// checked that every level is known.
+func (c *Config) auditDetail() http.AuditDetailConfig {
+	routes := make(map[string]audit.DetailLevel, len(c.AuditDetailRoutes))
+	for route, level := range c.AuditDetailRoutes {
+		routes[route] = audit.DetailLevel(level)
+	}
+	return http.AuditDetailConfig{Default: audit.DetailLevel(c.AuditDetailLevel), Routes: routes}
+}
+
+// redactedValue replaces secrets in the effective configuration
+const redactedValue = "[redacted]"
+
+// redact hides a secret value while still showing whether it is set
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// Effective returns the configuration for GET /admin/config, keyed by the
+// lowercased environment variable name. Fields are listed explicitly so a new
+// setting is not exposed until it is added here; secrets are redacted.
+func (c *Config) Effective() map[string]interface{} {
+	return map[string]interface{}{
+		"environment":                        c.Environment,
+		"port":                               c.Port,
+		"aws_region":                         c.AWSRegion,
+		"dynamodb_table":                     c.DynamoDBTable,
+		"audit_logs_table":                   c.AuditLogsTable,
+		"dynamodb_endpoint":                  c.DynamoDBEndpoint,
+		"postgres_host":                      c.PostgreSQLHost,
+		"postgres_port":                      c.PostgreSQLPort,
+		"postgres_user":                      c.PostgreSQLUser,
+		"postgres_password":                  redact(c.PostgreSQLPassword),
+		"postgres_db":                        c.PostgreSQLDBName,
+		"db_secret_arn":                      c.DBSecretARN,
+		"api_key_cookie_name":                c.APIKeyCookieName,
+		"api_key_query_param":                c.APIKeyQueryParam,
+		"client_cert_fingerprint_header":     c.ClientCertHeader,
+		"require_user_agent":                 c.RequireUserAgent,
+		"debug_response_headers":             c.DebugResponseHeaders,
+		"json_field_naming":                  c.JSONFieldNaming,
+		"require_https_webhooks":             c.RequireHTTPSWebhooks,
+		"webhook_signing_secret_arn":         c.WebhookSigningSecretARN,
+		"account_name_pattern":               c.AccountNamePattern,
+		"allowed_webhook_schemes":            c.AllowedWebhookSchemes,
+		"allowed_webhook_ports":              c.AllowedWebhookPorts,
+		"max_webhook_url_length":             c.MaxWebhookURLLength,
+		"audit_event_types":                  c.AuditEventTypes,
+		"audit_skip_auth_success":            c.AuditSkipAuthSuccess,
+		"audit_auth_success_sample_rate":     c.AuditAuthSuccessSampleRate,
+		"audit_spillover_file":               c.AuditSpilloverFile,
+		"audit_spillover_max_bytes":          c.AuditSpilloverMaxBytes,
+		"audit_spillover_drain_interval":     c.AuditSpilloverDrainInterval.String(),
+		"audit_detail_level":                 c.AuditDetailLevel,
+		"audit_detail_routes":                c.AuditDetailRoutes,
+		"expiry_warning_enabled":             c.ExpiryWarningEnabled,
+		"expiry_warning_interval":            c.ExpiryWarningInterval.String(),
+		"expiry_warning_lead_time":           c.ExpiryWarningLeadTime.String(),
+		"webhook_disable_after_failures":     c.WebhookDisableAfterFailures,
+		"expired_key_sweep_enabled":          c.ExpiredKeySweepEnabled,
+		"expired_key_sweep_interval":         c.ExpiredKeySweepInterval.String(),
+		"allow_unauthenticated_key_issuance": c.AllowUnauthenticatedKeyIssuance,
+		"default_key_permissions_enabled":    c.DefaultKeyPermissionsEnabled,
+		"default_key_permissions":            c.DefaultKeyPermissions,
+		"api_key_prefix":                     c.APIKeyPrefix,
+		"suspended_allowed_permissions":      c.SuspendedAllowedPermissions,
+		"api_key_expiry_grace_period":        c.APIKeyExpiryGracePeriod.String(),
+		"permission_aliases":                 c.PermissionAliases,
+		"validate_permission_scope":          c.ValidatePermissionScope,
+		"validate_anonymize_expired":         c.ValidateAnonymizeExpired,
+		"max_active_keys_per_account":        c.MaxActiveKeysPerAccount,
+		"api_key_name_uniqueness":            c.KeyNameUniqueness,
+		"reconcile_key_counters_on_startup":  c.ReconcileKeyCountersOnStartup,
+		"register_rate_limit":                c.RegisterRateLimit,
+		"register_rate_limit_window":         c.RegisterRateLimitWindow.String(),
+		"permission_rate_limits":             c.PermissionRateLimits,
+		"permission_rate_limit_window":       c.PermissionRateLimitWindow.String(),
+		"rate_limit_store":                   c.RateLimitStore,
+		"registration_challenge":             c.RegistrationChallenge,
+		"registration_pow_difficulty":        c.RegistrationPoWDifficulty,
+		"idempotency_header":                 c.IdempotencyHeaders,
+		"idempotency_methods":                c.IdempotencyMethods,
+		"idempotency_max_response_bytes":     c.IdempotencyMaxResponseBytes,
+		"max_idempotency_keys_per_account":   c.MaxIdempotencyKeysPerAccount,
+		"maintenance_mode":                   c.MaintenanceMode,
+		"lenient_pagination":                 c.LenientPagination,
+		"shutdown_drain_delay":               c.ShutdownDrainDelay.String(),
+		"slow_request_threshold":             c.SlowRequestThreshold.String(),
+		"account_stats_cache_ttl":            c.AccountStatsCacheTTL.String(),
+		"feature_flags":                      c.FeatureFlags,
+	}
+}
@@ -5,33 +5,63 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/google/uuid"
 
 	"github.com/aws-payment-gateway/internal/auth/adapter/http"
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
 	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/jobs"
 	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/aws-payment-gateway/internal/auth/security"
 	"github.com/aws-payment-gateway/internal/auth/usecase"
+	"github.com/aws-payment-gateway/internal/auth/webhook"
 	"github.com/aws-payment-gateway/internal/common/db"
+	"github.com/aws-payment-gateway/internal/common/featureflags"
+	"github.com/aws-payment-gateway/internal/common/secrets"
+	"github.com/aws-payment-gateway/pkg/auth"
 )
 
 func main() {
-	// Load configuration
+	// Load and validate configuration before any client is initialized
 	config := loadConfig()
+	if config.usesSecretsManager() {
+		secretsClient, err := secrets.NewSecretsManagerClient(context.Background(), config.AWSRegion)
+		if err != nil {
+			log.Fatalf("Failed to initialize Secrets Manager: %v", err)
+		}
+		if err := config.applySecrets(context.Background(), secretsClient); err != nil {
+			log.Fatalf("Failed to load secrets: %v", err)
+		}
+	}
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	// One-off table setup instead of serving
+	if len(os.Args) > 1 && os.Args[1] == bootstrapTablesCommand {
+		if err := bootstrapTables(context.Background(), config); err != nil {
+			log.Fatalf("Failed to bootstrap tables: %v", err)
+		}
+		return
+	}
 
 	// Initialize DynamoDB client for API keys
-	dynamoClient, err := db.NewDynamoDBClient(context.Background(), config.AWSRegion, config.DynamoDBTable)
+	dynamoClient, err := db.NewDynamoDBClientWithEndpoint(context.Background(), config.AWSRegion, config.DynamoDBTable, config.DynamoDBEndpoint)
 	if err != nil {
 		log.Fatalf("Failed to initialize DynamoDB: %v", err)
 	}
 
 	// Initialize DynamoDB client for audit logs
-	auditDynamoClient, err := db.NewDynamoDBClient(context.Background(), config.AWSRegion, config.AuditLogsTable)
+	auditDynamoClient, err := db.NewDynamoDBClientWithEndpoint(context.Background(), config.AWSRegion, config.AuditLogsTable, config.DynamoDBEndpoint)
 	if err != nil {
 		log.Fatalf("Failed to initialize audit DynamoDB: %v", err)
 	}
@@ -52,35 +82,226 @@ func main() {
 	// Initialize repositories
 	appRepo := repository.NewPostgreSQLAppRepository(postgresClient)
 	apiKeyRepo := repository.NewDynamoDBApiKeyRepository(dynamoClient)
+	idempotencyRepo := repository.NewDynamoDBIdempotencyKeyRepository(dynamoClient)
 
 	// Initialize audit logger
-	auditLogger := audit.NewDynamoDBAuditLogger(auditDynamoClient)
+	var auditSpillover *audit.Spillover
+	if config.AuditSpilloverFile != "" {
+		auditSpillover, err = audit.NewSpillover(config.AuditSpilloverFile, int64(config.AuditSpilloverMaxBytes))
+		if err != nil {
+			log.Fatalf("Failed to open audit spillover: %v", err)
+		}
+		if pending := auditSpillover.Pending(); pending > 0 {
+			log.Printf("Audit spillover holds %d events from a previous run", pending)
+		}
+	}
+	auditLogger := audit.NewDynamoDBAuditLogger(auditDynamoClient, audit.AuditLoggerConfig{
+		EventTypes:                config.AuditEventTypes,
+		SkipAuthenticationSuccess: config.AuditSkipAuthSuccess,
+		AuthSuccessSampleRate:     config.AuditAuthSuccessSampleRate,
+		Spillover:                 auditSpillover,
+	})
+
+	// Feature flags were checked by Validate
+	flags, _ := featureflags.New(config.FeatureFlags...)
+	if names := flags.Names(); len(names) > 0 {
+		log.Printf("Feature flags enabled: %s", strings.Join(names, ", "))
+	}
 
 	// Initialize use cases
-	registerApp := usecase.NewRegisterApp(appRepo, apiKeyRepo)
-	issueApiKey := usecase.NewIssueApiKey(appRepo, apiKeyRepo)
-	validateApiKey := usecase.NewValidateApiKey(apiKeyRepo, appRepo)
+	webhookPolicy := domain.WebhookURLPolicy{
+		RequireHTTPS:   config.RequireHTTPSWebhooks,
+		AllowedSchemes: config.AllowedWebhookSchemes,
+		AllowedPorts:   config.AllowedWebhookPorts,
+		MaxLength:      config.MaxWebhookURLLength,
+	}
+	var registrationChallenge security.ChallengeVerifier = security.NoopChallengeVerifier{}
+	if config.RegistrationChallenge == "pow" {
+		registrationChallenge = security.NewProofOfWorkVerifier(config.RegistrationPoWDifficulty, 5*time.Minute)
+	}
+	registerApp := usecase.NewRegisterApp(appRepo, apiKeyRepo, usecase.RegisterAppConfig{
+		WebhookPolicy: webhookPolicy,
+		Challenge:     registrationChallenge,
+		NamePolicy:    config.accountNamePolicy(),
+		Flags:         flags,
+	})
+	var defaultKeyPermissions []string
+	if config.DefaultKeyPermissionsEnabled {
+		defaultKeyPermissions = config.DefaultKeyPermissions
+	}
+	keyGenerator := auth.RandomKeyGenerator{Prefix: config.APIKeyPrefix}
+	issueApiKey := usecase.NewIssueApiKey(appRepo, apiKeyRepo, usecase.IssueApiKeyConfig{
+		DefaultPermissions: defaultKeyPermissions,
+		KeyGenerator:       keyGenerator,
+		Flags:              flags,
+		MaxActiveKeys:      config.MaxActiveKeysPerAccount,
+		PermissionAliases:  config.PermissionAliases,
+		NameScope:          domain.KeyNameScope(config.KeyNameUniqueness),
+	})
+	validateApiKey := usecase.NewValidateApiKey(apiKeyRepo, appRepo, usecase.ValidateApiKeyConfig{
+		SuspendedAllowedPermissions: config.SuspendedAllowedPermissions,
+		ExpiryGracePeriod:           config.APIKeyExpiryGracePeriod,
+	})
+	validateApiKeyPair := usecase.NewValidateApiKeyPair(validateApiKey)
 	getAPIKeys := usecase.NewGetAPIKeys(appRepo, apiKeyRepo)
+	listRevokedApiKeys := usecase.NewListRevokedApiKeys(appRepo, apiKeyRepo)
+	listActiveApiKeys := usecase.NewListActiveApiKeys(appRepo, apiKeyRepo)
 	revokeApiKey := usecase.NewRevokeApiKey(apiKeyRepo)
+	revokeApiKeys := usecase.NewRevokeApiKeys(apiKeyRepo)
+	pauseApiKey := usecase.NewPauseApiKey(apiKeyRepo)
+	resumeApiKey := usecase.NewResumeApiKey(apiKeyRepo)
+	regenerateApiKey := usecase.NewRegenerateApiKey(apiKeyRepo, appRepo, keyGenerator)
+	checkAccess := usecase.NewCheckAccess(apiKeyRepo, appRepo, domain.AccessPolicy{
+		SuspendedAllowedPermissions: config.SuspendedAllowedPermissions,
+		ExpiryGracePeriod:           config.APIKeyExpiryGracePeriod,
+		PermissionAliases:           config.PermissionAliases,
+	})
+	resolvePermissions := usecase.NewResolvePermissions(config.PermissionAliases)
+	updateApiKeyPermissions := usecase.NewUpdateApiKeyPermissions(apiKeyRepo, appRepo, config.PermissionAliases)
+	exportAccount := usecase.NewExportAccount(appRepo, apiKeyRepo)
+	lookupApiKeyByHash := usecase.NewLookupApiKeyByHash(apiKeyRepo)
+	lookupApiKeyByRawKey := usecase.NewLookupApiKeyByRawKey(apiKeyRepo)
+	lookupApiKeyByExternalID := usecase.NewLookupApiKeyByExternalID(apiKeyRepo)
+	listAccounts := usecase.NewListAccounts(appRepo)
+	listAccessibleAccounts := usecase.NewListAccessibleAccounts(appRepo)
+	importAccounts := usecase.NewImportAccounts(appRepo, webhookPolicy, config.accountNamePolicy())
+	purgeAccountData := usecase.NewPurgeAccountData(appRepo, apiKeyRepo, idempotencyRepo, auditLogger)
+	getAccountStats := usecase.NewGetAccountStats(appRepo, apiKeyRepo, auditLogger, config.AccountStatsCacheTTL)
+	listAuditEvents := usecase.NewListAuditEvents(auditLogger)
+	countApiKeys := usecase.NewCountApiKeys(appRepo, apiKeyRepo)
+	updateAccount := usecase.NewUpdateAccount(appRepo, webhookPolicy)
+
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if config.ReconcileKeyCountersOnStartup {
+		reconcileKeyCounters := usecase.NewReconcileKeyCounters(appRepo, apiKeyRepo)
+		go func() {
+			output, err := reconcileKeyCounters.Execute(jobsCtx)
+			if err != nil {
+				log.Printf("Key counter reconciliation failed: %v", err)
+				return
+			}
+			log.Printf("Key counter reconciliation: %d reconciled, %d failed", output.Reconciled, output.Failed)
+		}()
+	}
+
+	if auditSpillover != nil {
+		go jobs.RunPeriodically(jobsCtx, "audit-spillover-drain", config.AuditSpilloverDrainInterval, func(ctx context.Context) error {
+			if auditSpillover.Pending() == 0 {
+				return nil
+			}
+			drained, err := auditLogger.DrainSpillover(ctx)
+			if drained > 0 {
+				log.Printf("Audit spillover: %d events written back, %d pending", drained, auditSpillover.Pending())
+			}
+			return err
+		})
+	}
+
+	if config.ExpiryWarningEnabled {
+		var notifier webhook.Notifier = webhook.NewHTTPNotifier(10 * time.Second).WithSigningSecret(config.WebhookSigningSecret)
+		if config.WebhookDisableAfterFailures > 0 {
+			disableFailingWebhook := usecase.NewDisableFailingWebhook(appRepo, auditLogger)
+			notifier = webhook.NewFailureLimitNotifier(notifier, config.WebhookDisableAfterFailures, func(ctx context.Context, accountID uuid.UUID, url string, failures int) error {
+				_, err := disableFailingWebhook.Execute(ctx, usecase.DisableFailingWebhookInput{
+					AccountID:  accountID,
+					WebhookURL: url,
+					Failures:   failures,
+				})
+				return err
+			})
+		}
+		notifyExpiringKeys := usecase.NewNotifyExpiringKeys(apiKeyRepo, appRepo, notifier, config.ExpiryWarningLeadTime)
+		go jobs.RunPeriodically(jobsCtx, "expiry-warning", config.ExpiryWarningInterval, func(ctx context.Context) error {
+			output, err := notifyExpiringKeys.Execute(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("Expiry warnings: %d notified, %d skipped, %d failed", output.Notified, output.Skipped, output.Failed)
+			return nil
+		})
+	}
+
+	if config.ExpiredKeySweepEnabled {
+		deactivateExpiredKeys := usecase.NewDeactivateExpiredKeys(apiKeyRepo, config.APIKeyExpiryGracePeriod)
+		go jobs.RunPeriodically(jobsCtx, "expired-key-sweep", config.ExpiredKeySweepInterval, func(ctx context.Context) error {
+			output, err := deactivateExpiredKeys.Execute(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("Expired key sweep: %d deactivated, %d failed", output.Deactivated, output.Failed)
+			return nil
+		})
+	}
 
 	// Initialize handlers
-	authHandler := http.NewAuthHandler(registerApp, issueApiKey, validateApiKey, getAPIKeys, revokeApiKey, auditLogger)
-	authMiddleware := http.NewAuthMiddleware(validateApiKey, apiKeyRepo, auditLogger)
+	paginationConfig := http.PaginationConfig{Lenient: config.LenientPagination}
+	validateResponseConfig := http.ValidateResponseConfig{
+		PermissionScope:  config.ValidatePermissionScope,
+		AnonymizeExpired: config.ValidateAnonymizeExpired,
+	}
+	auditDetail := config.auditDetail()
+	authHandler := http.NewAuthHandler(registerApp, issueApiKey, validateApiKey, validateApiKeyPair, getAPIKeys, listRevokedApiKeys, listActiveApiKeys, revokeApiKey, revokeApiKeys, exportAccount, auditLogger, auditDetail, validateResponseConfig, paginationConfig)
+	accountHandler := http.NewAccountHandler(updateAccount, listAccounts, listAccessibleAccounts, getAccountStats, auditLogger, auditDetail, paginationConfig)
+	adminHandler := http.NewAdminHandler(lookupApiKeyByHash, lookupApiKeyByRawKey, importAccounts, purgeAccountData, auditLogger, auditDetail)
+	auditHandler := http.NewAuditHandler(listAuditEvents)
+	apiKeyHandler := http.NewApiKeyHandler(pauseApiKey, resumeApiKey, regenerateApiKey, checkAccess, lookupApiKeyByExternalID, countApiKeys, resolvePermissions, updateApiKeyPermissions, auditLogger, auditDetail)
+	var rateLimitRepo repository.RateLimitRepository = repository.NewInMemoryRateLimitRepository()
+	if config.RateLimitStore == "dynamodb" {
+		rateLimitRepo = repository.NewDynamoDBRateLimitRepository(dynamoClient)
+	}
+	rateLimiter := http.NewRateLimitMiddleware(rateLimitRepo)
+	rateLimiter.AddConfig("register", &http.RateLimitConfig{
+		Requests:     config.RegisterRateLimit,
+		Window:       config.RegisterRateLimitWindow,
+		KeyGenerator: http.KeyByIP,
+	})
+	for permission, limit := range config.PermissionRateLimits {
+		rateLimiter.AddPermissionConfig(permission, &http.RateLimitConfig{
+			Requests:     limit,
+			Window:       config.PermissionRateLimitWindow,
+			KeyGenerator: http.KeyByAPIKeyID,
+		})
+	}
+	maintenance := http.NewMaintenanceMiddleware(http.MaintenanceMiddlewareConfig{
+		Enabled: config.MaintenanceMode,
+		// Key validation is read-only and must keep working for downstream services
+		AllowPaths: []string{"/api/v1/auth/validate", "/api/v1/auth/validate-pair"},
+	})
+	authMiddleware := http.NewAuthMiddleware(validateApiKey, apiKeyRepo, auditLogger, http.AuthMiddlewareConfig{
+		CookieName: config.APIKeyCookieName,
+		QueryParam: config.APIKeyQueryParam,
+		// Limits are looked up when routes are registered, after the configs above
+		PermissionRateLimiter: rateLimiter.ForPermission,
+		ClientCertHeader:      config.ClientCertHeader,
+	})
+	idempotency := http.NewIdempotencyMiddleware(
+		usecase.NewCheckIdempotency(idempotencyRepo),
+		usecase.NewCreateIdempotency(idempotencyRepo, usecase.CreateIdempotencyConfig{
+			MaxKeysPerAccount: config.MaxIdempotencyKeysPerAccount,
+		}),
+		usecase.NewCompleteIdempotency(idempotencyRepo, usecase.CompleteIdempotencyConfig{
+			MaxResponseBytes: config.IdempotencyMaxResponseBytes,
+		}),
+		http.IdempotencyMiddlewareConfig{
+			HeaderNames: config.IdempotencyHeaders,
+			Methods:     config.IdempotencyMethods,
+		},
+	)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
+		JSONEncoder: http.JSONEncoder(config.JSONFieldNaming),
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
 				code = e.Code
 			}
 
-			return c.Status(code).JSON(struct {
-				Error   string `json:"error"`
-				Message string `json:"message"`
-				Details string `json:"details,omitempty"`
-			}{
-				Error:   "internal_error",
+			return http.RespondErrorWithStatus(c, code, dto.ErrorResponse{
+				Error:   domain.ErrCodeInternalError,
 				Message: "An internal error occurred",
 				Details: err.Error(),
 			})
@@ -88,33 +309,114 @@ func main() {
 	})
 
 	// Add middleware
-	app.Use(recover.New())
-	app.Use(logger.New())
+	app.Use(requestid.New())
+	app.Use(http.NewRecoverMiddleware(auditLogger).Handler())
+	loggerConfig := logger.Config{}
+	if config.APIKeyQueryParam != "" {
+		log.Printf("WARNING: API keys are accepted in the '%s' query parameter; URLs may be logged by proxies and clients", config.APIKeyQueryParam)
+		loggerConfig.CustomTags = http.RedactedLogTags(config.APIKeyQueryParam)
+	}
+	app.Use(logger.New(loggerConfig))
+	if config.SlowRequestThreshold > 0 {
+		app.Use(http.SlowRequestLogger(config.SlowRequestThreshold))
+	}
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization,x-api-key",
+		AllowOrigins:  "*",
+		AllowMethods:  "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:  "Origin,Content-Type,Accept,Authorization,x-api-key,If-Match",
+		ExposeHeaders: "ETag,Location,X-Request-ID",
 	}))
 
-	// Health check endpoint
+	app.Use(maintenance.Handler())
+
+	if config.RequireUserAgent {
+		app.Use(http.RequireUserAgent("/api/v1/auth/register", "/api/v1/auth/validate", "/api/v1/auth/validate-pair"))
+	}
+
+	// Toggle maintenance mode without a restart: kill -USR1 <pid>
+	maintenanceSignal := make(chan os.Signal, 1)
+	signal.Notify(maintenanceSignal, syscall.SIGUSR1)
+	go func() {
+		for range maintenanceSignal {
+			log.Printf("Maintenance mode set to %t", maintenance.Toggle())
+		}
+	}()
+
+	// Health check endpoints; readiness turns 503 while draining for shutdown
+	readiness := http.NewAtomicReadinessGate()
 	app.Get("/health", authHandler.HealthCheck)
+	app.Get("/health/ready", http.ReadinessCheck(readiness, auditLogger))
 
 	// API routes
 	api := app.Group("/api/v1")
 	auth := api.Group("/auth")
 
-	// Public routes
-	auth.Post("/register", authHandler.RegisterApp)
-	auth.Post("/api-keys", authHandler.IssueApiKey)
+	// Public routes. Routes named after a request schema get an example body in
+	// the Postman collection.
+	// Registration is public; an API key, if sent, registers a sub-account in the caller's tenant.
+	// Idempotency runs after OptionalAuth so keys are scoped to the caller's account.
+	if config.RegisterRateLimit > 0 {
+		auth.Post("/register", rateLimiter.ForConfig("register"), authMiddleware.OptionalAuth(), idempotency.Handle(), authHandler.RegisterApp).Name("register-app")
+	} else {
+		auth.Post("/register", authMiddleware.OptionalAuth(), idempotency.Handle(), authHandler.RegisterApp).Name("register-app")
+	}
 	auth.Post("/validate", authHandler.ValidateApiKey)
+	auth.Post("/validate-pair", authHandler.ValidateApiKeyPair)
+	auth.Get("/schemas/:name", http.RequestSchema)
+	auth.Get("/postman", http.PostmanCollection)
+	if config.AllowUnauthenticatedKeyIssuance {
+		// Legacy migration path: anyone who knows an account ID can issue keys for it
+		log.Println("WARNING: ALLOW_UNAUTHENTICATED_KEY_ISSUANCE is enabled; API key issuance is not authenticated")
+		auth.Post("/api-keys", authHandler.IssueApiKey).Name("issue-api-key")
+	}
 
-	// Protected routes
-	protected := auth.Group("/")
-	protected.Use(authMiddleware.RequireAuth())
+	// Protected routes. Authentication is attached to each route rather than
+	// used on a group, so paths that match no route still reach NotFound.
+	authChain := []fiber.Handler{authMiddleware.RequireAuth()}
+	if config.DebugResponseHeaders {
+		log.Printf("Debug response headers enabled (%s, %s)", http.HeaderDebugAccountID, http.HeaderDebugAPIKeyID)
+		authChain = append(authChain, http.DebugHeaders())
+	}
+	protected := func(handlers ...fiber.Handler) []fiber.Handler {
+		return append(append([]fiber.Handler{}, authChain...), handlers...)
+	}
 
-	// Account-specific routes (require authentication)
-	protected.Get("/accounts/:account_id/api-keys", authMiddleware.RequirePermission("read:keys"), authHandler.GetAPIKeys)
-	protected.Delete("/api-keys/:api_key_id", authMiddleware.RequirePermission("write:keys"), authHandler.RevokeApiKey)
+	// Account-specific routes (require authentication). Keys scoped to certain
+	// accounts only reach those through routes naming an account.
+	accountScope := authMiddleware.RequireResourceScope("account_id")
+	auth.Get("/can", protected(apiKeyHandler.CheckAccess)...)
+	auth.Post("/permissions/resolve", protected(apiKeyHandler.ResolvePermissions)...)
+	auth.Get("/me/accounts", protected(accountHandler.ListMyAccounts)...)
+	auth.Post("/me/revoke", protected(authHandler.RevokeOwnApiKey)...)
+	auth.Post("/api-keys", protected(authMiddleware.RequirePermission("write:keys"), authHandler.IssueApiKey)...).Name("issue-api-key")
+	auth.Get("/accounts/:account_id/api-keys", protected(authMiddleware.RequirePermission("read:keys"), accountScope, authHandler.GetAPIKeys)...)
+	auth.Get("/accounts/:account_id/api-keys/revoked", protected(authMiddleware.RequirePermission("read:keys"), accountScope, authHandler.ListRevokedAPIKeys)...)
+	auth.Get("/accounts/:account_id/active-keys", protected(authMiddleware.RequirePermission("read:keys"), accountScope, authHandler.ListActiveAPIKeys)...)
+	auth.Get("/accounts/:account_id/api-keys/count", protected(authMiddleware.RequirePermission("read:keys"), accountScope, apiKeyHandler.CountApiKeys)...)
+	auth.Get("/accounts/:account_id/export", protected(authMiddleware.RequirePermission("read:keys"), authMiddleware.RequirePermission("read:accounts"), accountScope, authHandler.ExportAccount)...)
+	auth.Post("/accounts/import", protected(authMiddleware.RequirePermission("admin:accounts"), adminHandler.ImportAccounts)...)
+	auth.Post("/accounts/:account_id/purge", protected(authMiddleware.RequirePermission("admin:accounts"), accountScope, adminHandler.PurgeAccountData)...)
+	auth.Get("/accounts/:account_id/stats", protected(authMiddleware.RequirePermission("read:accounts"), accountScope, accountHandler.GetAccountStats)...)
+	auth.Get("/accounts", protected(authMiddleware.RequirePermission("read:accounts"), accountHandler.ListAccounts)...)
+	auth.Patch("/accounts/:account_id", protected(authMiddleware.RequirePermission("manage:webhooks"), accountScope, accountHandler.UpdateAccount)...)
+	auth.Delete("/accounts/:account_id/webhook", protected(authMiddleware.RequireAnyPermission("admin:accounts", "manage:webhooks"), accountScope, accountHandler.ResetWebhook)...)
+	auth.Put("/accounts/:account_id/allowed-permissions", protected(authMiddleware.RequirePermission("admin:accounts"), accountScope, accountHandler.SetAllowedPermissions)...)
+	auth.Post("/accounts/:account_id/keys/disable", protected(authMiddleware.RequireAnyPermission("admin:accounts", "write:keys"), accountScope, accountHandler.DisableKeys)...)
+	auth.Post("/accounts/:account_id/keys/enable", protected(authMiddleware.RequirePermission("admin:accounts"), accountScope, accountHandler.EnableKeys)...)
+	auth.Get("/audit-events", protected(authMiddleware.RequireAnyPermission("read:audit", "read:own-audit"), auditHandler.ListAuditEvents)...)
+	auth.Get("/api-keys/by-external-id/:external_id", protected(authMiddleware.RequirePermission("read:keys"), apiKeyHandler.GetApiKeyByExternalID)...)
+	auth.Get("/api-keys/by-hash/:hash", protected(authMiddleware.RequirePermission("admin:keys"), adminHandler.GetAPIKeyByHash)...)
+	auth.Get("/admin/config", protected(authMiddleware.RequirePermission("admin:accounts"), http.EffectiveConfig(config.Effective(), time.Now().UTC()))...)
+	auth.Post("/api-keys/revoke-batch", protected(authMiddleware.RequirePermission("write:keys"), authHandler.RevokeAPIKeysBatch)...)
+	auth.Post("/api-keys/lookup", protected(authMiddleware.RequirePermission("admin:keys"), adminHandler.LookupAPIKey)...)
+	auth.Post("/api-keys/:api_key_id/pause", protected(authMiddleware.RequirePermission("write:keys"), apiKeyHandler.PauseApiKey)...)
+	auth.Post("/api-keys/:api_key_id/resume", protected(authMiddleware.RequirePermission("write:keys"), apiKeyHandler.ResumeApiKey)...)
+	auth.Post("/api-keys/:api_key_id/regenerate", protected(authMiddleware.RequirePermission("write:keys"), apiKeyHandler.RegenerateApiKey)...)
+	auth.Put("/api-keys/:api_key_id/permissions", protected(authMiddleware.RequirePermission("write:keys"), apiKeyHandler.UpdateApiKeyPermissions)...)
+	auth.Delete("/api-keys/:api_key_id", protected(authMiddleware.RequirePermission("write:keys"), authHandler.RevokeApiKey)...)
+
+	// Anything that matched no route; must stay last
+	app.Use(http.NotFound)
 
 	// Start server
 	go func() {
@@ -128,7 +430,15 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// Stop advertising readiness first and give load balancers time to notice
+	if config.ShutdownDrainDelay > 0 {
+		readiness.SetReady(false)
+		log.Printf("Draining for %s before shutdown...", config.ShutdownDrainDelay)
+		time.Sleep(config.ShutdownDrainDelay)
+	}
+
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -139,43 +449,3 @@ func main() {
 
 	log.Println("Server exited")
 }
-
-// Config represents the application configuration
-type Config struct {
-	Port           string
-	AWSRegion      string
-	DynamoDBTable  string
-	AuditLogsTable string
-	// PostgreSQL configuration
-	PostgreSQLHost     string
-	PostgreSQLPort     string
-	PostgreSQLUser     string
-	PostgreSQLPassword string
-	PostgreSQLDBName   string
-}
-
-// loadConfig loads configuration from environment variables
-func loadConfig() *Config {
-	config := &Config{
-		Port:           getEnv("PORT", "8080"),
-		AWSRegion:      getEnv("AWS_REGION", "us-west-2"),
-		DynamoDBTable:  getEnv("DYNAMODB_TABLE", "auth-service"),
-		AuditLogsTable: getEnv("AUDIT_LOGS_TABLE", "audit_logs"),
-		// PostgreSQL configuration
-		PostgreSQLHost:     getEnv("POSTGRES_HOST", "localhost"),
-		PostgreSQLPort:     getEnv("POSTGRES_PORT", "5432"),
-		PostgreSQLUser:     getEnv("POSTGRES_USER", "postgres"),
-		PostgreSQLPassword: getEnv("POSTGRES_PASSWORD", "password"),
-		PostgreSQLDBName:   getEnv("POSTGRES_DB", "payment_gateway"),
-	}
-
-	return config
-}
-
-// getEnv gets environment variable with default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
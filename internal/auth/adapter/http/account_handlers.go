@@ -0,0 +1,604 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AccountHandler handles HTTP requests for account management
+type AccountHandler struct {
+	updateAccount   *usecase.UpdateAccount
+	listAccounts    *usecase.ListAccounts
+	listAccessible  *usecase.ListAccessibleAccounts
+	getAccountStats *usecase.GetAccountStats
+	auditLogger     audit.AuditLoggerInterface
+	auditDetail     AuditDetailConfig
+	pagination      PaginationConfig
+}
+
+// NewAccountHandler creates a new AccountHandler
+func NewAccountHandler(updateAccount *usecase.UpdateAccount, listAccounts *usecase.ListAccounts, listAccessible *usecase.ListAccessibleAccounts, getAccountStats *usecase.GetAccountStats, auditLogger audit.AuditLoggerInterface, auditDetail AuditDetailConfig, pagination PaginationConfig) *AccountHandler {
+	return &AccountHandler{
+		updateAccount:   updateAccount,
+		listAccounts:    listAccounts,
+		listAccessible:  listAccessible,
+		getAccountStats: getAccountStats,
+		auditLogger:     auditLogger,
+		auditDetail:     auditDetail,
+		pagination:      pagination,
+	}
+}
+
+// toAccountResponse converts a domain account to its response format
+func toAccountResponse(account *domain.Account) dto.AccountResponse {
+	return dto.AccountResponse{
+		AccountID:              account.ID,
+		OwnerID:                account.OwnerID,
+		Name:                   account.Name,
+		Status:                 string(account.Status),
+		WebhookURL:             account.WebhookURL,
+		CreatedAt:              account.CreatedAt,
+		UpdatedAt:              account.UpdatedAt,
+		Version:                account.Version,
+		KeyPrefix:              account.KeyPrefix,
+		KeysDisabled:           account.KeysDisabled,
+		RequireClientCert:      account.RequireClientCert,
+		ClientCertFingerprints: account.ClientCertFingerprints,
+		AllowedPermissions:     account.AllowedPermissions,
+	}
+}
+
+// UpdateAccount handles account updates
+// @Summary Update an account
+// @Description Update account settings. Send the ETag from a previous response in If-Match to avoid overwriting concurrent changes.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param request body dto.UpdateAccountRequest true "Account update request"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id} [patch]
+func (h *AccountHandler) UpdateAccount(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Callers may only update their own account
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: "Cannot update another account",
+		})
+	}
+
+	expectedVersion, errResp := ifMatchVersion(c)
+	if errResp != nil {
+		return RespondErrorWith(c, *errResp)
+	}
+
+	var req dto.UpdateAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	// The route only requires manage:webhooks; changing how keys look needs more
+	if req.KeyPrefix != nil && !HasPermission(c, domain.PermissionWriteAccounts) {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: fmt.Sprintf("Permission '%s' is required to change key_prefix", domain.PermissionWriteAccounts),
+		})
+	}
+
+	// Client certificate settings decide who can use the account's keys at all
+	if (req.RequireClientCert != nil || req.ClientCertFingerprints != nil) && !HasPermission(c, domain.PermissionWriteAccounts) {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: fmt.Sprintf("Permission '%s' is required to change client certificate settings", domain.PermissionWriteAccounts),
+		})
+	}
+
+	// Execute use case
+	output, err := h.updateAccount.Execute(ctx, usecase.UpdateAccountInput{
+		AccountID:              accountID,
+		WebhookURL:             req.WebhookURL,
+		KeyPrefix:              req.KeyPrefix,
+		RequireClientCert:      req.RequireClientCert,
+		ClientCertFingerprints: req.ClientCertFingerprints,
+		ExpectedVersion:        expectedVersion,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVersionConflict):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   versionConflictCode(expectedVersion),
+				Message: "The account was modified by another request; fetch it again and retry",
+			})
+		case errors.Is(err, domain.ErrInvalidWebhookURL):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeValidationError,
+				Message: "Invalid webhook URL",
+				Details: err.Error(),
+			})
+		case errors.Is(err, domain.ErrInvalidKeyPrefix):
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("key_prefix", err.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		case errors.Is(err, domain.ErrInvalidCertFingerprint), errors.Is(err, domain.ErrClientCertAllowlistEmpty):
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("client_cert_fingerprints", err.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		case err.Error() == "account not found or inactive":
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to update account",
+			Details: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(output.Account.Version))
+	return c.Status(fiber.StatusOK).JSON(toAccountResponse(output.Account))
+}
+
+// ResetWebhook handles clearing an account's webhook URL
+// @Summary Reset an account's webhook
+// @Description Clear the webhook URL, e.g. when deliveries keep failing. Callers with admin:accounts may reset any account; manage:webhooks only allows the caller's own.
+// @Tags accounts
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/webhook [delete]
+func (h *AccountHandler) ResetWebhook(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID && !HasPermission(c, domain.PermissionAdminAccounts) {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: fmt.Sprintf("Permission '%s' is required to reset another account's webhook", domain.PermissionAdminAccounts),
+		})
+	}
+
+	// Execute use case; an empty URL clears the webhook
+	cleared := ""
+	output, err := h.updateAccount.Execute(ctx, usecase.UpdateAccountInput{
+		AccountID:  accountID,
+		WebhookURL: &cleared,
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to reset webhook",
+			Details: err.Error(),
+		})
+	}
+
+	// Log the reset, keeping the old URL so it can be restored
+	details := map[string]string{"reset_by_account_id": callerAccountID.String()}
+	if output.PreviousWebhookURL != nil {
+		details["previous_webhook_url"] = *output.PreviousWebhookURL
+	}
+	event := &audit.AuditEvent{
+		EventType: audit.EventTypeWebhookReset,
+		AccountID: &accountID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   true,
+		Details:   details,
+	}
+	if apiKeyID, err := GetAPIKeyID(c); err == nil {
+		event.APIKeyID = &apiKeyID
+	}
+	event.Details = h.auditDetail.details(c, event.Details)
+	h.auditLogger.LogEvent(ctx, event)
+
+	c.Set(fiber.HeaderETag, formatETag(output.Account.Version))
+	return c.Status(fiber.StatusOK).JSON(toAccountResponse(output.Account))
+}
+
+// SetAllowedPermissions handles replacing the cap on an account's key permissions
+// @Summary Set an account's allowed permissions
+// @Description Limit the permissions keys of the account can be issued with, e.g. so a free account cannot mint admin keys. Wildcards are expanded; an empty list removes the cap. Existing keys are not changed.
+// @Tags accounts
+// @Accept json
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Param request body dto.SetAllowedPermissionsRequest true "Allowed permissions"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/allowed-permissions [put]
+func (h *AccountHandler) SetAllowedPermissions(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	var req dto.SetAllowedPermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	// Execute use case
+	output, err := h.updateAccount.Execute(ctx, usecase.UpdateAccountInput{
+		AccountID:          accountID,
+		AllowedPermissions: &req.Permissions,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPermission) {
+			return RespondErrorWith(c, invalidPermissionsResponse(err, req.Permissions))
+		}
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to set allowed permissions",
+			Details: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(output.Account.Version))
+	return c.Status(fiber.StatusOK).JSON(toAccountResponse(output.Account))
+}
+
+// DisableKeys handles switching off every API key of an account at once
+// @Summary Disable all of an account's API keys
+// @Description Reject every key of the account without revoking them, e.g. during a suspected compromise. Callers with admin:accounts may disable any account; write:keys only allows the caller's own. Re-enabling requires admin:accounts, since none of the account's keys work afterwards.
+// @Tags accounts
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/keys/disable [post]
+func (h *AccountHandler) DisableKeys(c *fiber.Ctx) error {
+	return h.setKeysDisabled(c, true)
+}
+
+// EnableKeys handles switching an account's API keys back on
+// @Summary Re-enable an account's API keys
+// @Description Clear the account-wide key switch. Keys return to their own status: revoked, paused or expired keys stay unusable.
+// @Tags accounts
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Success 200 {object} dto.AccountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/keys/enable [post]
+func (h *AccountHandler) EnableKeys(c *fiber.Ctx) error {
+	return h.setKeysDisabled(c, false)
+}
+
+// setKeysDisabled turns the account-wide key switch on or off and audits the change
+func (h *AccountHandler) setKeysDisabled(c *fiber.Ctx, disabled bool) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID && !HasPermission(c, domain.PermissionAdminAccounts) {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: fmt.Sprintf("Permission '%s' is required to change another account's keys", domain.PermissionAdminAccounts),
+		})
+	}
+
+	output, err := h.updateAccount.Execute(ctx, usecase.UpdateAccountInput{
+		AccountID:    accountID,
+		KeysDisabled: &disabled,
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to update account keys",
+			Details: err.Error(),
+		})
+	}
+
+	eventType := audit.EventTypeKeysEnabled
+	if disabled {
+		eventType = audit.EventTypeKeysDisabled
+	}
+	event := &audit.AuditEvent{
+		EventType: eventType,
+		AccountID: &accountID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   true,
+		Details:   map[string]string{"changed_by_account_id": callerAccountID.String()},
+	}
+	if apiKeyID, err := GetAPIKeyID(c); err == nil {
+		event.APIKeyID = &apiKeyID
+	}
+	event.Details = h.auditDetail.details(c, event.Details)
+	h.auditLogger.LogEvent(ctx, event)
+
+	c.Set(fiber.HeaderETag, formatETag(output.Account.Version))
+	return c.Status(fiber.StatusOK).JSON(toAccountResponse(output.Account))
+}
+
+// ListAccounts handles listing the accounts in the caller's tenant
+// @Summary List accounts
+// @Description List accounts owned by the caller's tenant. Callers with admin:accounts see every account. Keys with resource scopes only see the accounts in their scopes.
+// @Tags accounts
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} dto.ListAccountsResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts [get]
+func (h *AccountHandler) ListAccounts(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// Parse pagination parameters
+	limit, offset, err := parsePagination(c, h.pagination)
+	if err != nil {
+		return RespondErrorWith(c, invalidPaginationResponse(err))
+	}
+
+	// Execute use case
+	output, err := h.listAccounts.Execute(ctx, usecase.ListAccountsInput{
+		CallerAccountID: callerAccountID,
+		AllTenants:      HasPermission(c, domain.PermissionAdminAccounts),
+		ResourceScopes:  GetResourceScopes(c),
+		Limit:           limit,
+		Offset:          offset,
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to list accounts",
+			Details: err.Error(),
+		})
+	}
+
+	accounts := make([]dto.AccountResponse, len(output.Accounts))
+	for i, account := range output.Accounts {
+		accounts[i] = toAccountResponse(account)
+	}
+
+	response := dto.ListAccountsResponse{
+		Accounts: accounts,
+		Limit:    limit,
+		Offset:   offset,
+		Total:    len(accounts),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ListMyAccounts handles listing the accounts the authenticated key can access
+// @Summary List accessible accounts
+// @Description List the accounts the calling key can act on: every account for keys with admin:accounts, otherwise only the key's own account.
+// @Tags accounts
+// @Produce json
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} dto.AccessibleAccountsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/me/accounts [get]
+func (h *AccountHandler) ListMyAccounts(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// Parse pagination parameters
+	limit, offset, err := parsePagination(c, h.pagination)
+	if err != nil {
+		return RespondErrorWith(c, invalidPaginationResponse(err))
+	}
+
+	// Execute use case
+	output, err := h.listAccessible.Execute(ctx, usecase.ListAccessibleAccountsInput{
+		CallerAccountID: callerAccountID,
+		CrossAccount:    HasPermission(c, domain.PermissionAdminAccounts),
+		Limit:           limit,
+		Offset:          offset,
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to list accessible accounts",
+			Details: err.Error(),
+		})
+	}
+
+	accounts := make([]dto.AccountResponse, len(output.Accounts))
+	for i, account := range output.Accounts {
+		accounts[i] = toAccountResponse(account)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.AccessibleAccountsResponse{
+		Scope: output.Scope,
+		ListAccountsResponse: dto.ListAccountsResponse{
+			Accounts: accounts,
+			Limit:    limit,
+			Offset:   offset,
+			Total:    len(accounts),
+		},
+	})
+}
+
+// GetAccountStats handles getting aggregate usage stats for an account
+// @Summary Get account usage stats
+// @Description Get key counts, last authentication time and successful authentications in the last 24 hours. Results are cached briefly.
+// @Tags accounts
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Success 200 {object} dto.AccountStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/stats [get]
+func (h *AccountHandler) GetAccountStats(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Callers may only view their own account's stats
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: "Cannot view another account's stats",
+		})
+	}
+
+	// Execute use case
+	output, err := h.getAccountStats.Execute(ctx, usecase.GetAccountStatsInput{AccountID: accountID})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account stats",
+			Details: err.Error(),
+		})
+	}
+
+	response := dto.AccountStatsResponse{
+		AccountID:           accountID,
+		TotalKeys:           output.TotalKeys,
+		ActiveKeys:          output.ActiveKeys,
+		LastAuthenticatedAt: output.LastAuthenticated,
+		RequestsLast24h:     output.RequestsLast24h,
+		GeneratedAt:         output.GeneratedAt,
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
@@ -0,0 +1,309 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AdminHandler handles HTTP requests for cross-account administration (admin:keys, admin:accounts)
+type AdminHandler struct {
+	lookupApiKeyByHash   *usecase.LookupApiKeyByHash
+	lookupApiKeyByRawKey *usecase.LookupApiKeyByRawKey
+	importAccounts       *usecase.ImportAccounts
+	purgeAccountData     *usecase.PurgeAccountData
+	auditLogger          audit.AuditLoggerInterface
+	auditDetail          AuditDetailConfig
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(lookupApiKeyByHash *usecase.LookupApiKeyByHash, lookupApiKeyByRawKey *usecase.LookupApiKeyByRawKey, importAccounts *usecase.ImportAccounts, purgeAccountData *usecase.PurgeAccountData, auditLogger audit.AuditLoggerInterface, auditDetail AuditDetailConfig) *AdminHandler {
+	return &AdminHandler{
+		lookupApiKeyByHash:   lookupApiKeyByHash,
+		lookupApiKeyByRawKey: lookupApiKeyByRawKey,
+		importAccounts:       importAccounts,
+		purgeAccountData:     purgeAccountData,
+		auditLogger:          auditLogger,
+		auditDetail:          auditDetail,
+	}
+}
+
+// GetAPIKeyByHash handles looking up the key and account a key hash belongs to
+// @Summary Look up an API key by hash
+// @Description Find which API key and account a key hash belongs to. Returns metadata only; does not record key usage.
+// @Tags admin
+// @Produce json
+// @Param hash path string true "Key hash"
+// @Success 200 {object} dto.ApiKeyLookupResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/by-hash/{hash} [get]
+func (h *AdminHandler) GetAPIKeyByHash(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Hashes are hex, but tolerate clients that URL-encode the path segment
+	keyHash, err := url.PathUnescape(c.Params("hash"))
+	if err != nil || keyHash == "" {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid key hash",
+		})
+	}
+
+	// Execute use case
+	output, err := h.lookupApiKeyByHash.Execute(ctx, usecase.LookupApiKeyByHashInput{KeyHash: keyHash})
+	if err != nil {
+		if err.Error() == "API key not found" {
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to look up API key",
+			Details: err.Error(),
+		})
+	}
+
+	if !InResourceScope(c, output.APIKey.AccountID.String()) {
+		return RespondErrorWith(c, outOfScopeResponse("account_id", output.APIKey.AccountID.String()))
+	}
+
+	response := dto.ApiKeyLookupResponse{
+		AccountID:      output.APIKey.AccountID,
+		ApiKeyResponse: toApiKeyResponse(output.APIKey),
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// LookupAPIKey handles checking which key, if any, a raw key maps to
+// @Summary Look up an API key by raw key
+// @Description Report whether a raw key maps to an API key, its account and key IDs, and whether the key is active. Does not record key usage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.LookupApiKeyRequest true "Raw key"
+// @Success 200 {object} dto.LookupApiKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/lookup [post]
+func (h *AdminHandler) LookupAPIKey(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse request
+	var req dto.LookupApiKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+	if req.RawKey == "" {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeValidationError,
+			Message: "raw_key is required",
+		})
+	}
+
+	// Execute use case
+	output, err := h.lookupApiKeyByRawKey.Execute(ctx, usecase.LookupApiKeyByRawKeyInput{RawKey: req.RawKey})
+	if err != nil {
+		if err.Error() == "API key not found" {
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to look up API key",
+			Details: err.Error(),
+		})
+	}
+
+	if !InResourceScope(c, output.AccountID.String()) {
+		return RespondErrorWith(c, outOfScopeResponse("account_id", output.AccountID.String()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.LookupApiKeyResponse{
+		Active:    output.Active,
+		AccountID: output.AccountID,
+		APIKeyID:  output.APIKeyID,
+	})
+}
+
+// ImportAccounts handles bulk-importing accounts with caller-provided IDs
+// @Summary Import accounts
+// @Description Create many accounts at once, keeping their IDs. Valid rows are inserted in one transaction; each row reports whether it was created or why it failed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body []dto.ImportAccountRequest true "Accounts to import"
+// @Success 200 {object} dto.ImportAccountsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/import [post]
+func (h *AdminHandler) ImportAccounts(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse request
+	var req []dto.ImportAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	rows := make([]usecase.ImportAccountRow, len(req))
+	for i, account := range req {
+		rows[i] = usecase.ImportAccountRow{
+			ID:         account.ID,
+			Name:       account.Name,
+			Status:     account.Status,
+			WebhookURL: account.WebhookURL,
+			OwnerID:    account.OwnerID,
+			CreatedAt:  account.CreatedAt,
+		}
+	}
+
+	// Execute use case
+	output, err := h.importAccounts.Execute(ctx, usecase.ImportAccountsInput{Accounts: rows})
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid input") {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeValidationError,
+				Message: "Invalid request data",
+				Details: err.Error(),
+			})
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to import accounts",
+			Details: err.Error(),
+		})
+	}
+
+	response := dto.ImportAccountsResponse{
+		Results: make([]dto.ImportAccountResult, len(output.Results)),
+		Created: output.Created,
+		Failed:  output.Failed,
+	}
+	for i, result := range output.Results {
+		response.Results[i] = dto.ImportAccountResult{
+			Index:     result.Index,
+			AccountID: result.AccountID,
+			Name:      result.Name,
+			Created:   result.Created,
+			Error:     result.Error,
+		}
+
+		// Audit each created account like a registration
+		if result.Created {
+			accountID, name := result.AccountID, result.Name
+			h.auditLogger.LogAccountCreation(
+				ctx,
+				&accountID,
+				&name,
+				c.IP(), c.Get("User-Agent"),
+				h.auditDetail.details(c, map[string]string{"source": "import"}),
+			)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// PurgeAccountData handles hard-deleting everything stored about an account
+// @Summary Purge account data
+// @Description Permanently delete an account, its API keys and idempotency keys, and optionally its audit events, for data-deletion requests. Returns what was removed.
+// @Tags admin
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Param include_audit_events query bool false "Also delete the account's audit events"
+// @Success 200 {object} dto.PurgeAccountDataResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/purge [post]
+func (h *AdminHandler) PurgeAccountData(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Execute use case
+	output, err := h.purgeAccountData.Execute(ctx, usecase.PurgeAccountDataInput{
+		AccountID:          accountID,
+		IncludeAuditEvents: c.QueryBool("include_audit_events"),
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+		if errors.Is(err, domain.ErrAccountHasLedgerPostings) {
+			return RespondError(c, domain.ErrCodeAccountHasLedger)
+		}
+		if strings.HasPrefix(err.Error(), "invalid input") {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeValidationError,
+				Message: "Invalid request data",
+				Details: err.Error(),
+			})
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to purge account data",
+			Details: err.Error(),
+		})
+	}
+
+	// Record the purge itself; it is logged after any audit events were deleted
+	// so the deletion request stays on record
+	event := &audit.AuditEvent{
+		EventType: audit.EventTypeAccountPurged,
+		AccountID: &accountID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   true,
+		Details: map[string]string{
+			"api_keys_deleted":         strconv.Itoa(output.APIKeysDeleted),
+			"idempotency_keys_deleted": strconv.Itoa(output.IdempotencyKeysDeleted),
+			"audit_events_deleted":     strconv.Itoa(output.AuditEventsDeleted),
+		},
+	}
+	if callerID, err := GetAccountID(c); err == nil {
+		event.Details["purged_by"] = callerID.String()
+	}
+	event.Details = h.auditDetail.details(c, event.Details)
+	h.auditLogger.LogEvent(ctx, event)
+
+	return c.Status(fiber.StatusOK).JSON(dto.PurgeAccountDataResponse{
+		AccountID:              output.AccountID,
+		APIKeysDeleted:         output.APIKeysDeleted,
+		IdempotencyKeysDeleted: output.IdempotencyKeysDeleted,
+		AuditEventsDeleted:     output.AuditEventsDeleted,
+		AuditEventsPurged:      output.AuditEventsPurged,
+		PurgedAt:               output.PurgedAt,
+	})
+}
@@ -0,0 +1,635 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ApiKeyHandler handles HTTP requests for API key lifecycle changes
+type ApiKeyHandler struct {
+	pauseApiKey      *usecase.PauseApiKey
+	resumeApiKey     *usecase.ResumeApiKey
+	regenerateApiKey *usecase.RegenerateApiKey
+	checkAccess      *usecase.CheckAccess
+	lookupByExtID    *usecase.LookupApiKeyByExternalID
+	countApiKeys     *usecase.CountApiKeys
+	resolvePerms     *usecase.ResolvePermissions
+	updatePerms      *usecase.UpdateApiKeyPermissions
+	auditLogger      audit.AuditLoggerInterface
+	auditDetail      AuditDetailConfig
+}
+
+// NewApiKeyHandler creates a new ApiKeyHandler
+func NewApiKeyHandler(pauseApiKey *usecase.PauseApiKey, resumeApiKey *usecase.ResumeApiKey, regenerateApiKey *usecase.RegenerateApiKey, checkAccess *usecase.CheckAccess, lookupByExtID *usecase.LookupApiKeyByExternalID, countApiKeys *usecase.CountApiKeys, resolvePerms *usecase.ResolvePermissions, updatePerms *usecase.UpdateApiKeyPermissions, auditLogger audit.AuditLoggerInterface, auditDetail AuditDetailConfig) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		pauseApiKey:      pauseApiKey,
+		resumeApiKey:     resumeApiKey,
+		regenerateApiKey: regenerateApiKey,
+		checkAccess:      checkAccess,
+		lookupByExtID:    lookupByExtID,
+		countApiKeys:     countApiKeys,
+		resolvePerms:     resolvePerms,
+		updatePerms:      updatePerms,
+		auditLogger:      auditLogger,
+		auditDetail:      auditDetail,
+	}
+}
+
+// PauseApiKey handles temporarily suspending an API key
+// @Summary Pause an API key
+// @Description Temporarily suspend an active API key. Paused keys fail validation until resumed.
+// @Tags auth
+// @Produce json
+// @Param api_key_id path string true "API Key ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.ApiKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/{api_key_id}/pause [post]
+func (h *ApiKeyHandler) PauseApiKey(c *fiber.Ctx) error {
+	return h.setPaused(c, h.pauseApiKey.Execute, audit.EventTypeAPIKeyPaused, "Failed to pause API key")
+}
+
+// ResumeApiKey handles reactivating a paused API key
+// @Summary Resume an API key
+// @Description Reactivate a paused API key. Revoked keys cannot be resumed.
+// @Tags auth
+// @Produce json
+// @Param api_key_id path string true "API Key ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.ApiKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/{api_key_id}/resume [post]
+func (h *ApiKeyHandler) ResumeApiKey(c *fiber.Ctx) error {
+	return h.setPaused(c, h.resumeApiKey.Execute, audit.EventTypeAPIKeyResumed, "Failed to resume API key")
+}
+
+// setPaused runs a pause or resume use case for the key in the path and audits the result
+func (h *ApiKeyHandler) setPaused(
+	c *fiber.Ctx,
+	execute func(context.Context, usecase.SetApiKeyPausedInput) (*usecase.SetApiKeyPausedOutput, error),
+	eventType string,
+	failureMessage string,
+) error {
+	ctx := context.Background()
+
+	// Parse API key ID
+	apiKeyID, err := uuid.Parse(c.Params("api_key_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAPIKeyID)
+	}
+
+	expectedVersion, errResp := ifMatchVersion(c)
+	if errResp != nil {
+		return RespondErrorWith(c, *errResp)
+	}
+
+	// Get account ID from context
+	accountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// Execute use case
+	output, err := execute(ctx, usecase.SetApiKeyPausedInput{
+		APIKeyID:        apiKeyID,
+		AccountID:       accountID,
+		ExpectedVersion: expectedVersion,
+	})
+
+	event := &audit.AuditEvent{
+		EventType: eventType,
+		AccountID: &accountID,
+		APIKeyID:  &apiKeyID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   err == nil,
+	}
+	if err != nil {
+		event.Details = map[string]string{"error": err.Error()}
+	}
+	event.Details = h.auditDetail.details(c, event.Details)
+	h.auditLogger.LogEvent(ctx, event)
+
+	if err != nil {
+		switch {
+		case err.Error() == "API key not found":
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeAPIKeyState,
+				Message: domain.ErrCodeAPIKeyState.DefaultMessage(),
+				Details: err.Error(),
+			})
+		case errors.Is(err, domain.ErrVersionConflict):
+			return RespondError(c, versionConflictCode(expectedVersion))
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: failureMessage,
+			Details: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(output.APIKey.Version))
+	return c.Status(fiber.StatusOK).JSON(toApiKeyResponse(output.APIKey))
+}
+
+// RegenerateApiKey handles replacing an API key's secret
+// @Summary Regenerate an API key secret
+// @Description Replace the secret of an API key, keeping its ID, name, permissions and expiry. The old secret stops working immediately and the new one is only returned once.
+// @Tags auth
+// @Produce json
+// @Param api_key_id path string true "API Key ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Success 200 {object} dto.IssueApiKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/{api_key_id}/regenerate [post]
+func (h *ApiKeyHandler) RegenerateApiKey(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse API key ID
+	apiKeyID, err := uuid.Parse(c.Params("api_key_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAPIKeyID)
+	}
+
+	expectedVersion, errResp := ifMatchVersion(c)
+	if errResp != nil {
+		return RespondErrorWith(c, *errResp)
+	}
+
+	// Get account ID from context
+	accountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// Execute use case
+	output, err := h.regenerateApiKey.Execute(ctx, usecase.RegenerateApiKeyInput{
+		APIKeyID:        apiKeyID,
+		AccountID:       accountID,
+		ExpectedVersion: expectedVersion,
+	})
+
+	event := &audit.AuditEvent{
+		EventType: audit.EventTypeAPIKeyRegenerated,
+		AccountID: &accountID,
+		APIKeyID:  &apiKeyID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   err == nil,
+	}
+	if err != nil {
+		event.Details = map[string]string{"error": err.Error()}
+	}
+	event.Details = h.auditDetail.details(c, event.Details)
+	h.auditLogger.LogEvent(ctx, event)
+
+	if err != nil {
+		switch {
+		case err.Error() == "API key not found":
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeAPIKeyState,
+				Message: domain.ErrCodeAPIKeyState.DefaultMessage(),
+				Details: err.Error(),
+			})
+		case errors.Is(err, domain.ErrVersionConflict):
+			return RespondError(c, versionConflictCode(expectedVersion))
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to regenerate API key",
+			Details: err.Error(),
+		})
+	}
+
+	apiKey := output.APIKey
+	c.Set(fiber.HeaderETag, formatETag(apiKey.Version))
+	return c.Status(fiber.StatusOK).JSON(dto.IssueApiKeyResponse{
+		APIKeyID:       apiKey.ID,
+		APIKey:         output.RawKey,
+		KeyHash:        apiKey.KeyHash,
+		AccountID:      apiKey.AccountID,
+		Name:           apiKey.Name,
+		Permissions:    []string(apiKey.Permissions),
+		Status:         string(apiKey.Status),
+		ExpiresAt:      apiKey.ExpiresAt,
+		CreatedAt:      apiKey.CreatedAt,
+		ExternalID:     apiKey.ExternalID,
+		ResourceScopes: apiKey.ResourceScopes,
+	})
+}
+
+// UpdateApiKeyPermissions handles replacing an API key's permissions
+// @Summary Update API key permissions
+// @Description Replace the permissions of one of the caller's API keys. Aliases and wildcards are resolved as at issuance, and the change is recorded as an api_key_permissions_changed audit event.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param api_key_id path string true "API Key ID"
+// @Param If-Match header string false "ETag of the version being updated"
+// @Param request body dto.UpdateApiKeyPermissionsRequest true "New permissions"
+// @Success 200 {object} dto.UpdateApiKeyPermissionsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/{api_key_id}/permissions [put]
+func (h *ApiKeyHandler) UpdateApiKeyPermissions(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse API key ID
+	apiKeyID, err := uuid.Parse(c.Params("api_key_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAPIKeyID)
+	}
+
+	expectedVersion, errResp := ifMatchVersion(c)
+	if errResp != nil {
+		return RespondErrorWith(c, *errResp)
+	}
+
+	var req dto.UpdateApiKeyPermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Failed to parse request body",
+			Details: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	// Get account ID from context
+	accountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// The same grant rules as issuance, so a key cannot upgrade itself
+	if errResp := grantErrorResponse(c, h.updatePerms.CanonicalPermissions(req.Permissions)); errResp != nil {
+		return RespondErrorWith(c, *errResp)
+	}
+
+	// Execute use case
+	output, err := h.updatePerms.Execute(ctx, usecase.UpdateApiKeyPermissionsInput{
+		APIKeyID:        apiKeyID,
+		AccountID:       accountID,
+		Permissions:     req.Permissions,
+		ExpectedVersion: expectedVersion,
+	})
+
+	event := &audit.AuditEvent{
+		EventType: audit.EventTypeAPIKeyPermissionsChanged,
+		AccountID: &accountID,
+		APIKeyID:  &apiKeyID,
+		IPAddress: c.IP(),
+		UserAgent: c.Get("User-Agent"),
+		Success:   err == nil,
+	}
+	if err != nil {
+		event.Details = map[string]string{"error": err.Error()}
+	} else {
+		event.APIKeyName = &output.APIKey.Name
+		event.Details = map[string]string{
+			"previous_permissions": strings.Join(output.PreviousPermissions, ","),
+			"permissions":          strings.Join(output.APIKey.Permissions, ","),
+			"added":                strings.Join(output.Added, ","),
+			"removed":              strings.Join(output.Removed, ","),
+		}
+	}
+	event.Details = h.auditDetail.details(c, event.Details)
+	h.auditLogger.LogEvent(ctx, event)
+
+	if err != nil {
+		switch {
+		case err.Error() == "API key not found":
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		case err.Error() == "account not found or inactive":
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		case errors.Is(err, domain.ErrInvalidPermission):
+			return RespondErrorWith(c, invalidPermissionsResponse(err, req.Permissions))
+		case errors.Is(err, domain.ErrPermissionsRequired):
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("permissions", domain.ErrPermissionsRequired.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		case errors.Is(err, domain.ErrPermissionOutsideAccountScope):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: "The account is not allowed to grant these permissions",
+				Details: err.Error(),
+			})
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeAPIKeyState,
+				Message: domain.ErrCodeAPIKeyState.DefaultMessage(),
+				Details: err.Error(),
+			})
+		case errors.Is(err, domain.ErrVersionConflict):
+			return RespondError(c, versionConflictCode(expectedVersion))
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to update API key permissions",
+			Details: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(output.APIKey.Version))
+	return c.Status(fiber.StatusOK).JSON(dto.UpdateApiKeyPermissionsResponse{
+		ApiKeyResponse:      toApiKeyResponse(output.APIKey),
+		PreviousPermissions: output.PreviousPermissions,
+		Added:               output.Added,
+		Removed:             output.Removed,
+	})
+}
+
+// CheckAccess handles explaining whether the caller's key, or another key of its account, may use a permission
+// @Summary Check whether a key can perform an operation
+// @Description Combine key validity, account status and the key's permissions into one decision for the calling key, or for the key given in api_key_id. Denials carry a reason: key_inactive, key_expired, account_suspended, account_inactive, keys_disabled or missing_permission.
+// @Tags auth
+// @Produce json
+// @Param permission query string true "Permission to check"
+// @Param api_key_id query string false "Key to check instead of the calling key; requires read:keys"
+// @Success 200 {object} dto.CheckAccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/can [get]
+func (h *ApiKeyHandler) CheckAccess(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	requested := c.Query("permission")
+	permission := h.checkAccess.CanonicalPermission(requested)
+	if permission == "" || !domain.IsValidPermission(permission) {
+		var fieldErrs dto.ValidationErrors
+		if permission == "" {
+			fieldErrs.Add("permission", "permission is required")
+		} else {
+			fieldErrs.Add("permission", fmt.Sprintf("unknown permission '%s'", requested))
+		}
+		return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+	}
+
+	input := usecase.CheckAccessInput{Permission: permission}
+	if target := c.Query("api_key_id"); target != "" {
+		// Checking another key needs read:keys, and admin:keys for keys of other accounts
+		apiKeyID, err := uuid.Parse(target)
+		if err != nil {
+			return RespondError(c, domain.ErrCodeInvalidAPIKeyID)
+		}
+		if !HasPermission(c, domain.PermissionReadKeys) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: "Checking another API key requires read:keys",
+			})
+		}
+		input.APIKeyID = apiKeyID
+		if !HasPermission(c, domain.PermissionAdminKeys) {
+			accountID, err := GetAccountID(c)
+			if err != nil {
+				return RespondErrorWith(c, dto.ErrorResponse{
+					Error:   domain.ErrCodeInternalError,
+					Message: "Failed to get account context",
+					Details: err.Error(),
+				})
+			}
+			input.AccountID = accountID
+		}
+	} else {
+		// Get API key ID from context
+		apiKeyID, err := GetAPIKeyID(c)
+		if err != nil {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInternalError,
+				Message: "Failed to get API key context",
+				Details: err.Error(),
+			})
+		}
+		input.APIKeyID = apiKeyID
+	}
+
+	// Execute use case
+	output, err := h.checkAccess.Execute(ctx, input)
+	if err != nil {
+		if err.Error() == "API key not found" {
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		}
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to check access",
+			Details: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CheckAccessResponse{
+		Permission: permission,
+		Allowed:    output.Allowed,
+		Reason:     output.Reason,
+	})
+}
+
+// ResolvePermissions handles previewing how a permission list will be interpreted
+// @Summary Resolve a permission list
+// @Description Show how a raw permission list would be interpreted at issuance: aliases resolved, wildcards expanded and each entry validated. Nothing is issued.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResolvePermissionsRequest true "Permissions to resolve"
+// @Success 200 {object} dto.ResolvePermissionsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/v1/auth/permissions/resolve [post]
+func (h *ApiKeyHandler) ResolvePermissions(c *fiber.Ctx) error {
+	// Parse request
+	var req dto.ResolvePermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+	if len(req.Permissions) == 0 {
+		var fieldErrs dto.ValidationErrors
+		fieldErrs.Add("permissions", "permissions is required")
+		return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+	}
+
+	// Execute use case
+	output, err := h.resolvePerms.Execute(usecase.ResolvePermissionsInput{Permissions: req.Permissions})
+	if err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	entries := make([]dto.ResolvedPermissionResponse, len(output.Entries))
+	for i, entry := range output.Entries {
+		entries[i] = dto.ResolvedPermissionResponse{
+			Input:       entry.Input,
+			Canonical:   entry.Canonical,
+			Permissions: entry.Permissions,
+			Error:       entry.Error,
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ResolvePermissionsResponse{
+		Entries:     entries,
+		Permissions: []string(output.Permissions),
+		Valid:       output.Valid,
+	})
+}
+
+// GetApiKeyByExternalID handles looking up one of the caller's keys by external ID
+// @Summary Get an API key by external ID
+// @Description Find the caller's API key carrying the external ID set at issuance. Keys of other accounts are never returned.
+// @Tags auth
+// @Produce json
+// @Param external_id path string true "External ID"
+// @Success 200 {object} dto.ApiKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/by-external-id/{external_id} [get]
+func (h *ApiKeyHandler) GetApiKeyByExternalID(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	externalID, err := url.PathUnescape(c.Params("external_id"))
+	if err != nil || externalID == "" {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid external ID",
+		})
+	}
+
+	// Get account ID from context
+	accountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// Execute use case
+	apiKey, err := h.lookupByExtID.Execute(ctx, usecase.LookupApiKeyByExternalIDInput{
+		AccountID:  accountID,
+		ExternalID: externalID,
+	})
+	if err != nil {
+		if err.Error() == "API key not found" {
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to look up API key",
+			Details: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(toApiKeyResponse(apiKey))
+}
+
+// CountApiKeys handles reading an account's live API key count
+// @Summary Count an account's API keys
+// @Description Return the number of the account's keys that have not been revoked, read from a counter kept with the keys instead of listing them. Keys that expired since the counter was last reconciled may still be included.
+// @Tags auth
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Success 200 {object} dto.ApiKeyCountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/api-keys/count [get]
+func (h *ApiKeyHandler) CountApiKeys(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Callers may only count their own account's keys
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: "Cannot count another account's API keys",
+		})
+	}
+
+	// Execute use case
+	output, err := h.countApiKeys.Execute(ctx, usecase.CountApiKeysInput{AccountID: accountID})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to count API keys",
+			Details: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ApiKeyCountResponse{
+		AccountID:  output.AccountID,
+		ActiveKeys: output.ActiveKeys,
+	})
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aws-payment-gateway/internal/auth/audit"
+)
+
+// AuditDetailConfig decides, per route, how much request detail handlers put in
+// the audit events they record. Authentication events from the auth middleware
+// are not affected.
+type AuditDetailConfig struct {
+	// Default applies to routes without their own level; empty means audit.DetailFull
+	Default audit.DetailLevel
+	// Routes maps a route to its level. Keys are the route pattern as registered,
+	// e.g. "/api/v1/auth/api-keys/:api_key_id", optionally preceded by the method
+	// and a space; a method-specific entry wins over one for the bare path.
+	Routes map[string]audit.DetailLevel
+}
+
+// details trims details to the level configured for the route handling c
+func (cfg AuditDetailConfig) details(c *fiber.Ctx, details map[string]string) map[string]string {
+	return cfg.levelFor(c.Method(), c.Route().Path).Apply(details)
+}
+
+// levelFor returns the level for a route
+func (cfg AuditDetailConfig) levelFor(method, path string) audit.DetailLevel {
+	if level, ok := cfg.Routes[method+" "+path]; ok {
+		return level
+	}
+	if level, ok := cfg.Routes[path]; ok {
+		return level
+	}
+	if cfg.Default != "" {
+		return cfg.Default
+	}
+	return audit.DetailFull
+}
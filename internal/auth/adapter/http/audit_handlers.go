@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// defaultAuditWindow is how far back audit queries look when since is omitted
+const defaultAuditWindow = 24 * time.Hour
+
+// AuditHandler handles HTTP requests for reading the audit log
+type AuditHandler struct {
+	listAuditEvents *usecase.ListAuditEvents
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(listAuditEvents *usecase.ListAuditEvents) *AuditHandler {
+	return &AuditHandler{
+		listAuditEvents: listAuditEvents,
+	}
+}
+
+// ListAuditEvents handles listing audit events
+// @Summary List audit events
+// @Description List audit events of one type, newest first. Keys with read:audit may query any account or all of them; keys with only read:own-audit see their own account's events.
+// @Tags audit
+// @Produce json
+// @Param event_type query string true "Event type, e.g. authentication"
+// @Param account_id query string false "Only events for this account"
+// @Param since query string false "RFC 3339 start of the window (default: 24h before until)"
+// @Param until query string false "RFC 3339 end of the window (default: now)"
+// @Param limit query int false "Maximum number of events" default(10)
+// @Success 200 {object} dto.ListAuditEventsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/audit-events [get]
+func (h *AuditHandler) ListAuditEvents(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	input, fieldErrs := parseAuditQuery(c)
+	if err := fieldErrs.Err(); err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	// read:own-audit is pinned to the caller's account; only read:audit may look further
+	if !HasPermission(c, domain.PermissionReadAudit) {
+		if input.AccountID != nil && *input.AccountID != callerAccountID {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: fmt.Sprintf("Permission '%s' is required to view other accounts' audit events", domain.PermissionReadAudit),
+			})
+		}
+		input.AccountID = &callerAccountID
+	}
+
+	// Keys with resource scopes only see the events of accounts in their scopes
+	if len(GetResourceScopes(c)) > 0 {
+		if input.AccountID == nil {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeResourceOutOfScope,
+				Message: "API keys with resource scopes must pass an account_id within their scopes",
+			})
+		}
+		if !InResourceScope(c, input.AccountID.String()) {
+			return RespondErrorWith(c, outOfScopeResponse("account_id", input.AccountID.String()))
+		}
+	}
+
+	output, err := h.listAuditEvents.Execute(ctx, input)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to list audit events",
+			Details: err.Error(),
+		})
+	}
+
+	events := make([]dto.AuditEventResponse, len(output.Events))
+	for i, event := range output.Events {
+		events[i] = toAuditEventResponse(event)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ListAuditEventsResponse{
+		Events: events,
+		Since:  input.Since,
+		Until:  input.Until,
+	})
+}
+
+// parseAuditQuery reads the audit query parameters, applying defaults and
+// collecting a field error for each bad value
+func parseAuditQuery(c *fiber.Ctx) (usecase.ListAuditEventsInput, dto.ValidationErrors) {
+	var errs dto.ValidationErrors
+	input := usecase.ListAuditEventsInput{
+		EventType: c.Query("event_type"),
+		Until:     time.Now().UTC(),
+		Limit:     defaultPageLimit,
+	}
+
+	switch {
+	case input.EventType == "":
+		errs.Add("event_type", "event_type is required")
+	case !audit.IsKnownEventType(input.EventType):
+		errs.Add("event_type", fmt.Sprintf("unknown event_type '%s'", input.EventType))
+	}
+
+	if raw := c.Query("account_id"); raw != "" {
+		accountID, err := uuid.Parse(raw)
+		if err != nil {
+			errs.Add("account_id", "account_id must be a UUID")
+		} else {
+			input.AccountID = &accountID
+		}
+	}
+
+	if raw := c.Query("until"); raw != "" {
+		until, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			errs.Add("until", "until must be an RFC 3339 timestamp")
+		} else {
+			input.Until = until.UTC()
+		}
+	}
+
+	input.Since = input.Until.Add(-defaultAuditWindow)
+	if raw := c.Query("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			errs.Add("since", "since must be an RFC 3339 timestamp")
+		} else {
+			input.Since = since.UTC()
+		}
+	}
+
+	switch {
+	case input.Until.Before(input.Since):
+		errs.Add("since", "since must not be after until")
+	case input.Until.Sub(input.Since) > usecase.MaxAuditWindow:
+		errs.Add("since", fmt.Sprintf("the time range must not exceed %d days", int(usecase.MaxAuditWindow.Hours()/24)))
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			errs.Add("limit", fmt.Sprintf("limit must be a number, got '%s'", raw))
+		case n < 1 || n > maxPageLimit:
+			errs.Add("limit", fmt.Sprintf("limit must be between 1 and %d, got %d", maxPageLimit, n))
+		default:
+			input.Limit = n
+		}
+	}
+
+	return input, errs
+}
+
+// toAuditEventResponse converts an audit event to its response shape
+func toAuditEventResponse(event *audit.AuditEvent) dto.AuditEventResponse {
+	return dto.AuditEventResponse{
+		Timestamp:   event.Timestamp,
+		EventType:   event.EventType,
+		Description: audit.GetEventDescription(event.EventType),
+		AccountID:   event.AccountID,
+		APIKeyID:    event.APIKeyID,
+		APIKeyName:  event.APIKeyName,
+		IPAddress:   event.IPAddress,
+		UserAgent:   event.UserAgent,
+		Success:     event.Success,
+		Details:     event.Details,
+	}
+}
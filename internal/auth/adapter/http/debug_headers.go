@@ -0,0 +1,27 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Debug response headers identifying the authenticated caller
+const (
+	HeaderDebugAccountID = "X-Account-ID"
+	HeaderDebugAPIKeyID  = "X-API-Key-ID"
+)
+
+// DebugHeaders echoes the authenticated account and API key IDs on every response
+// so requests can be traced from the client side. Only IDs are exposed, never the
+// key itself. Register it after RequireAuth, and only outside production.
+func DebugHeaders() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if accountID, err := GetAccountID(c); err == nil {
+			c.Set(HeaderDebugAccountID, accountID.String())
+		}
+		if apiKeyID, err := GetAPIKeyID(c); err == nil {
+			c.Set(HeaderDebugAPIKeyID, apiKeyID.String())
+		}
+
+		return c.Next()
+	}
+}
@@ -3,129 +3,216 @@ package dto
 import (
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
 )
 
 // ErrorResponse represents a standard error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
+	Error   domain.ErrorCode `json:"error"`
+	Message string           `json:"message"`
+	Details string           `json:"details,omitempty"`
+	Fields  []FieldError     `json:"fields,omitempty"`
+}
+
+// ProblemDetails is the RFC 7807 (application/problem+json) form of ErrorResponse.
+// Code, Details and Fields are extension members carrying the same values.
+type ProblemDetails struct {
+	Type    string           `json:"type"`
+	Title   string           `json:"title"`
+	Status  int              `json:"status"`
+	Detail  string           `json:"detail,omitempty"`
+	Code    domain.ErrorCode `json:"code"`
+	Details string           `json:"details,omitempty"`
+	Fields  []FieldError     `json:"fields,omitempty"`
+}
+
+// FieldError describes a single validation failure for a request field
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+}
+
+// ValidationErrors collects every validation failure found in a request
+type ValidationErrors []FieldError
+
+// Add records a validation failure for a field
+func (v *ValidationErrors) Add(field, message string) {
+	*v = append(*v, FieldError{Field: field, Message: message})
+}
+
+// Err returns the collected failures as an error, or nil if there are none
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Error implements the error interface
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, fe := range v {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
 }
 
 // RegisterAppRequest represents a registration request
 type RegisterAppRequest struct {
 	Name       string  `json:"name" validate:"required,min=3,max=100"`
 	WebhookURL *string `json:"webhook_url,omitempty" validate:"omitempty,url"`
+	// KeyPrefix is prepended to keys issued for the account instead of the service default
+	KeyPrefix string `json:"key_prefix,omitempty"`
 }
 
 // Validate validates the registration request
 func (r *RegisterAppRequest) Validate() error {
-	if r.Name == "" {
-		return fmt.Errorf("name is required")
-	}
-
-	if len(r.Name) < 3 {
-		return fmt.Errorf("name must be at least 3 characters")
-	}
+	var errs ValidationErrors
 
-	if len(r.Name) > 100 {
-		return fmt.Errorf("name must be at most 100 characters")
+	if r.Name == "" {
+		errs.Add("name", "name is required")
+	} else if len(r.Name) < 3 {
+		errs.Add("name", "name must be at least 3 characters")
+	} else if len(r.Name) > 100 {
+		errs.Add("name", "name must be at most 100 characters")
 	}
 
 	if r.WebhookURL != nil {
 		if _, err := url.ParseRequestURI(*r.WebhookURL); err != nil {
-			return fmt.Errorf("invalid webhook URL: %w", err)
+			errs.Add("webhook_url", fmt.Sprintf("invalid webhook URL: %v", err))
 		}
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // RegisterAppResponse represents a registration response
 type RegisterAppResponse struct {
 	AccountID uuid.UUID `json:"account_id"`
+	OwnerID   uuid.UUID `json:"owner_id"`
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
+	KeyPrefix string    `json:"key_prefix,omitempty"`
 }
 
 // IssueApiKeyRequest represents an API key issuance request
 type IssueApiKeyRequest struct {
 	AccountID   uuid.UUID `json:"account_id" validate:"required"`
 	Name        string    `json:"name" validate:"required,min=3,max=100"`
-	Permissions []string  `json:"permissions" validate:"required,dive,required,min=1"`
+	Permissions []string  `json:"permissions" validate:"omitempty,dive,required,min=1"`
 	ExpiresIn   *int      `json:"expires_in,omitempty" validate:"omitempty,min=1,max=8760"` // hours
+	ExternalID  string    `json:"external_id,omitempty" validate:"omitempty,max=128"`
+	// ResourceScopes restricts the key to these resources, e.g. account IDs
+	ResourceScopes []string `json:"resource_scopes,omitempty" validate:"omitempty,max=100,dive,required,max=128"`
 }
 
+// maxResourceScopes caps how many resources a key can be scoped to
+const maxResourceScopes = 100
+
 // Validate validates the API key issuance request
 func (r *IssueApiKeyRequest) Validate() error {
+	var errs ValidationErrors
+
 	if r.AccountID == uuid.Nil {
-		return fmt.Errorf("account_id is required")
+		errs.Add("account_id", "account_id is required")
 	}
 
 	if r.Name == "" {
-		return fmt.Errorf("name is required")
+		errs.Add("name", "name is required")
+	} else if len(r.Name) < 3 {
+		errs.Add("name", "name must be at least 3 characters")
+	} else if len(r.Name) > 100 {
+		errs.Add("name", "name must be at most 100 characters")
 	}
 
-	if len(r.Name) < 3 {
-		return fmt.Errorf("name must be at least 3 characters")
+	// An empty list is allowed here; the use case applies configured defaults
+	// or rejects it
+	for i, perm := range r.Permissions {
+		if perm == "" {
+			errs.Add(fmt.Sprintf("permissions[%d]", i), "permission cannot be empty")
+		}
 	}
 
-	if len(r.Name) > 100 {
-		return fmt.Errorf("name must be at most 100 characters")
+	if r.ExpiresIn != nil {
+		if *r.ExpiresIn < 1 {
+			errs.Add("expires_in", "expires_in must be at least 1 hour")
+		} else if *r.ExpiresIn > 8760 {
+			errs.Add("expires_in", "expires_in must be at most 8760 hours (1 year)")
+		}
 	}
 
-	if len(r.Permissions) == 0 {
-		return fmt.Errorf("at least one permission is required")
+	if r.ExternalID != "" && !isValidExternalID(r.ExternalID) {
+		errs.Add("external_id", "external_id must be at most 128 letters, digits, '.', '_', ':' or '-'")
 	}
 
-	for _, perm := range r.Permissions {
-		if perm == "" {
-			return fmt.Errorf("permission cannot be empty")
+	if len(r.ResourceScopes) > maxResourceScopes {
+		errs.Add("resource_scopes", fmt.Sprintf("resource_scopes must have at most %d entries", maxResourceScopes))
+	}
+	for i, scope := range r.ResourceScopes {
+		if scope == "" {
+			errs.Add(fmt.Sprintf("resource_scopes[%d]", i), "resource scope cannot be empty")
+		} else if len(scope) > 128 {
+			errs.Add(fmt.Sprintf("resource_scopes[%d]", i), "resource scope must be at most 128 characters")
 		}
 	}
 
-	if r.ExpiresIn != nil {
-		if *r.ExpiresIn < 1 {
-			return fmt.Errorf("expires_in must be at least 1 hour")
-		}
+	return errs.Err()
+}
 
-		if *r.ExpiresIn > 8760 {
-			return fmt.Errorf("expires_in must be at most 8760 hours (1 year)")
+// isValidExternalID checks that an external ID is short and URL-path safe
+func isValidExternalID(externalID string) bool {
+	if len(externalID) > 128 {
+		return false
+	}
+	for _, r := range externalID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == ':', r == '-':
+		default:
+			return false
 		}
 	}
-
-	return nil
+	return true
 }
 
 // IssueApiKeyResponse represents an API key issuance response
 type IssueApiKeyResponse struct {
-	APIKeyID    uuid.UUID `json:"api_key_id"`
-	APIKey      string    `json:"api_key"` // The actual API key (only returned once)
-	KeyHash     string    `json:"key_hash"`
-	AccountID   uuid.UUID `json:"account_id"`
-	Name        string    `json:"name"`
-	Permissions []string  `json:"permissions"`
-	Status      string    `json:"status"`
-	ExpiresAt   time.Time `json:"expires_at"`
-	CreatedAt   time.Time `json:"created_at"`
+	APIKeyID       uuid.UUID `json:"api_key_id"`
+	APIKey         string    `json:"api_key"` // The actual API key (only returned once)
+	KeyHash        string    `json:"key_hash"`
+	AccountID      uuid.UUID `json:"account_id"`
+	Name           string    `json:"name"`
+	Permissions    []string  `json:"permissions"`
+	Status         string    `json:"status"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExternalID     string    `json:"external_id,omitempty"`
+	ResourceScopes []string  `json:"resource_scopes,omitempty"`
 }
 
 // ValidateApiKeyRequest represents an API key validation request
 type ValidateApiKeyRequest struct {
 	KeyHash string `json:"key_hash" validate:"required"`
+	// ClientCertFingerprint is the SHA-256 fingerprint of the client certificate
+	// the key was presented with; required for accounts with require_client_cert
+	ClientCertFingerprint string `json:"client_cert_fingerprint,omitempty"`
 }
 
 // Validate validates the API key validation request
 func (r *ValidateApiKeyRequest) Validate() error {
+	var errs ValidationErrors
+
 	if r.KeyHash == "" {
-		return fmt.Errorf("key_hash is required")
+		errs.Add("key_hash", "key_hash is required")
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // ValidateApiKeyResponse represents an API key validation response
@@ -135,19 +222,150 @@ type ValidateApiKeyResponse struct {
 	APIKeyID    *uuid.UUID `json:"api_key_id,omitempty"`
 	Name        *string    `json:"name,omitempty"`
 	Permissions []string   `json:"permissions,omitempty"`
-	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
-	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// PermissionChecks replaces Permissions when the service limits validate output to a configured scope
+	PermissionChecks map[string]bool `json:"permission_checks,omitempty"`
+	LastUsedAt       *time.Time      `json:"last_used_at,omitempty"`
+	ExpiresAt        *time.Time      `json:"expires_at,omitempty"`
+	// Restricted means the account is suspended and only allowlisted permissions are returned
+	Restricted bool `json:"restricted,omitempty"`
+	// InGracePeriod means the key has expired and will stop working once the grace period ends
+	InGracePeriod bool `json:"in_grace_period,omitempty"`
+	// KeysDisabled means the account has switched off all of its keys
+	KeysDisabled bool `json:"keys_disabled,omitempty"`
+	// ResourceScopes are the only resources the key may act on; absent means unrestricted
+	ResourceScopes []string `json:"resource_scopes,omitempty"`
+	// ClientCertRejected means the account requires a client certificate and
+	// client_cert_fingerprint was missing or not in its allowlist
+	ClientCertRejected bool `json:"client_cert_rejected,omitempty"`
+}
+
+// CheckAccessResponse represents whether a key may use a permission
+type CheckAccessResponse struct {
+	Permission string `json:"permission"`
+	Allowed    bool   `json:"allowed"`
+	// Reason is key_inactive, key_expired, account_suspended, account_inactive, keys_disabled or missing_permission
+	Reason string `json:"reason,omitempty"`
+}
+
+// ResolvePermissionsRequest represents a raw permission list to preview
+type ResolvePermissionsRequest struct {
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+// ResolvedPermissionResponse reports how one entry of the raw list was interpreted
+type ResolvedPermissionResponse struct {
+	Input       string   `json:"input"`
+	Canonical   string   `json:"canonical"`
+	Permissions []string `json:"permissions"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// ResolvePermissionsResponse represents the interpreted permission list
+type ResolvePermissionsResponse struct {
+	Entries     []ResolvedPermissionResponse `json:"entries"`
+	Permissions []string                     `json:"permissions"`
+	Valid       bool                         `json:"valid"`
+}
+
+// ValidateApiKeyPairRequest represents an account+key pair validation request
+type ValidateApiKeyPairRequest struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	RawKey    string    `json:"raw_key" validate:"required"`
+	// ClientCertFingerprint is the SHA-256 fingerprint of the client certificate
+	// the key was presented with; required for accounts with require_client_cert
+	ClientCertFingerprint string `json:"client_cert_fingerprint,omitempty"`
+}
+
+// Validate validates the account+key pair validation request
+func (r *ValidateApiKeyPairRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.AccountID == uuid.Nil {
+		errs.Add("account_id", "account_id is required")
+	}
+
+	if r.RawKey == "" {
+		errs.Add("raw_key", "raw_key is required")
+	}
+
+	return errs.Err()
+}
+
+// ValidateApiKeyPairResponse represents an account+key pair validation response.
+// Key details are only included when the pair is valid.
+type ValidateApiKeyPairResponse struct {
+	Valid       bool       `json:"valid"`
+	Reason      string     `json:"reason,omitempty"`
+	AccountID   *uuid.UUID `json:"account_id,omitempty"`
+	APIKeyID    *uuid.UUID `json:"api_key_id,omitempty"`
+	Name        *string    `json:"name,omitempty"`
+	Permissions []string   `json:"permissions,omitempty"`
+	// PermissionChecks replaces Permissions when the service limits validate output to a configured scope
+	PermissionChecks map[string]bool `json:"permission_checks,omitempty"`
+	ExpiresAt        *time.Time      `json:"expires_at,omitempty"`
 }
 
 // ApiKeyResponse represents an API key in list responses
 type ApiKeyResponse struct {
-	APIKeyID    uuid.UUID  `json:"api_key_id"`
-	Name        string     `json:"name"`
-	Permissions []string   `json:"permissions"`
-	Status      string     `json:"status"`
-	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
-	ExpiresAt   time.Time  `json:"expires_at"`
-	CreatedAt   time.Time  `json:"created_at"`
+	APIKeyID       uuid.UUID  `json:"api_key_id"`
+	Name           string     `json:"name"`
+	Permissions    []string   `json:"permissions"`
+	Status         string     `json:"status"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Version        int        `json:"version"`
+	ExternalID     string     `json:"external_id,omitempty"`
+	KeyHint        string     `json:"key_hint,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	ResourceScopes []string   `json:"resource_scopes,omitempty"`
+}
+
+// UpdateApiKeyPermissionsRequest represents a request to replace an API key's permissions
+type UpdateApiKeyPermissionsRequest struct {
+	Permissions []string `json:"permissions" validate:"required,dive,required,min=1"`
+}
+
+// Validate validates the permission update request
+func (r *UpdateApiKeyPermissionsRequest) Validate() error {
+	var errs ValidationErrors
+
+	if len(r.Permissions) == 0 {
+		errs.Add("permissions", "at least one permission is required")
+	}
+	for i, perm := range r.Permissions {
+		if perm == "" {
+			errs.Add(fmt.Sprintf("permissions[%d]", i), "permission cannot be empty")
+		}
+	}
+
+	return errs.Err()
+}
+
+// UpdateApiKeyPermissionsResponse represents the updated key and what changed
+type UpdateApiKeyPermissionsResponse struct {
+	ApiKeyResponse
+	PreviousPermissions []string `json:"previous_permissions"`
+	Added               []string `json:"added"`
+	Removed             []string `json:"removed"`
+}
+
+// ApiKeyLookupResponse represents an API key found by hash, with its owning account
+type ApiKeyLookupResponse struct {
+	AccountID uuid.UUID `json:"account_id"`
+	ApiKeyResponse
+}
+
+// LookupApiKeyRequest represents a request to look up an API key by its raw value
+type LookupApiKeyRequest struct {
+	RawKey string `json:"raw_key" validate:"required"`
+}
+
+// LookupApiKeyResponse reports which key a raw key maps to, without any other metadata
+type LookupApiKeyResponse struct {
+	Active    bool      `json:"active"`
+	AccountID uuid.UUID `json:"account_id"`
+	APIKeyID  uuid.UUID `json:"api_key_id"`
 }
 
 // GetAPIKeysResponse represents a get API keys response
@@ -158,10 +376,241 @@ type GetAPIKeysResponse struct {
 	Total   int              `json:"total"`
 }
 
+// ListRevokedAPIKeysResponse represents a page of recently revoked API keys
+type ListRevokedAPIKeysResponse struct {
+	APIKeys []ApiKeyResponse `json:"api_keys"`
+	Since   time.Time        `json:"since"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	Total   int              `json:"total"`
+}
+
+// ListActiveAPIKeysResponse represents a page of recently used API keys
+type ListActiveAPIKeysResponse struct {
+	APIKeys []ApiKeyResponse `json:"api_keys"`
+	Within  string           `json:"within"`
+	Since   time.Time        `json:"since"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	Total   int              `json:"total"`
+}
+
+// AuditEventResponse represents one audit log event
+type AuditEventResponse struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	EventType   string            `json:"event_type"`
+	Description string            `json:"description"`
+	AccountID   *uuid.UUID        `json:"account_id,omitempty"`
+	APIKeyID    *uuid.UUID        `json:"api_key_id,omitempty"`
+	APIKeyName  *string           `json:"api_key_name,omitempty"`
+	IPAddress   string            `json:"ip_address"`
+	UserAgent   string            `json:"user_agent"`
+	Success     bool              `json:"success"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+// ListAuditEventsResponse represents a list audit events response
+type ListAuditEventsResponse struct {
+	Events []AuditEventResponse `json:"events"`
+	Since  time.Time            `json:"since"`
+	Until  time.Time            `json:"until"`
+}
+
+// AccountResponse represents account details in responses
+type AccountResponse struct {
+	AccountID    uuid.UUID `json:"account_id"`
+	OwnerID      uuid.UUID `json:"owner_id"`
+	Name         string    `json:"name"`
+	Status       string    `json:"status"`
+	WebhookURL   *string   `json:"webhook_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int       `json:"version"`
+	KeyPrefix    string    `json:"key_prefix,omitempty"`
+	KeysDisabled bool      `json:"keys_disabled"`
+	// RequireClientCert means the account's keys only work with an allowlisted client certificate
+	RequireClientCert      bool     `json:"require_client_cert"`
+	ClientCertFingerprints []string `json:"client_cert_fingerprints,omitempty"`
+	// AllowedPermissions caps the permissions of keys issued for the account; omitted when uncapped
+	AllowedPermissions []string `json:"allowed_permissions,omitempty"`
+}
+
+// SetAllowedPermissionsRequest represents a request to replace an account's
+// permission cap. An empty list removes the cap.
+type SetAllowedPermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// Validate validates the allowed permissions request
+func (r *SetAllowedPermissionsRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.Permissions == nil {
+		errs.Add("permissions", "permissions is required; send an empty list to remove the cap")
+	}
+
+	return errs.Err()
+}
+
+// ListAccountsResponse represents a list accounts response
+type ListAccountsResponse struct {
+	Accounts []AccountResponse `json:"accounts"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+	Total    int               `json:"total"`
+}
+
+// AccessibleAccountsResponse represents the accounts the authenticated key can act on
+type AccessibleAccountsResponse struct {
+	// Scope is "all" for keys with admin:accounts and "own" otherwise
+	Scope string `json:"scope"`
+	ListAccountsResponse
+}
+
+// AccountStatsResponse represents aggregate usage stats for an account
+type AccountStatsResponse struct {
+	AccountID           uuid.UUID  `json:"account_id"`
+	TotalKeys           int        `json:"total_keys"`
+	ActiveKeys          int        `json:"active_keys"`
+	LastAuthenticatedAt *time.Time `json:"last_authenticated_at,omitempty"`
+	RequestsLast24h     int        `json:"requests_last_24h"`
+	GeneratedAt         time.Time  `json:"generated_at"`
+}
+
+// ApiKeyCountResponse represents an account's live API key count
+type ApiKeyCountResponse struct {
+	AccountID  uuid.UUID `json:"account_id"`
+	ActiveKeys int       `json:"active_keys"`
+}
+
+// RevokeAPIKeysBatchRequest represents a request to revoke specific API keys
+type RevokeAPIKeysBatchRequest struct {
+	// APIKeyIDs holds at most domain.MaxRevokeBatchSize IDs; checked by Validate
+	APIKeyIDs []string `json:"api_key_ids" validate:"required"`
+}
+
+// Validate validates the batch revocation request
+func (r *RevokeAPIKeysBatchRequest) Validate() error {
+	var errs ValidationErrors
+
+	switch {
+	case len(r.APIKeyIDs) == 0:
+		errs.Add("api_key_ids", "api_key_ids is required")
+	case len(r.APIKeyIDs) > domain.MaxRevokeBatchSize:
+		errs.Add("api_key_ids", fmt.Sprintf("at most %d API key IDs can be revoked at once", domain.MaxRevokeBatchSize))
+	}
+	for i, id := range r.APIKeyIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			errs.Add(fmt.Sprintf("api_key_ids[%d]", i), fmt.Sprintf("'%s' is not a valid API key ID", id))
+		}
+	}
+
+	return errs.Err()
+}
+
+// RevokeAPIKeyResult represents the outcome of revoking one key of a batch
+type RevokeAPIKeyResult struct {
+	APIKeyID uuid.UUID `json:"api_key_id"`
+	Revoked  bool      `json:"revoked"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// RevokeAPIKeysBatchResponse represents a batch revocation response
+type RevokeAPIKeysBatchResponse struct {
+	Results []RevokeAPIKeyResult `json:"results"`
+	Revoked int                  `json:"revoked"`
+	Failed  int                  `json:"failed"`
+}
+
+// ImportAccountRequest represents one account in a bulk import
+type ImportAccountRequest struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status,omitempty"`
+	WebhookURL *string    `json:"webhook_url,omitempty"`
+	OwnerID    *uuid.UUID `json:"owner_id,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+// ImportAccountResult represents the outcome of importing one account
+type ImportAccountResult struct {
+	Index     int       `json:"index"`
+	AccountID uuid.UUID `json:"account_id"`
+	Name      string    `json:"name"`
+	Created   bool      `json:"created"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ImportAccountsResponse represents a bulk import response
+type ImportAccountsResponse struct {
+	Results []ImportAccountResult `json:"results"`
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+}
+
+// PurgeAccountDataResponse reports what an account data purge removed
+type PurgeAccountDataResponse struct {
+	AccountID              uuid.UUID `json:"account_id"`
+	APIKeysDeleted         int       `json:"api_keys_deleted"`
+	IdempotencyKeysDeleted int       `json:"idempotency_keys_deleted"`
+	AuditEventsDeleted     int       `json:"audit_events_deleted"`
+	// AuditEventsPurged is false when audit events were kept
+	AuditEventsPurged bool      `json:"audit_events_purged"`
+	PurgedAt          time.Time `json:"purged_at"`
+}
+
+// UpdateAccountRequest represents an account update request.
+// An empty webhook_url clears the webhook and an empty key_prefix restores the default prefix.
+// client_cert_fingerprints replaces the whole allowlist.
+type UpdateAccountRequest struct {
+	WebhookURL             *string   `json:"webhook_url"`
+	KeyPrefix              *string   `json:"key_prefix"`
+	RequireClientCert      *bool     `json:"require_client_cert"`
+	ClientCertFingerprints *[]string `json:"client_cert_fingerprints"`
+}
+
+// Validate validates the account update request
+func (r *UpdateAccountRequest) Validate() error {
+	var errs ValidationErrors
+
+	if r.WebhookURL == nil && r.KeyPrefix == nil && r.RequireClientCert == nil && r.ClientCertFingerprints == nil {
+		errs.Add("webhook_url", "webhook_url, key_prefix, require_client_cert or client_cert_fingerprints is required")
+	}
+
+	return errs.Err()
+}
+
+// AccountExportResponse represents a full export of an account's API key inventory.
+// It only carries key metadata; secrets and key hashes are never included.
+type AccountExportResponse struct {
+	Account    AccountResponse  `json:"account"`
+	APIKeys    []ApiKeyResponse `json:"api_keys"`
+	Total      int              `json:"total"`
+	ExportedAt time.Time        `json:"exported_at"`
+}
+
 // HealthResponse represents a health check response
 type HealthResponse struct {
 	Status    string    `json:"status"`
 	Timestamp time.Time `json:"timestamp"`
 	Service   string    `json:"service"`
 	Version   string    `json:"version"`
+	// Checks reports dependencies that can degrade the instance without making it unready
+	Checks map[string]ComponentHealth `json:"checks,omitempty"`
+}
+
+// EffectiveConfigResponse represents the sanitized configuration of the instance
+type EffectiveConfigResponse struct {
+	// Config is keyed by lowercased environment variable name
+	Config   map[string]interface{} `json:"config"`
+	LoadedAt time.Time              `json:"loaded_at"`
+}
+
+// ComponentHealth describes the state of one dependency in a readiness response
+type ComponentHealth struct {
+	Status            string     `json:"status"`
+	RecentWriteErrors int        `json:"recent_write_errors"`
+	LastWriteError    *time.Time `json:"last_write_error,omitempty"`
+	// Spilled counts audit events kept locally until the audit table accepts them
+	Spilled int `json:"spilled,omitempty"`
 }
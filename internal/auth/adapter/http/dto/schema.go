@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JSONSchemaDialect is the JSON Schema version request schemas are written in
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// requestSchemaTypes are the request bodies published as JSON Schemas, by name
+var requestSchemaTypes = map[string]interface{}{
+	"register-app":  RegisterAppRequest{},
+	"issue-api-key": IssueApiKeyRequest{},
+}
+
+// RequestSchemaNames returns the names RequestSchema accepts, sorted
+func RequestSchemaNames() []string {
+	names := make([]string, 0, len(requestSchemaTypes))
+	for name := range requestSchemaTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RequestSchema generates the JSON Schema of a published request body from its
+// json and validate struct tags, so the schema cannot drift from the DTO. Rules
+// only enforced in Validate (e.g. the external_id character set) are not included.
+func RequestSchema(name string) (map[string]interface{}, bool) {
+	v, ok := requestSchemaTypes[name]
+	if !ok {
+		return nil, false
+	}
+
+	t := reflect.TypeOf(v)
+	schema := structSchema(t)
+	schema["$schema"] = JSONSchemaDialect
+	schema["title"] = t.Name()
+	return schema, true
+}
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// structSchema describes a struct as an object with one property per json field
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		property := typeSchema(field.Type)
+		applyRules(property, field.Type, rules)
+		properties[name] = property
+
+		if len(rules) > 0 && rules[0] == "required" {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// typeSchema maps a Go type onto its JSON Schema type
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	return map[string]interface{}{}
+}
+
+// applyRules translates validate tag rules into schema keywords. Rules after
+// "dive" apply to the items of a slice.
+func applyRules(schema map[string]interface{}, t reflect.Type, rules []string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i, rule := range rules {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			if items, ok := schema["items"].(map[string]interface{}); ok {
+				applyRules(items, t.Elem(), rules[i+1:])
+			}
+			return
+		case "url":
+			schema["format"] = "uri"
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			schema[boundKeyword(t, key)] = n
+		}
+	}
+}
+
+// boundKeyword returns the keyword a min or max rule becomes for the type:
+// a length for strings, a size for arrays and a value bound for numbers
+func boundKeyword(t reflect.Type, rule string) string {
+	suffix := "imum"
+	switch t.Kind() {
+	case reflect.String:
+		suffix = "Length"
+	case reflect.Slice, reflect.Array:
+		suffix = "Items"
+	}
+	if rule == "min" {
+		if suffix == "imum" {
+			return "minimum"
+		}
+		return "min" + suffix
+	}
+	if suffix == "imum" {
+		return "maximum"
+	}
+	return "max" + suffix
+}
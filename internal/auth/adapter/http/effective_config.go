@@ -0,0 +1,27 @@
+package http
+
+import (
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EffectiveConfig serves the service's configuration as loaded at startup, for
+// diagnosing misconfigured deployments. The caller builds settings and is
+// responsible for redacting secrets; it is returned as is.
+// @Summary Get the effective configuration
+// @Description Return the configuration the instance is running with, keyed by lowercased environment variable name. Secrets are redacted.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} dto.EffectiveConfigResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /api/v1/auth/admin/config [get]
+func EffectiveConfig(settings map[string]interface{}, loadedAt time.Time) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusOK).JSON(dto.EffectiveConfigResponse{
+			Config:   settings,
+			LoadedAt: loadedAt,
+		})
+	}
+}
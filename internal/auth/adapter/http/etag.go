@@ -0,0 +1,58 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// formatETag renders a record version as a strong ETag
+func formatETag(version int) string {
+	return fmt.Sprintf("\"%d\"", version)
+}
+
+// parseIfMatch extracts the expected version from an If-Match header.
+// It returns nil when the header is absent or "*" (no precondition).
+func parseIfMatch(header string) (*int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), "\"")
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("malformed If-Match header: %s", header)
+	}
+
+	return &version, nil
+}
+
+// ifMatchVersion reads the expected version from the request's If-Match header.
+// A malformed header yields the 412 response to send instead.
+func ifMatchVersion(c *fiber.Ctx) (*int, *dto.ErrorResponse) {
+	expectedVersion, err := parseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return nil, &dto.ErrorResponse{
+			Error:   domain.ErrCodePreconditionFailed,
+			Message: "If-Match must be an ETag returned by this API",
+			Details: err.Error(),
+		}
+	}
+
+	return expectedVersion, nil
+}
+
+// versionConflictCode picks the error for a write that lost a version check:
+// 412 when the caller sent If-Match, otherwise 409 since the caller set no
+// precondition and the write merely raced another one
+func versionConflictCode(expectedVersion *int) domain.ErrorCode {
+	if expectedVersion != nil {
+		return domain.ErrCodePreconditionFailed
+	}
+	return domain.ErrCodeVersionConflict
+}
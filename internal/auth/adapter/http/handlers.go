@@ -1,9 +1,11 @@
 package http
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"log"
 	"time"
 
 	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
@@ -19,9 +21,17 @@ type AuthHandler struct {
 	registerApp    *usecase.RegisterApp
 	issueApiKey    *usecase.IssueApiKey
 	validateApiKey *usecase.ValidateApiKey
+	validatePair   *usecase.ValidateApiKeyPair
 	getAPIKeys     *usecase.GetAPIKeys
+	listRevoked    *usecase.ListRevokedApiKeys
+	listActive     *usecase.ListActiveApiKeys
 	revokeApiKey   *usecase.RevokeApiKey
+	revokeBatch    *usecase.RevokeApiKeys
+	exportAccount  *usecase.ExportAccount
 	auditLogger    audit.AuditLoggerInterface
+	auditDetail    AuditDetailConfig
+	validateView   ValidateResponseConfig
+	pagination     PaginationConfig
 }
 
 // NewAuthHandler creates a new AuthHandler
@@ -29,20 +39,116 @@ func NewAuthHandler(
 	registerApp *usecase.RegisterApp,
 	issueApiKey *usecase.IssueApiKey,
 	validateApiKey *usecase.ValidateApiKey,
+	validatePair *usecase.ValidateApiKeyPair,
 	getAPIKeys *usecase.GetAPIKeys,
+	listRevoked *usecase.ListRevokedApiKeys,
+	listActive *usecase.ListActiveApiKeys,
 	revokeApiKey *usecase.RevokeApiKey,
+	revokeBatch *usecase.RevokeApiKeys,
+	exportAccount *usecase.ExportAccount,
 	auditLogger audit.AuditLoggerInterface,
+	auditDetail AuditDetailConfig,
+	validateView ValidateResponseConfig,
+	pagination PaginationConfig,
 ) *AuthHandler {
 	return &AuthHandler{
 		registerApp:    registerApp,
 		issueApiKey:    issueApiKey,
 		validateApiKey: validateApiKey,
+		validatePair:   validatePair,
 		getAPIKeys:     getAPIKeys,
+		listRevoked:    listRevoked,
+		listActive:     listActive,
 		revokeApiKey:   revokeApiKey,
+		revokeBatch:    revokeBatch,
+		exportAccount:  exportAccount,
 		auditLogger:    auditLogger,
+		auditDetail:    auditDetail,
+		validateView:   validateView,
+		pagination:     pagination,
 	}
 }
 
+// validationErrorResponse builds a validation error response listing every failed field
+func validationErrorResponse(err error) dto.ErrorResponse {
+	response := dto.ErrorResponse{
+		Error:   domain.ErrCodeValidationError,
+		Message: "Invalid request data",
+		Details: err.Error(),
+	}
+
+	var fieldErrs dto.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		response.Fields = fieldErrs
+	}
+
+	return response
+}
+
+// toApiKeyResponse converts a domain API key to its metadata-only response format
+func toApiKeyResponse(apiKey *domain.ApiKey) dto.ApiKeyResponse {
+	return dto.ApiKeyResponse{
+		APIKeyID:       apiKey.ID,
+		Name:           apiKey.Name,
+		Permissions:    []string(apiKey.Permissions),
+		Status:         string(apiKey.Status),
+		LastUsedAt:     apiKey.LastUsedAt,
+		ExpiresAt:      apiKey.ExpiresAt,
+		CreatedAt:      apiKey.CreatedAt,
+		Version:        apiKey.Version,
+		ExternalID:     apiKey.ExternalID,
+		KeyHint:        apiKey.KeyHint,
+		RevokedAt:      apiKey.RevokedAt,
+		ResourceScopes: apiKey.ResourceScopes,
+	}
+}
+
+// invalidPermissionsResponse builds a validation error naming each unknown permission
+func invalidPermissionsResponse(err error, permissions []string) dto.ErrorResponse {
+	var fieldErrs dto.ValidationErrors
+	for i, perm := range permissions {
+		if !domain.IsValidPermission(perm) {
+			fieldErrs.Add(fmt.Sprintf("permissions[%d]", i), fmt.Sprintf("unknown permission '%s'", perm))
+		}
+	}
+
+	return dto.ErrorResponse{
+		Error:   domain.ErrCodeValidationError,
+		Message: "Invalid permissions",
+		Details: err.Error(),
+		Fields:  fieldErrs,
+	}
+}
+
+// grantErrorResponse checks that the caller may grant every requested
+// permission and returns the 403 to send when it may not. Holders of admin:keys
+// may grant anything; other callers never admin:keys, admin:accounts or
+// read:audit, and otherwise only permissions their own key holds, so a key
+// cannot mint or upgrade a key past itself.
+func grantErrorResponse(c *fiber.Ctx, requested domain.ApiKeyPermissions) *dto.ErrorResponse {
+	if HasPermission(c, domain.PermissionAdminKeys) {
+		return nil
+	}
+
+	callerPermissions, _ := GetPermissions(c)
+	for _, perm := range requested.Expand() {
+		switch perm {
+		case domain.PermissionAdminKeys, domain.PermissionAdminAccounts, domain.PermissionReadAudit:
+			return &dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: fmt.Sprintf("Permission '%s' is required to grant '%s'", domain.PermissionAdminKeys, perm),
+			}
+		}
+		if !(domain.ApiKeyPermissions{perm}).IsSubsetOf(callerPermissions) {
+			return &dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: fmt.Sprintf("Cannot grant permission '%s' the calling API key does not hold", perm),
+			}
+		}
+	}
+	return nil
+}
+
 // RegisterApp handles account registration
 // @Summary Register a new application
 // @Description Register a new application account in the system
@@ -50,7 +156,10 @@ func NewAuthHandler(
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterAppRequest true "Registration request"
+// @Param upsert query bool false "Return the existing active account with this name in the caller's tenant instead of 409"
 // @Success 201 {object} dto.RegisterAppResponse
+// @Header 201 {string} Location "/api/v1/auth/accounts/{account_id}"
+// @Success 200 {object} dto.RegisterAppResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -60,8 +169,8 @@ func (h *AuthHandler) RegisterApp(c *fiber.Ctx) error {
 
 	var req dto.RegisterAppRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "invalid_request",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
 			Message: "Failed to parse request body",
 			Details: err.Error(),
 		})
@@ -69,17 +178,27 @@ func (h *AuthHandler) RegisterApp(c *fiber.Ctx) error {
 
 	// Validate request
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: "Invalid request data",
-			Details: err.Error(),
-		})
+		return RespondErrorWith(c, validationErrorResponse(err))
 	}
 
 	// Convert to use case input
 	input := usecase.RegisterAppInput{
-		Name:       req.Name,
-		WebhookURL: req.WebhookURL,
+		Name:           req.Name,
+		WebhookURL:     req.WebhookURL,
+		KeyPrefix:      req.KeyPrefix,
+		ChallengeToken: c.Get("X-Registration-Challenge"),
+		Upsert:         c.QueryBool("upsert"),
+	}
+
+	// Authenticated callers register sub-accounts in their own tenant
+	if callerAccountID, err := GetAccountID(c); err == nil {
+		if !HasPermission(c, domain.PermissionWriteAccounts) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: "Permission 'write:accounts' is required to register accounts in your tenant",
+			})
+		}
+		input.CallerAccountID = &callerAccountID
 	}
 
 	// Execute use case
@@ -91,44 +210,94 @@ func (h *AuthHandler) RegisterApp(c *fiber.Ctx) error {
 			nil,
 			&req.Name,
 			c.IP(), c.Get("User-Agent"),
-			map[string]string{
+			h.auditDetail.details(c, map[string]string{
 				"error":   err.Error(),
 				"name":    req.Name,
 				"success": "false",
-			},
+			}),
 		)
 
-		if err.Error() == fmt.Sprintf("app with name '%s' already exists", req.Name) {
-			return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
-				Error:   "account_exists",
-				Message: "Account with this name already exists",
+		if errors.Is(err, domain.ErrChallengeRequired) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeChallengeRequired,
+				Message: "A registration challenge response is required",
+			})
+		}
+
+		if errors.Is(err, domain.ErrChallengeFailed) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeChallengeFailed,
+				Message: "Registration challenge verification failed",
+				Details: err.Error(),
+			})
+		}
+
+		if errors.Is(err, domain.ErrInvalidName) {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("name", domain.ErrInvalidName.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+
+		if errors.Is(err, domain.ErrNameNotAllowed) {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("name", domain.ErrNameNotAllowed.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+
+		if errors.Is(err, domain.ErrInvalidKeyPrefix) {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("key_prefix", domain.ErrInvalidKeyPrefix.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+
+		if errors.Is(err, domain.ErrInvalidWebhookURL) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeValidationError,
+				Message: "Invalid request data",
+				Details: err.Error(),
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		if err.Error() == fmt.Sprintf("app with name '%s' already exists", req.Name) {
+			return RespondError(c, domain.ErrCodeAccountExists)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
 			Message: "Failed to register account",
 			Details: err.Error(),
 		})
 	}
 
-	// Log successful account creation
-	h.auditLogger.LogAccountCreation(
-		ctx,
-		&output.AccountID,
-		&output.Name,
-		c.IP(), c.Get("User-Agent"),
-		map[string]string{"success": "true"},
-	)
-
 	// Convert to response
 	response := dto.RegisterAppResponse{
 		AccountID: output.AccountID,
+		OwnerID:   output.OwnerID,
 		Name:      output.Name,
 		Status:    output.Status,
 		CreatedAt: output.CreatedAt,
+		KeyPrefix: output.KeyPrefix,
 	}
 
+	// An upsert that matched an existing account created nothing
+	if !output.Created {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	// Log successful account creation
+	h.auditLogger.LogAccountCreation(
+		ctx,
+		&output.AccountID,
+		&output.Name,
+		c.IP(), c.Get("User-Agent"),
+		h.auditDetail.details(c, map[string]string{"success": "true"}),
+	)
+
+	c.Location(fmt.Sprintf("/api/v1/auth/accounts/%s", output.AccountID))
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -140,6 +309,7 @@ func (h *AuthHandler) RegisterApp(c *fiber.Ctx) error {
 // @Produce json
 // @Param request body dto.IssueApiKeyRequest true "API key issuance request"
 // @Success 201 {object} dto.IssueApiKeyResponse
+// @Header 201 {string} Location "/api/v1/auth/api-keys/{api_key_id}"
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -149,8 +319,8 @@ func (h *AuthHandler) IssueApiKey(c *fiber.Ctx) error {
 
 	var req dto.IssueApiKeyRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "invalid_request",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
 			Message: "Failed to parse request body",
 			Details: err.Error(),
 		})
@@ -158,19 +328,56 @@ func (h *AuthHandler) IssueApiKey(c *fiber.Ctx) error {
 
 	// Validate request
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: "Invalid request data",
-			Details: err.Error(),
-		})
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	// Resolve permission aliases before the grant checks below see the names
+	req.Permissions = h.issueApiKey.CanonicalPermissions(req.Permissions)
+
+	// When the route is authenticated, callers may only issue keys for their own
+	// account unless they hold admin:keys
+	if callerAccountID, err := GetAccountID(c); err == nil {
+		isAdmin := HasPermission(c, domain.PermissionAdminKeys)
+		if callerAccountID != req.AccountID && !isAdmin {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: "Cannot issue API keys for another account",
+			})
+		}
+
+		// A caller cannot issue a key with more than its own key holds
+		if errResp := grantErrorResponse(c, req.Permissions); errResp != nil {
+			return RespondErrorWith(c, *errResp)
+		}
+
+		// A scoped caller cannot reach past its scopes through the keys it issues;
+		// keys it issues without scopes inherit the caller's
+		if !InResourceScope(c, req.AccountID.String()) {
+			return RespondError(c, domain.ErrCodeResourceOutOfScope)
+		}
+		if callerScopes := GetResourceScopes(c); len(callerScopes) > 0 {
+			if len(req.ResourceScopes) == 0 {
+				req.ResourceScopes = callerScopes
+			}
+			for _, scope := range req.ResourceScopes {
+				if !domain.ResourceInScope(callerScopes, domain.CanonicalResourceScope(scope)) {
+					return RespondErrorWith(c, dto.ErrorResponse{
+						Error:   domain.ErrCodeResourceOutOfScope,
+						Message: fmt.Sprintf("Cannot scope a key to '%s', which is outside the caller's scopes", scope),
+					})
+				}
+			}
+		}
 	}
 
 	// Convert to use case input
 	input := usecase.IssueApiKeyInput{
-		AccountID:   req.AccountID,
-		Name:        req.Name,
-		Permissions: domain.ApiKeyPermissions(req.Permissions),
-		ExpiresIn:   req.ExpiresIn,
+		AccountID:      req.AccountID,
+		Name:           req.Name,
+		Permissions:    domain.ApiKeyPermissions(req.Permissions),
+		ExpiresIn:      req.ExpiresIn,
+		ExternalID:     req.ExternalID,
+		ResourceScopes: req.ResourceScopes,
 	}
 
 	// Execute use case
@@ -183,21 +390,50 @@ func (h *AuthHandler) IssueApiKey(c *fiber.Ctx) error {
 			nil,
 			&req.Name,
 			c.IP(), c.Get("User-Agent"),
-			map[string]string{
+			h.auditDetail.details(c, map[string]string{
 				"error":   err.Error(),
 				"success": "false",
-			},
+			}),
 		)
 
 		if err.Error() == "account not found or inactive" {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "account_not_found",
-				Message: "Account not found or inactive",
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+		if errors.Is(err, domain.ErrInvalidPermission) {
+			return RespondErrorWith(c, invalidPermissionsResponse(err, req.Permissions))
+		}
+		if errors.Is(err, domain.ErrInvalidName) {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("name", domain.ErrInvalidName.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+		if errors.Is(err, domain.ErrExternalIDExists) {
+			return RespondError(c, domain.ErrCodeExternalIDExists)
+		}
+		if errors.Is(err, domain.ErrKeyNameExists) {
+			return RespondError(c, domain.ErrCodeKeyNameExists)
+		}
+		if errors.Is(err, domain.ErrKeyQuotaExceeded) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeKeyQuotaExceeded,
+				Message: "The account has reached its maximum number of API keys; revoke one before issuing another",
+			})
+		}
+		if errors.Is(err, domain.ErrPermissionsRequired) {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("permissions", domain.ErrPermissionsRequired.Error())
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+		if errors.Is(err, domain.ErrPermissionOutsideAccountScope) {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInsufficientPermissions,
+				Message: "The account is not allowed to issue keys with these permissions",
+				Details: err.Error(),
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
 			Message: "Failed to issue API key",
 			Details: err.Error(),
 		})
@@ -210,21 +446,24 @@ func (h *AuthHandler) IssueApiKey(c *fiber.Ctx) error {
 		&output.APIKeyID,
 		&output.Name,
 		c.IP(), c.Get("User-Agent"),
-		map[string]string{"success": "true"},
+		h.auditDetail.details(c, map[string]string{"success": "true"}),
 	)
 
 	// Convert to response
 	response := dto.IssueApiKeyResponse{
-		APIKeyID:    output.APIKeyID,
-		KeyHash:     output.KeyHash,
-		AccountID:   output.AccountID,
-		Name:        output.Name,
-		Permissions: []string(output.Permissions),
-		Status:      output.Status,
-		ExpiresAt:   output.ExpiresAt,
-		CreatedAt:   output.CreatedAt,
+		APIKeyID:       output.APIKeyID,
+		KeyHash:        output.KeyHash,
+		AccountID:      output.AccountID,
+		Name:           output.Name,
+		Permissions:    []string(output.Permissions),
+		Status:         output.Status,
+		ExpiresAt:      output.ExpiresAt,
+		CreatedAt:      output.CreatedAt,
+		ExternalID:     output.ExternalID,
+		ResourceScopes: output.ResourceScopes,
 	}
 
+	c.Location(fmt.Sprintf("/api/v1/auth/api-keys/%s", output.APIKeyID))
 	return c.Status(fiber.StatusCreated).JSON(response)
 }
 
@@ -245,8 +484,8 @@ func (h *AuthHandler) ValidateApiKey(c *fiber.Ctx) error {
 
 	var req dto.ValidateApiKeyRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "invalid_request",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
 			Message: "Failed to parse request body",
 			Details: err.Error(),
 		})
@@ -254,37 +493,102 @@ func (h *AuthHandler) ValidateApiKey(c *fiber.Ctx) error {
 
 	// Validate request
 	if err := req.Validate(); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: "Invalid request data",
-			Details: err.Error(),
-		})
+		return RespondErrorWith(c, validationErrorResponse(err))
 	}
 
 	// Convert to use case input
 	input := usecase.ValidateApiKeyInput{
-		KeyHash: req.KeyHash,
+		KeyHash:               req.KeyHash,
+		ClientCertFingerprint: req.ClientCertFingerprint,
 	}
 
 	// Execute use case
 	output, err := h.validateApiKey.Execute(ctx, input)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
 			Message: "Failed to validate API key",
 			Details: err.Error(),
 		})
 	}
 
+	// Answer as if the key had never existed
+	if h.validateView.hidesKey(output.Valid, output.ExpiresAt) {
+		output = &usecase.ValidateApiKeyOutput{}
+	}
+
 	// Convert to response
 	response := dto.ValidateApiKeyResponse{
-		Valid:       output.Valid,
-		AccountID:   output.AccountID,
-		APIKeyID:    output.APIKeyID,
-		Name:        output.Name,
-		Permissions: []string(output.Permissions),
-		LastUsedAt:  output.LastUsedAt,
-		ExpiresAt:   output.ExpiresAt,
+		Valid:              output.Valid,
+		AccountID:          output.AccountID,
+		APIKeyID:           output.APIKeyID,
+		Name:               output.Name,
+		LastUsedAt:         output.LastUsedAt,
+		ExpiresAt:          output.ExpiresAt,
+		Restricted:         output.Restricted,
+		InGracePeriod:      output.InGracePeriod,
+		KeysDisabled:       output.KeysDisabled,
+		ResourceScopes:     output.ResourceScopes,
+		ClientCertRejected: output.ClientCertRejected,
+	}
+	response.Permissions, response.PermissionChecks = h.validateView.permissionView(output.Permissions)
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// ValidateApiKeyPair handles validating an account ID and API key together
+// @Summary Validate an account and API key pair
+// @Description Valid only if the key validates and belongs to the given account. Use it to catch key/account misconfigurations early.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ValidateApiKeyPairRequest true "Account and key pair"
+// @Success 200 {object} dto.ValidateApiKeyPairResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/validate-pair [post]
+func (h *AuthHandler) ValidateApiKeyPair(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	var req dto.ValidateApiKeyPairRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Failed to parse request body",
+			Details: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	// Execute use case
+	output, err := h.validatePair.Execute(ctx, usecase.ValidateApiKeyPairInput{
+		AccountID:             req.AccountID,
+		RawKey:                req.RawKey,
+		ClientCertFingerprint: req.ClientCertFingerprint,
+	})
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to validate API key",
+			Details: err.Error(),
+		})
+	}
+
+	// Convert to response
+	response := dto.ValidateApiKeyPairResponse{
+		Valid:  output.Valid,
+		Reason: output.Reason,
+	}
+	if output.Key != nil {
+		response.AccountID = output.Key.AccountID
+		response.APIKeyID = output.Key.APIKeyID
+		response.Name = output.Key.Name
+		response.Permissions, response.PermissionChecks = h.validateView.permissionView(output.Key.Permissions)
+		response.ExpiresAt = output.Key.ExpiresAt
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -292,9 +596,11 @@ func (h *AuthHandler) ValidateApiKey(c *fiber.Ctx) error {
 
 // GetAPIKeys handles getting API keys for an account
 // @Summary Get API keys for an account
-// @Description Retrieve all API keys for a specific account with pagination
+// @Description Retrieve all API keys for a specific account with pagination.
+// @Description Send Accept: application/x-ndjson to stream every key instead, one JSON object per line.
 // @Tags auth
 // @Produce json
+// @Produce application/x-ndjson
 // @Param account_id path string true "Account ID"
 // @Param limit query int false "Limit number of results" default(10)
 // @Param offset query int false "Offset for pagination" default(0)
@@ -310,23 +616,17 @@ func (h *AuthHandler) GetAPIKeys(c *fiber.Ctx) error {
 	accountIDStr := c.Params("account_id")
 	accountID, err := uuid.Parse(accountIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "invalid_account_id",
-			Message: "Invalid account ID format",
-		})
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
 	}
 
-	// Parse pagination parameters
-	limitStr := c.Query("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 || limit > 100 {
-		limit = 10 // Default limit
+	if c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationNDJSON) == MIMEApplicationNDJSON {
+		return h.streamAPIKeys(c, accountID)
 	}
 
-	offsetStr := c.Query("offset", "0")
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0 // Default offset
+	// Parse pagination parameters
+	limit, offset, err := parsePagination(c, h.pagination)
+	if err != nil {
+		return RespondErrorWith(c, invalidPaginationResponse(err))
 	}
 
 	// Convert to use case input
@@ -340,14 +640,11 @@ func (h *AuthHandler) GetAPIKeys(c *fiber.Ctx) error {
 	output, err := h.getAPIKeys.Execute(ctx, input)
 	if err != nil {
 		if err.Error() == "account not found or inactive" {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "account_not_found",
-				Message: "Account not found or inactive",
-			})
+			return RespondError(c, domain.ErrCodeAccountNotFound)
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
 			Message: "Failed to get API keys",
 			Details: err.Error(),
 		})
@@ -356,15 +653,7 @@ func (h *AuthHandler) GetAPIKeys(c *fiber.Ctx) error {
 	// Convert API keys to response format
 	apiKeys := make([]dto.ApiKeyResponse, len(output.APIKeys))
 	for i, apiKey := range output.APIKeys {
-		apiKeys[i] = dto.ApiKeyResponse{
-			APIKeyID:    apiKey.ID,
-			Name:        apiKey.Name,
-			Permissions: []string(apiKey.Permissions),
-			Status:      string(apiKey.Status),
-			LastUsedAt:  apiKey.LastUsedAt,
-			ExpiresAt:   apiKey.ExpiresAt,
-			CreatedAt:   apiKey.CreatedAt,
-		}
+		apiKeys[i] = toApiKeyResponse(apiKey)
 	}
 
 	// Create response
@@ -378,14 +667,238 @@ func (h *AuthHandler) GetAPIKeys(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
+// ListRevokedAPIKeys handles listing an account's recently revoked API keys
+// @Summary List recently revoked API keys
+// @Description List the caller's keys revoked after since, most recently revoked first. Keys revoked before revocation times were recorded are not included.
+// @Tags auth
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Param since query string false "RFC 3339 start of the window (default: 7 days ago)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} dto.ListRevokedAPIKeysResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/api-keys/revoked [get]
+func (h *AuthHandler) ListRevokedAPIKeys(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Callers may only review their own account's revocations
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: "Cannot view another account's revoked keys",
+		})
+	}
+
+	since := time.Now().UTC().Add(-usecase.DefaultRevokedKeysWindow)
+	if raw := c.Query("since"); raw != "" {
+		since, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("since", fmt.Sprintf("since must be an RFC 3339 timestamp, got '%s'", raw))
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+	}
+
+	// Parse pagination parameters
+	limit, offset, err := parsePagination(c, h.pagination)
+	if err != nil {
+		return RespondErrorWith(c, invalidPaginationResponse(err))
+	}
+
+	// Execute use case
+	output, err := h.listRevoked.Execute(ctx, usecase.ListRevokedApiKeysInput{
+		AccountID: accountID,
+		Since:     since,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to list revoked API keys",
+			Details: err.Error(),
+		})
+	}
+
+	apiKeys := make([]dto.ApiKeyResponse, len(output.APIKeys))
+	for i, apiKey := range output.APIKeys {
+		apiKeys[i] = toApiKeyResponse(apiKey)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ListRevokedAPIKeysResponse{
+		APIKeys: apiKeys,
+		Since:   since,
+		Limit:   output.Limit,
+		Offset:  output.Offset,
+		Total:   output.Total,
+	})
+}
+
+// ListActiveAPIKeys handles listing an account's recently used API keys
+// @Summary List recently used API keys
+// @Description List the caller's keys last used within the window, most recently used first. Intended for a "currently active integrations" view.
+// @Tags auth
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Param within query string false "Duration such as 1h or 30m (default: 24h)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} dto.ListActiveAPIKeysResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/active-keys [get]
+func (h *AuthHandler) ListActiveAPIKeys(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountID, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Callers may only view their own account's activity
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: "Cannot view another account's active keys",
+		})
+	}
+
+	within := usecase.DefaultActiveKeysWindow
+	if raw := c.Query("within"); raw != "" {
+		within, err = time.ParseDuration(raw)
+		if err != nil || within <= 0 {
+			var fieldErrs dto.ValidationErrors
+			fieldErrs.Add("within", fmt.Sprintf("within must be a positive duration such as 1h or 30m, got '%s'", raw))
+			return RespondErrorWith(c, validationErrorResponse(fieldErrs.Err()))
+		}
+	}
+
+	// Parse pagination parameters
+	limit, offset, err := parsePagination(c, h.pagination)
+	if err != nil {
+		return RespondErrorWith(c, invalidPaginationResponse(err))
+	}
+
+	// Execute use case
+	output, err := h.listActive.Execute(ctx, usecase.ListActiveApiKeysInput{
+		AccountID: accountID,
+		Within:    within,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to list active API keys",
+			Details: err.Error(),
+		})
+	}
+
+	apiKeys := make([]dto.ApiKeyResponse, len(output.APIKeys))
+	for i, apiKey := range output.APIKeys {
+		apiKeys[i] = toApiKeyResponse(apiKey)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ListActiveAPIKeysResponse{
+		APIKeys: apiKeys,
+		Within:  within.String(),
+		Since:   output.Since,
+		Limit:   output.Limit,
+		Offset:  output.Offset,
+		Total:   output.Total,
+	})
+}
+
+// streamAPIKeys writes every API key of the account as NDJSON. Pagination
+// parameters are ignored; keys are read from the store a page at a time and
+// flushed as they arrive, so the full list is never held in memory.
+func (h *AuthHandler) streamAPIKeys(c *fiber.Ctx, accountID uuid.UUID) error {
+	ctx := context.Background()
+
+	// Check the account up front: once streaming starts the status is already sent
+	if err := h.getAPIKeys.CheckAccount(ctx, accountID); err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get API keys",
+			Details: err.Error(),
+		})
+	}
+
+	// Encode with the app's encoder so lines follow the configured field naming
+	encode := c.App().Config().JSONEncoder
+
+	c.Set(fiber.HeaderContentType, MIMEApplicationNDJSON)
+	c.Status(fiber.StatusOK).Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		err := h.getAPIKeys.Stream(ctx, accountID, func(apiKey *domain.ApiKey) error {
+			line, err := encode(toApiKeyResponse(apiKey))
+			if err != nil {
+				return err
+			}
+			w.Write(line)
+			w.WriteByte('\n')
+			return w.Flush()
+		})
+		if err != nil {
+			// Too late to change the status; the client sees a truncated stream
+			log.Printf("failed to stream API keys for account %s: %v", accountID, err)
+		}
+	})
+
+	return nil
+}
+
 // RevokeApiKey handles API key revocation
 // @Summary Revoke an API key
-// @Description Revoke (delete) an API key
+// @Description Revoke (delete) an API key. Send the key's ETag in If-Match to revoke it only if it has not changed since it was read.
 // @Tags auth
 // @Param api_key_id path string true "API Key ID"
+// @Param If-Match header string false "ETag of the version being revoked"
 // @Success 204
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 412 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /api/v1/auth/api-keys/{api_key_id} [delete]
 func (h *AuthHandler) RevokeApiKey(c *fiber.Ctx) error {
@@ -395,17 +908,19 @@ func (h *AuthHandler) RevokeApiKey(c *fiber.Ctx) error {
 	apiKeyIDStr := c.Params("api_key_id")
 	apiKeyID, err := uuid.Parse(apiKeyIDStr)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
-			Error:   "invalid_api_key_id",
-			Message: "Invalid API key ID format",
-		})
+		return RespondError(c, domain.ErrCodeInvalidAPIKeyID)
+	}
+
+	expectedVersion, errResp := ifMatchVersion(c)
+	if errResp != nil {
+		return RespondErrorWith(c, *errResp)
 	}
 
 	// Get account ID from context for audit logging
 	accountID, err := GetAccountID(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
 			Message: "Failed to get account context",
 			Details: err.Error(),
 		})
@@ -420,7 +935,8 @@ func (h *AuthHandler) RevokeApiKey(c *fiber.Ctx) error {
 
 	// Convert to use case input
 	input := usecase.RevokeApiKeyInput{
-		APIKeyID: apiKeyID,
+		APIKeyID:        apiKeyID,
+		ExpectedVersion: expectedVersion,
 	}
 
 	// Execute use case
@@ -433,21 +949,21 @@ func (h *AuthHandler) RevokeApiKey(c *fiber.Ctx) error {
 			&apiKeyID,
 			&apiKeyName,
 			c.IP(), c.Get("User-Agent"),
-			map[string]string{
+			h.auditDetail.details(c, map[string]string{
 				"error":   err.Error(),
 				"success": "false",
-			},
+			}),
 		)
 
 		if err.Error() == "API key not found" {
-			return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
-				Error:   "api_key_not_found",
-				Message: "API key not found",
-			})
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		}
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return RespondError(c, versionConflictCode(expectedVersion))
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-			Error:   "internal_error",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
 			Message: "Failed to revoke API key",
 			Details: err.Error(),
 		})
@@ -460,12 +976,241 @@ func (h *AuthHandler) RevokeApiKey(c *fiber.Ctx) error {
 		&apiKeyID,
 		&apiKeyName,
 		c.IP(), c.Get("User-Agent"),
-		map[string]string{"success": "true"},
+		h.auditDetail.details(c, map[string]string{"success": "true"}),
 	)
 
 	return c.Status(fiber.StatusNoContent).Send(nil)
 }
 
+// RevokeAPIKeysBatch handles revoking a list of the caller's API keys
+// @Summary Revoke several API keys
+// @Description Revoke up to 100 of the caller's keys by ID, e.g. from a leak report. Each key is handled on its own; keys that do not exist or belong to another account are reported as not found.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RevokeAPIKeysBatchRequest true "API key IDs"
+// @Success 200 {object} dto.RevokeAPIKeysBatchResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/api-keys/revoke-batch [post]
+func (h *AuthHandler) RevokeAPIKeysBatch(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	var req dto.RevokeAPIKeysBatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "Invalid request body",
+			Details: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return RespondErrorWith(c, validationErrorResponse(err))
+	}
+
+	accountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+
+	// Already checked by Validate
+	apiKeyIDs := make([]uuid.UUID, len(req.APIKeyIDs))
+	for i, id := range req.APIKeyIDs {
+		apiKeyIDs[i] = uuid.MustParse(id)
+	}
+
+	// Execute use case
+	output, err := h.revokeBatch.Execute(ctx, usecase.RevokeApiKeysInput{
+		AccountID: accountID,
+		APIKeyIDs: apiKeyIDs,
+	})
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to revoke API keys",
+			Details: err.Error(),
+		})
+	}
+
+	response := dto.RevokeAPIKeysBatchResponse{
+		Results: make([]dto.RevokeAPIKeyResult, len(output.Results)),
+		Revoked: output.Revoked,
+		Failed:  output.Failed,
+	}
+	for i, result := range output.Results {
+		response.Results[i] = dto.RevokeAPIKeyResult{
+			APIKeyID: result.APIKeyID,
+			Revoked:  result.Revoked,
+			Error:    result.Error,
+		}
+
+		// Audit each key like a single revocation
+		apiKeyID := result.APIKeyID
+		details := map[string]string{"success": "true", "batch": "true"}
+		if !result.Revoked {
+			details = map[string]string{"error": result.Error, "success": "false", "batch": "true"}
+		}
+		h.auditLogger.LogAPIKeyRevocation(
+			ctx,
+			&accountID,
+			&apiKeyID,
+			nil,
+			c.IP(), c.Get("User-Agent"),
+			h.auditDetail.details(c, details),
+		)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// RevokeOwnApiKey handles revocation of the API key used to authenticate the request
+// @Summary Revoke the calling API key
+// @Description Revoke the API key that authenticated this request, e.g. after it was leaked. Later requests with the key fail.
+// @Tags auth
+// @Success 204
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/me/revoke [post]
+func (h *AuthHandler) RevokeOwnApiKey(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// The key to revoke is the one RequireAuth resolved for this request
+	apiKeyID, err := GetAPIKeyID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get API key context",
+			Details: err.Error(),
+		})
+	}
+	accountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	apiKeyName, err := GetAPIKeyName(c)
+	if err != nil {
+		apiKeyName = ""
+	}
+
+	_, err = h.revokeApiKey.Execute(ctx, usecase.RevokeApiKeyInput{APIKeyID: apiKeyID})
+	if err != nil {
+		h.auditLogger.LogAPIKeyRevocation(
+			ctx,
+			&accountID,
+			&apiKeyID,
+			&apiKeyName,
+			c.IP(), c.Get("User-Agent"),
+			h.auditDetail.details(c, map[string]string{
+				"error":   err.Error(),
+				"success": "false",
+				"self":    "true",
+			}),
+		)
+
+		if err.Error() == "API key not found" {
+			return RespondError(c, domain.ErrCodeAPIKeyNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to revoke API key",
+			Details: err.Error(),
+		})
+	}
+
+	h.auditLogger.LogAPIKeyRevocation(
+		ctx,
+		&accountID,
+		&apiKeyID,
+		&apiKeyName,
+		c.IP(), c.Get("User-Agent"),
+		h.auditDetail.details(c, map[string]string{"success": "true", "self": "true"}),
+	)
+
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// ExportAccount handles exporting an account's API key inventory
+// @Summary Export account API key inventory
+// @Description Download account details and all API key metadata (never secrets) as a single JSON document
+// @Tags auth
+// @Produce json
+// @Param account_id path string true "Account ID"
+// @Success 200 {object} dto.AccountExportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /api/v1/auth/accounts/{account_id}/export [get]
+func (h *AuthHandler) ExportAccount(c *fiber.Ctx) error {
+	ctx := context.Background()
+
+	// Parse account ID
+	accountIDStr := c.Params("account_id")
+	accountID, err := uuid.Parse(accountIDStr)
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInvalidAccountID)
+	}
+
+	// Callers may only export their own account
+	callerAccountID, err := GetAccountID(c)
+	if err != nil {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to get account context",
+			Details: err.Error(),
+		})
+	}
+	if callerAccountID != accountID {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
+			Message: "Cannot export another account",
+		})
+	}
+
+	// Execute use case
+	output, err := h.exportAccount.Execute(ctx, usecase.ExportAccountInput{AccountID: accountID})
+	if err != nil {
+		if err.Error() == "account not found or inactive" {
+			return RespondError(c, domain.ErrCodeAccountNotFound)
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInternalError,
+			Message: "Failed to export account",
+			Details: err.Error(),
+		})
+	}
+
+	// Convert API keys to response format (metadata only)
+	apiKeys := make([]dto.ApiKeyResponse, len(output.APIKeys))
+	for i, apiKey := range output.APIKeys {
+		apiKeys[i] = toApiKeyResponse(apiKey)
+	}
+
+	// Create response
+	response := dto.AccountExportResponse{
+		Account:    toAccountResponse(output.Account),
+		APIKeys:    apiKeys,
+		Total:      len(apiKeys),
+		ExportedAt: output.ExportedAt,
+	}
+
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"account-%s-export.json\"", accountID))
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
 // HealthCheck handles health check requests
 // @Summary Health check
 // @Description Check if the auth service is healthy
@@ -3,19 +3,48 @@ package http
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
 
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
 	"github.com/aws-payment-gateway/internal/auth/domain"
 	"github.com/aws-payment-gateway/internal/auth/usecase"
 	"github.com/gofiber/fiber/v2"
 )
 
+// IdempotencyMiddlewareConfig defines optional behaviour for IdempotencyMiddleware
+type IdempotencyMiddlewareConfig struct {
+	// HashHeaders is an allowlist of request headers included in the request hash.
+	// By default no headers are hashed, so retries that differ only in incidental
+	// headers (Date, User-Agent, trace IDs) match the original request.
+	HashHeaders []string
+	// HeaderNames are the request headers the idempotency key is read from, in
+	// order of precedence. Defaults to DefaultIdempotencyHeader.
+	HeaderNames []string
+	// Methods are the HTTP methods idempotency applies to; requests with other
+	// methods pass through untouched even when they carry a key. Defaults to
+	// DefaultIdempotencyMethods.
+	Methods []string
+}
+
+// DefaultIdempotencyHeader is the header the idempotency key is read from by default
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyMethods are the methods idempotency applies to by default.
+// GET and DELETE are already idempotent, so they are left out.
+var DefaultIdempotencyMethods = []string{fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch}
+
 // IdempotencyMiddleware provides idempotency handling for HTTP requests
 type IdempotencyMiddleware struct {
 	checkIdempotency    *usecase.CheckIdempotency
 	createIdempotency   *usecase.CreateIdempotency
 	completeIdempotency *usecase.CompleteIdempotency
+	hashHeaders         []string
+	headerNames         []string
+	methods             map[string]bool
 }
 
 // NewIdempotencyMiddleware creates a new IdempotencyMiddleware
@@ -23,40 +52,62 @@ func NewIdempotencyMiddleware(
 	checkIdempotency *usecase.CheckIdempotency,
 	createIdempotency *usecase.CreateIdempotency,
 	completeIdempotency *usecase.CompleteIdempotency,
+	config IdempotencyMiddlewareConfig,
 ) *IdempotencyMiddleware {
+	// Normalize and sort the header allowlist so hashing is deterministic
+	hashHeaders := make([]string, 0, len(config.HashHeaders))
+	for _, header := range config.HashHeaders {
+		hashHeaders = append(hashHeaders, strings.ToLower(header))
+	}
+	sort.Strings(hashHeaders)
+
+	headerNames := config.HeaderNames
+	if len(headerNames) == 0 {
+		headerNames = []string{DefaultIdempotencyHeader}
+	}
+
+	methodNames := config.Methods
+	if len(methodNames) == 0 {
+		methodNames = DefaultIdempotencyMethods
+	}
+	methods := make(map[string]bool, len(methodNames))
+	for _, method := range methodNames {
+		methods[strings.ToUpper(method)] = true
+	}
+
 	return &IdempotencyMiddleware{
 		checkIdempotency:    checkIdempotency,
 		createIdempotency:   createIdempotency,
 		completeIdempotency: completeIdempotency,
+		hashHeaders:         hashHeaders,
+		headerNames:         headerNames,
+		methods:             methods,
 	}
 }
 
-// generateRequestHash generates a hash for the request
-func (m *IdempotencyMiddleware) generateRequestHash(c *fiber.Ctx) string {
+// generateRequestHash generates a hash for the request from the caller's account,
+// the idempotency key, the method, path, body and the allowlisted headers only.
+// Scoping by account prevents two accounts using the same key value from colliding.
+func (m *IdempotencyMiddleware) generateRequestHash(c *fiber.Ctx, idempotencyKey string) string {
+	// Scope to the authenticated account, if any
+	scope := "anonymous"
+	if accountID, err := GetAccountID(c); err == nil {
+		scope = accountID.String()
+	}
+
 	// Get request method and path
 	method := c.Method()
 	path := c.Path()
 
 	// Get request body (if any)
-	var body string
-	if c.Body() != nil {
-		body = string(c.Body())
-	}
-
-	// Get relevant headers
-	headers := make(map[string]string)
-	for key, values := range c.GetReqHeaders() {
-		if len(values) > 0 {
-			headers[key] = values[0] // Take first value
-		}
-	}
+	body := string(c.Body())
 
 	// Create normalized request string for hashing
-	requestData := fmt.Sprintf("%s:%s:%s:%s", method, path, body)
+	requestData := fmt.Sprintf("%s:%s:%s:%s:%s", scope, idempotencyKey, method, path, body)
 
-	// Add headers to request data
-	for key, value := range headers {
-		requestData += fmt.Sprintf(":%s:%s", strings.ToLower(key), value)
+	// Add allowlisted headers in a fixed order
+	for _, header := range m.hashHeaders {
+		requestData += fmt.Sprintf(":%s:%s", header, c.Get(header))
 	}
 
 	// Hash the request data
@@ -64,18 +115,21 @@ func (m *IdempotencyMiddleware) generateRequestHash(c *fiber.Ctx) string {
 	return hex.EncodeToString(hash[:])
 }
 
-// extractIdempotencyKey extracts idempotency key from request
+// extractIdempotencyKey extracts idempotency key from request. Requests whose
+// method is not gated get no key, so every stage skips them.
 func (m *IdempotencyMiddleware) extractIdempotencyKey(c *fiber.Ctx) string {
-	// Try different header names
-	idempotencyKey := c.Get("Idempotency-Key")
-	if idempotencyKey == "" {
-		idempotencyKey = c.Get("X-Idempotency-Key")
+	if !m.methods[c.Method()] {
+		return ""
 	}
-	if idempotencyKey == "" {
-		idempotencyKey = c.Get("X-Idempotency-Key")
+
+	// Use the first configured header that is present
+	for _, header := range m.headerNames {
+		if idempotencyKey := c.Get(header); idempotencyKey != "" {
+			return idempotencyKey
+		}
 	}
 
-	return idempotencyKey
+	return ""
 }
 
 // Check creates a middleware that checks for existing idempotency keys
@@ -89,18 +143,20 @@ func (m *IdempotencyMiddleware) Check() fiber.Handler {
 		}
 
 		// Generate request hash
-		requestHash := m.generateRequestHash(c)
+		requestHash := m.generateRequestHash(c, idempotencyKey)
+		accountID, _ := GetAccountID(c)
 
 		// Check if idempotency key exists
 		output, err := m.checkIdempotency.Execute(c.Context(), usecase.CheckIdempotencyInput{
 			IdempotencyKey: idempotencyKey,
 			RequestHash:    requestHash,
+			AccountID:      accountID,
 		})
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "idempotency_check_failed",
-				"message": "Failed to check idempotency key",
-				"details": err.Error(),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyCheckFailed,
+				Message: "Failed to check idempotency key",
+				Details: err.Error(),
 			})
 		}
 
@@ -108,22 +164,15 @@ func (m *IdempotencyMiddleware) Check() fiber.Handler {
 			// Key exists, check status
 			if output.Status == string(domain.IdempotencyKeyStatusCompleted) {
 				// Request already completed, return cached response
-				if output.Response != "" {
-					c.Set("Content-Type", "application/json")
-					return c.Status(200).SendString(output.Response)
-				}
-				return c.Status(200).JSON(fiber.Map{
-					"status":       "completed",
-					"completed_at": output.CreatedAt,
-				})
+				return replayResponse(c, output)
 			} else if output.Status == string(domain.IdempotencyKeyStatusExpired) {
 				// Key exists but expired, treat as new request
 				return c.Next()
 			} else {
 				// Key exists and is pending, request is in progress
-				return c.Status(409).JSON(fiber.Map{
-					"error":   "idempotency_key_pending",
-					"message": "Request with this idempotency key is already in progress",
+				return RespondErrorWith(c, dto.ErrorResponse{
+					Error:   domain.ErrCodeIdempotencyKeyPending,
+					Message: "Request with this idempotency key is already in progress",
 				})
 			}
 		}
@@ -144,19 +193,31 @@ func (m *IdempotencyMiddleware) Create() fiber.Handler {
 		}
 
 		// Generate request hash
-		requestHash := m.generateRequestHash(c)
+		requestHash := m.generateRequestHash(c, idempotencyKey)
+		accountID, _ := GetAccountID(c)
 
 		// Create new idempotency key
 		output, err := m.createIdempotency.Execute(c.Context(), usecase.CreateIdempotencyInput{
 			IdempotencyKey: idempotencyKey,
 			RequestHash:    requestHash,
 			Response:       "", // Will be set by the actual handler
+			AccountID:      accountID,
 		})
+		if errors.Is(err, domain.ErrIdempotencyKeyLimitExceeded) {
+			return RespondError(c, domain.ErrCodeIdempotencyLimitExceeded)
+		}
+		if errors.Is(err, domain.ErrIdempotencyKeyInProgress) {
+			// A concurrent request with the same key got past the check first
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyKeyPending,
+				Message: "Request with this idempotency key is already in progress",
+			})
+		}
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "idempotency_creation_failed",
-				"message": "Failed to create idempotency key",
-				"details": err.Error(),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyCreateFailed,
+				Message: "Failed to create idempotency key",
+				Details: err.Error(),
 			})
 		}
 
@@ -168,7 +229,8 @@ func (m *IdempotencyMiddleware) Create() fiber.Handler {
 	}
 }
 
-// Complete creates a middleware that completes idempotency keys
+// Complete creates a middleware that runs the route and then completes the
+// idempotency key with the response the route produced
 func (m *IdempotencyMiddleware) Complete() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Extract idempotency key from request
@@ -178,23 +240,132 @@ func (m *IdempotencyMiddleware) Complete() fiber.Handler {
 			return c.Next()
 		}
 
-		// Complete the idempotency key
-		_, err := m.completeIdempotency.Execute(c.Context(), usecase.CompleteIdempotencyInput{
+		// Run the route first so its response can be stored
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		// The route has already run, so a failure to store its response must not
+		// replace it; the key stays pending until it expires
+		if _, err := m.completeIdempotency.Execute(c.Context(), usecase.CompleteIdempotencyInput{
 			IdempotencyKey: idempotencyKey,
-			Response:       "", // Will be set by the actual handler response
+			Response:       string(c.Response().Body()),
+			ResponseStatus: c.Response().StatusCode(),
+		}); err != nil {
+			log.Printf("Failed to complete idempotency key %s: %v", idempotencyKey, err)
+		}
+
+		// Store the completion status in response header
+		c.Set("X-Idempotency-Key", idempotencyKey)
+
+		return nil
+	}
+}
+
+// Handle runs the whole idempotency flow around a single route, for routes that
+// need replays to be faithful: a completed request is answered with its original
+// status and body, a pending one is rejected, and otherwise the key is reserved,
+// the route runs and whatever it responded with is stored. Error responses are
+// stored too, so a retry after a failure needs a new key. Register it after any
+// authentication middleware so keys are scoped to the caller's account.
+func (m *IdempotencyMiddleware) Handle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		idempotencyKey := m.extractIdempotencyKey(c)
+		if idempotencyKey == "" {
+			return c.Next()
+		}
+
+		requestHash := m.generateRequestHash(c, idempotencyKey)
+		accountID, _ := GetAccountID(c)
+
+		existing, err := m.checkIdempotency.Execute(c.Context(), usecase.CheckIdempotencyInput{
+			IdempotencyKey: idempotencyKey,
+			RequestHash:    requestHash,
+			AccountID:      accountID,
 		})
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "idempotency_completion_failed",
-				"message": "Failed to complete idempotency key",
-				"details": err.Error(),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyCheckFailed,
+				Message: "Failed to check idempotency key",
+				Details: err.Error(),
 			})
 		}
 
-		// Store the completion status in response header
-		c.Set("X-Idempotency-Key", idempotencyKey)
+		switch {
+		case existing.Exists && existing.Status == string(domain.IdempotencyKeyStatusCompleted):
+			return replayResponse(c, existing)
+		case existing.Exists && existing.Status == string(domain.IdempotencyKeyStatusPending):
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyKeyPending,
+				Message: "Request with this idempotency key is already in progress",
+			})
+		}
 
-		// Continue with request processing
-		return c.Next()
+		// New or expired: reserve the key before running the route
+		created, err := m.createIdempotency.Execute(c.Context(), usecase.CreateIdempotencyInput{
+			IdempotencyKey: idempotencyKey,
+			RequestHash:    requestHash,
+			AccountID:      accountID,
+		})
+		if errors.Is(err, domain.ErrIdempotencyKeyLimitExceeded) {
+			return RespondError(c, domain.ErrCodeIdempotencyLimitExceeded)
+		}
+		if errors.Is(err, domain.ErrIdempotencyKeyInProgress) {
+			// A concurrent request with the same key got past the check first
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyKeyPending,
+				Message: "Request with this idempotency key is already in progress",
+			})
+		}
+		if err != nil {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeIdempotencyCreateFailed,
+				Message: "Failed to create idempotency key",
+				Details: err.Error(),
+			})
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		// The route has already run, so a failure to store its response must not
+		// replace it; the key stays pending until it expires
+		if _, err := m.completeIdempotency.Execute(c.Context(), usecase.CompleteIdempotencyInput{
+			IdempotencyKey: created.IdempotencyKey,
+			Response:       string(c.Response().Body()),
+			ResponseStatus: c.Response().StatusCode(),
+		}); err != nil {
+			log.Printf("Failed to complete idempotency key %s: %v", created.IdempotencyKey, err)
+		}
+
+		c.Set("X-Idempotency-Key", created.IdempotencyKey)
+		return nil
+	}
+}
+
+// HeaderIdempotentReplayed marks responses replayed from a stored idempotency key
+const HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+// replayResponse answers with a completed request's stored response, using the
+// status it was originally sent with. Keys completed before statuses were stored
+// replay as 200. Truncated responses cannot be replayed, so only the completion
+// status is returned.
+func replayResponse(c *fiber.Ctx, output *usecase.CheckIdempotencyOutput) error {
+	c.Set(HeaderIdempotentReplayed, "true")
+
+	status := output.ResponseStatus
+	if status == 0 {
+		status = fiber.StatusOK
+	}
+
+	if output.Response != "" {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.Status(status).SendString(output.Response)
 	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":             "completed",
+		"completed_at":       output.CreatedAt,
+		"response_truncated": output.ResponseTruncated,
+	})
 }
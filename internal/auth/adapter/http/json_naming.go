@@ -0,0 +1,143 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// JSON field naming styles for response bodies
+const (
+	JSONNamingSnakeCase = "snake_case"
+	JSONNamingCamelCase = "camelCase"
+)
+
+// JSONEncoder returns the encoder to use as fiber.Config.JSONEncoder. DTOs are
+// tagged in snake_case; for camelCase the encoded keys are rewritten rather than
+// keeping a second set of structs.
+func JSONEncoder(naming string) func(v interface{}) ([]byte, error) {
+	if naming != JSONNamingCamelCase {
+		return json.Marshal
+	}
+
+	return func(v interface{}) ([]byte, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return camelCaseKeys(data)
+	}
+}
+
+// jsonFrame tracks an open object or array while rewriting a document
+type jsonFrame struct {
+	object  bool
+	started bool
+	wantKey bool
+}
+
+// camelCaseKeys rewrites every object key in a JSON document from snake_case to
+// camelCase. Key order, values and number formatting are kept as they are.
+// Map keys are rewritten too, which only affects keys containing underscores.
+func camelCaseKeys(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	var stack []jsonFrame
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			out.WriteByte(byte(delim))
+			stack = stack[:len(stack)-1]
+			endValue(stack)
+			continue
+		}
+
+		if len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.object && top.wantKey {
+				if top.started {
+					out.WriteByte(',')
+				}
+				top.started = true
+				top.wantKey = false
+				if err := writeJSONValue(&out, snakeToCamel(tok.(string))); err != nil {
+					return nil, err
+				}
+				out.WriteByte(':')
+				continue
+			}
+			if !top.object {
+				if top.started {
+					out.WriteByte(',')
+				}
+				top.started = true
+			}
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			out.WriteByte(byte(delim))
+			stack = append(stack, jsonFrame{object: delim == '{', wantKey: delim == '{'})
+			continue
+		}
+
+		if err := writeJSONValue(&out, tok); err != nil {
+			return nil, err
+		}
+		endValue(stack)
+	}
+
+	return out.Bytes(), nil
+}
+
+// endValue marks the enclosing object, if any, as ready for its next key
+func endValue(stack []jsonFrame) {
+	if len(stack) > 0 && stack[len(stack)-1].object {
+		stack[len(stack)-1].wantKey = true
+	}
+}
+
+// writeJSONValue writes a scalar token back out in JSON form
+func writeJSONValue(out *bytes.Buffer, tok json.Token) error {
+	if number, ok := tok.(json.Number); ok {
+		out.WriteString(number.String())
+		return nil
+	}
+
+	encoded, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+	out.Write(encoded)
+	return nil
+}
+
+// snakeToCamel converts a snake_case name to camelCase, e.g. api_key_id to apiKeyId
+func snakeToCamel(name string) string {
+	if !strings.Contains(name, "_") {
+		return name
+	}
+
+	parts := strings.Split(name, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
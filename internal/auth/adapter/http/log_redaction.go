@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+)
+
+// redactedValue replaces secrets in logged query strings
+const redactedValue = "REDACTED"
+
+// redactQuery replaces the value of param in a raw query string
+func redactQuery(rawQuery, param string) string {
+	if param == "" || rawQuery == "" {
+		return rawQuery
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil && decoded == param {
+			pairs[i] = key + "=" + redactedValue
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// RedactedLogTags overrides the request logger's url and queryParams tags so
+// the given query parameter (e.g. an API key) is never written to the log
+func RedactedLogTags(param string) map[string]logger.LogFunc {
+	return map[string]logger.LogFunc{
+		logger.TagURL: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			path, rawQuery, found := strings.Cut(c.OriginalURL(), "?")
+			if !found {
+				return output.WriteString(path)
+			}
+			return output.WriteString(path + "?" + redactQuery(rawQuery, param))
+		},
+		logger.TagQueryStringParams: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			return output.WriteString(redactQuery(c.Request().URI().QueryArgs().String(), param))
+		},
+	}
+}
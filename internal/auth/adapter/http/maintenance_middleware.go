@@ -0,0 +1,76 @@
+package http
+
+import (
+	"sync/atomic"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaintenanceMiddlewareConfig configures the maintenance middleware
+type MaintenanceMiddlewareConfig struct {
+	// Enabled is the initial state
+	Enabled bool
+	// AllowPaths are write routes that stay available, e.g. POST /validate which only reads
+	AllowPaths []string
+}
+
+// MaintenanceMiddleware rejects mutating requests with 503 while maintenance mode is on.
+// Reads (GET, HEAD, OPTIONS) keep working. The mode can be toggled at runtime.
+type MaintenanceMiddleware struct {
+	enabled    atomic.Bool
+	allowPaths map[string]bool
+}
+
+// NewMaintenanceMiddleware creates a new MaintenanceMiddleware
+func NewMaintenanceMiddleware(config MaintenanceMiddlewareConfig) *MaintenanceMiddleware {
+	m := &MaintenanceMiddleware{
+		allowPaths: make(map[string]bool, len(config.AllowPaths)),
+	}
+	for _, path := range config.AllowPaths {
+		m.allowPaths[path] = true
+	}
+	m.enabled.Store(config.Enabled)
+	return m
+}
+
+// Enabled reports whether maintenance mode is on
+func (m *MaintenanceMiddleware) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// SetEnabled turns maintenance mode on or off
+func (m *MaintenanceMiddleware) SetEnabled(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+// Toggle flips maintenance mode and returns the new state
+func (m *MaintenanceMiddleware) Toggle() bool {
+	for {
+		current := m.enabled.Load()
+		if m.enabled.CompareAndSwap(current, !current) {
+			return !current
+		}
+	}
+}
+
+// Handler returns the middleware handler
+func (m *MaintenanceMiddleware) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !m.enabled.Load() || isReadOnlyMethod(c.Method()) || m.allowPaths[c.Path()] {
+			return c.Next()
+		}
+
+		c.Set("Retry-After", "60")
+		return RespondError(c, domain.ErrCodeMaintenance)
+	}
+}
+
+// isReadOnlyMethod reports whether the HTTP method does not modify state
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return false
+}
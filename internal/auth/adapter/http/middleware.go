@@ -15,37 +15,95 @@ import (
 	"github.com/aws-payment-gateway/internal/auth/usecase"
 )
 
+// AuthMiddlewareConfig defines optional behaviour for AuthMiddleware
+type AuthMiddlewareConfig struct {
+	// CookieName enables reading the API key from a cookie with this name when
+	// no header is present. Empty disables cookie extraction (the default).
+	// Only enable this for same-site browser dashboards: cookies are sent
+	// automatically by the browser, so the calling origin must be protected
+	// against CSRF.
+	CookieName string
+	// QueryParam enables reading the API key from this query parameter when no
+	// header or cookie is present, for webhook callbacks that cannot set headers.
+	// Empty disables it (the default). URLs end up in proxy and access logs, so
+	// keys sent this way should be treated as exposed.
+	QueryParam string
+	// PermissionRateLimiter returns the rate limiter applied once RequirePermission
+	// or RequireAnyPermission has matched a permission, or nil if that permission
+	// is not limited. Nil (the default) disables per-permission rate limiting.
+	PermissionRateLimiter func(permission string) fiber.Handler
+	// ClientCertHeader is the header the TLS-terminating proxy sets to the SHA-256
+	// fingerprint of the verified client certificate. It is only read for accounts
+	// with RequireClientCert; the proxy must strip it from incoming requests.
+	ClientCertHeader string
+}
+
+// HeaderAPIKeyGracePeriod is set on responses to requests authenticated with an
+// expired key that is still within the expiry grace period
+const HeaderAPIKeyGracePeriod = "X-API-Key-Grace-Period"
+
 // AuthMiddleware provides authentication middleware for API key validation
 type AuthMiddleware struct {
 	validateApiKey *usecase.ValidateApiKey
 	apiKeyRepo     repository.ApiKeyRepository
 	auditLogger    audit.AuditLoggerInterface
+	config         AuthMiddlewareConfig
 }
 
 // NewAuthMiddleware creates a new AuthMiddleware
-func NewAuthMiddleware(validateApiKey *usecase.ValidateApiKey, apiKeyRepo repository.ApiKeyRepository, auditLogger audit.AuditLoggerInterface) *AuthMiddleware {
+func NewAuthMiddleware(validateApiKey *usecase.ValidateApiKey, apiKeyRepo repository.ApiKeyRepository, auditLogger audit.AuditLoggerInterface, config AuthMiddlewareConfig) *AuthMiddleware {
 	return &AuthMiddleware{
 		validateApiKey: validateApiKey,
 		apiKeyRepo:     apiKeyRepo,
 		auditLogger:    auditLogger,
+		config:         config,
 	}
 }
 
-// RequireAuth creates a middleware that requires valid API key
-func (m *AuthMiddleware) RequireAuth() fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		// Get API key from header
-		apiKey := c.Get("x-api-key")
-		if apiKey == "" {
-			apiKey = c.Get("Authorization")
-			if apiKey != "" {
-				// Remove "Bearer " prefix if present
-				if strings.HasPrefix(apiKey, "Bearer ") {
-					apiKey = strings.TrimPrefix(apiKey, "Bearer ")
-				}
+// extractAPIKey extracts the API key from the request.
+// Headers always take precedence over the optional cookie and query parameter fallbacks.
+func (m *AuthMiddleware) extractAPIKey(c *fiber.Ctx) string {
+	// Get API key from header
+	apiKey := c.Get("x-api-key")
+	if apiKey == "" {
+		apiKey = c.Get("Authorization")
+		if apiKey != "" {
+			// Remove "Bearer " prefix if present
+			if strings.HasPrefix(apiKey, "Bearer ") {
+				apiKey = strings.TrimPrefix(apiKey, "Bearer ")
 			}
 		}
+	}
+
+	// Fall back to cookie if enabled
+	if apiKey == "" && m.config.CookieName != "" {
+		apiKey = c.Cookies(m.config.CookieName)
+	}
+
+	// Fall back to query parameter if enabled
+	if apiKey == "" && m.config.QueryParam != "" {
+		apiKey = c.Query(m.config.QueryParam)
+	}
+
+	return apiKey
+}
+
+// OptionalAuth authenticates the request when an API key is supplied and lets
+// anonymous requests through. A supplied but invalid key is still rejected.
+func (m *AuthMiddleware) OptionalAuth() fiber.Handler {
+	requireAuth := m.RequireAuth()
+	return func(c *fiber.Ctx) error {
+		if m.extractAPIKey(c) == "" {
+			return c.Next()
+		}
+		return requireAuth(c)
+	}
+}
 
+// RequireAuth creates a middleware that requires valid API key
+func (m *AuthMiddleware) RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey := m.extractAPIKey(c)
 		if apiKey == "" {
 			// Log failed authentication attempt
 			m.auditLogger.LogAuthentication(
@@ -56,8 +114,8 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 				map[string]string{"reason": "missing_api_key"},
 			)
 
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   string(domain.ErrCodeMissingAPIKey),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeMissingAPIKey,
 				Message: domain.ErrMissingAPIKey.Message,
 			})
 		}
@@ -65,7 +123,8 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 		// Validate API key using usecase
 		ctx := context.Background()
 		validationOutput, err := m.validateApiKey.Execute(ctx, usecase.ValidateApiKeyInput{
-			RawKey: apiKey,
+			RawKey:                apiKey,
+			ClientCertFingerprint: m.clientCertFingerprint(c),
 		})
 		if err != nil {
 			// Log failed authentication attempt
@@ -77,13 +136,26 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 				map[string]string{"reason": "validation_error", "error": err.Error()},
 			)
 
-			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   string(domain.ErrCodeValidationFailed),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeValidationFailed,
 				Message: "Failed to validate API key",
 				Details: err.Error(),
 			})
 		}
 
+		// Keys of accounts requiring mutual TLS fail without an allowlisted client certificate
+		if validationOutput.ClientCertRejected {
+			m.auditLogger.LogAuthentication(
+				ctx,
+				validationOutput.AccountID, validationOutput.APIKeyID, validationOutput.Name,
+				c.IP(), c.Get("User-Agent"),
+				false,
+				map[string]string{"reason": "client_cert_rejected"},
+			)
+
+			return RespondError(c, domain.ErrCodeClientCertRequired)
+		}
+
 		if !validationOutput.Valid || validationOutput.AccountID == nil {
 			// Log failed authentication attempt
 			m.auditLogger.LogAuthentication(
@@ -94,8 +166,8 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 				map[string]string{"reason": "invalid_or_expired_key"},
 			)
 
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   string(domain.ErrCodeInvalidAPIKey),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInvalidAPIKey,
 				Message: domain.ErrInvalidAPIKey.Message,
 			})
 		}
@@ -114,43 +186,65 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 		c.Locals("api_key_id", *validationOutput.APIKeyID)
 		c.Locals("api_key_name", *validationOutput.Name)
 		c.Locals("permissions", []string(validationOutput.Permissions))
+		c.Locals("resource_scopes", validationOutput.ResourceScopes)
+
+		// Tell clients still using an expired key to rotate it
+		if validationOutput.InGracePeriod {
+			c.Set(HeaderAPIKeyGracePeriod, "true")
+		}
 
 		// Continue to next handler
 		return c.Next()
 	}
 }
 
+// clientCertFingerprint returns the client certificate fingerprint reported by
+// the proxy, or "" when none was sent or no header is configured
+func (m *AuthMiddleware) clientCertFingerprint(c *fiber.Ctx) string {
+	if m.config.ClientCertHeader == "" {
+		return ""
+	}
+	return c.Get(m.config.ClientCertHeader)
+}
+
+// permissionRateLimit returns the configured rate limiter for permission, if any
+func (m *AuthMiddleware) permissionRateLimit(permission string) fiber.Handler {
+	if m.config.PermissionRateLimiter == nil {
+		return nil
+	}
+	return m.config.PermissionRateLimiter(permission)
+}
+
 // RequirePermission creates a middleware that requires specific permission
 func (m *AuthMiddleware) RequirePermission(permission string) fiber.Handler {
+	rateLimit := m.permissionRateLimit(permission)
 	return func(c *fiber.Ctx) error {
 		// Get permissions from context (set by RequireAuth)
 		permissions := c.Locals("permissions")
 		if permissions == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "not_authenticated",
-				Message: "Authentication required",
-			})
+			return RespondError(c, domain.ErrCodeNotAuthenticated)
 		}
 
 		// Check if user has required permission
 		userPermissions, ok := permissions.([]string)
 		if !ok {
-			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   "internal_error",
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInternalError,
 				Message: "Invalid permissions format",
 			})
 		}
 
-		for _, p := range userPermissions {
-			if p == permission {
-				// User has required permission, continue
-				return c.Next()
+		if domain.ApiKeyPermissions(userPermissions).Contains(permission) {
+			// User has required permission, continue
+			if rateLimit != nil {
+				return rateLimit(c)
 			}
+			return c.Next()
 		}
 
 		// User doesn't have required permission
-		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
-			Error:   "insufficient_permissions",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
 			Message: fmt.Sprintf("Permission '%s' is required", permission),
 		})
 	}
@@ -158,42 +252,73 @@ func (m *AuthMiddleware) RequirePermission(permission string) fiber.Handler {
 
 // RequireAnyPermission creates a middleware that requires any of the specified permissions
 func (m *AuthMiddleware) RequireAnyPermission(permissions ...string) fiber.Handler {
+	rateLimits := make(map[string]fiber.Handler, len(permissions))
+	for _, permission := range permissions {
+		if rateLimit := m.permissionRateLimit(permission); rateLimit != nil {
+			rateLimits[permission] = rateLimit
+		}
+	}
 	return func(c *fiber.Ctx) error {
 		// Get permissions from context (set by RequireAuth)
 		userPermissions := c.Locals("permissions")
 		if userPermissions == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "not_authenticated",
-				Message: "Authentication required",
-			})
+			return RespondError(c, domain.ErrCodeNotAuthenticated)
 		}
 
 		// Check if user has any of the required permissions
 		userPermList, ok := userPermissions.([]string)
 		if !ok {
-			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   "internal_error",
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInternalError,
 				Message: "Invalid permissions format",
 			})
 		}
 
 		for _, requiredPerm := range permissions {
-			for _, userPerm := range userPermList {
-				if userPerm == requiredPerm {
-					// User has required permission, continue
-					return c.Next()
+			if domain.ApiKeyPermissions(userPermList).Contains(requiredPerm) {
+				// User has required permission, continue; the first
+				// matching permission decides the rate limit bucket
+				if rateLimit := rateLimits[requiredPerm]; rateLimit != nil {
+					return rateLimit(c)
 				}
+				return c.Next()
 			}
 		}
 
 		// User doesn't have any of the required permissions
-		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
-			Error:   "insufficient_permissions",
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInsufficientPermissions,
 			Message: fmt.Sprintf("One of these permissions is required: %v", permissions),
 		})
 	}
 }
 
+// RequireResourceScope creates a middleware that rejects keys whose resource
+// scopes do not include the route parameter param, e.g. "account_id". Keys
+// without scopes pass, so it only narrows what RequirePermission allows.
+func (m *AuthMiddleware) RequireResourceScope(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Locals("permissions") == nil {
+			return RespondError(c, domain.ErrCodeNotAuthenticated)
+		}
+
+		resource := domain.CanonicalResourceScope(c.Params(param))
+
+		if !InResourceScope(c, resource) {
+			return RespondErrorWith(c, outOfScopeResponse(param, resource))
+		}
+		return c.Next()
+	}
+}
+
+// outOfScopeResponse is the 403 for a resource outside the authenticated key's scopes
+func outOfScopeResponse(param, resource string) dto.ErrorResponse {
+	return dto.ErrorResponse{
+		Error:   domain.ErrCodeResourceOutOfScope,
+		Message: fmt.Sprintf("This API key is not scoped to %s '%s'", param, resource),
+	}
+}
+
 // GetAccountID gets the account ID from the context
 func GetAccountID(c *fiber.Ctx) (uuid.UUID, error) {
 	accountID := c.Locals("account_id")
@@ -261,11 +386,21 @@ func HasPermission(c *fiber.Ctx, permission string) bool {
 		return false
 	}
 
-	for _, p := range permissions {
-		if p == permission {
-			return true
-		}
-	}
+	return domain.ApiKeyPermissions(permissions).Contains(permission)
+}
 
-	return false
+// GetResourceScopes gets the authenticated key's resource scopes from the
+// context; nil means the key is unrestricted or the request is anonymous
+func GetResourceScopes(c *fiber.Ctx) []string {
+	scopes, _ := c.Locals("resource_scopes").([]string)
+	return scopes
+}
+
+// InResourceScope checks if the authenticated key may act on resource.
+// Anonymous requests are never in scope.
+func InResourceScope(c *fiber.Ctx, resource string) bool {
+	if c.Locals("permissions") == nil {
+		return false
+	}
+	return domain.ResourceInScope(GetResourceScopes(c), resource)
 }
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Pagination bounds shared by list endpoints
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// PaginationConfig defines how list endpoints treat bad limit/offset parameters
+type PaginationConfig struct {
+	// Lenient silently replaces non-numeric or out-of-range values with the
+	// defaults instead of rejecting the request with 400 invalid_pagination
+	Lenient bool
+}
+
+// parsePagination reads the limit and offset query parameters. In strict mode
+// every bad value is reported; in lenient mode bad values fall back to defaults.
+func parsePagination(c *fiber.Ctx, config PaginationConfig) (int, int, error) {
+	var errs dto.ValidationErrors
+
+	limit := defaultPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			errs.Add("limit", fmt.Sprintf("limit must be a number, got '%s'", raw))
+		case n < 1 || n > maxPageLimit:
+			errs.Add("limit", fmt.Sprintf("limit must be between 1 and %d, got %d", maxPageLimit, n))
+		default:
+			limit = n
+		}
+	}
+
+	offset := 0
+	if raw := c.Query("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		switch {
+		case err != nil:
+			errs.Add("offset", fmt.Sprintf("offset must be a number, got '%s'", raw))
+		case n < 0:
+			errs.Add("offset", fmt.Sprintf("offset must not be negative, got %d", n))
+		default:
+			offset = n
+		}
+	}
+
+	if config.Lenient {
+		return limit, offset, nil
+	}
+
+	return limit, offset, errs.Err()
+}
+
+// invalidPaginationResponse builds the error response for rejected pagination parameters
+func invalidPaginationResponse(err error) dto.ErrorResponse {
+	response := validationErrorResponse(err)
+	response.Error = domain.ErrCodeInvalidPagination
+	response.Message = domain.ErrCodeInvalidPagination.DefaultMessage()
+	return response
+}
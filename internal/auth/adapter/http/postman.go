@@ -0,0 +1,235 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+)
+
+// PostmanSchema is the Postman collection format PostmanCollection produces
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// PostmanCollectionName is the collection name shown after import
+const PostmanCollectionName = "Auth Service"
+
+// postmanCollection and the types below are the subset of the Postman v2.1
+// collection format the generated collection uses
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanFolder   `json:"item"`
+	Auth     postmanAuth       `json:"auth"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanVariable `json:"header"`
+	URL         postmanURL        `json:"url"`
+	Body        *postmanBody      `json:"body,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	APIKey []postmanVariable `json:"apikey"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// PostmanCollection serves a Postman collection of every route the app has
+// registered, built from the router at request time so it cannot fall behind
+// the routes actually served. Routes named after a request schema (see
+// RequestSchema) get an example body of the schema's required fields.
+// @Summary Get a Postman collection
+// @Description Return a Postman v2.1 collection generated from the registered routes. Import it into Postman and set the apiKey variable.
+// @Tags schemas
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/auth/postman [get]
+func PostmanCollection(c *fiber.Ctx) error {
+	collection := buildPostmanCollection(c.App().GetRoutes(true), c.BaseURL())
+
+	// Marshalled directly so JSON_NAMING cannot rewrite Postman's field names
+	body, err := json.Marshal(collection)
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInternalError)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="auth-service.postman_collection.json"`)
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// buildPostmanCollection groups routes into one folder per first path segment
+// below /api/v1/auth, in path order. HEAD routes, which fiber adds for every
+// GET, and repeat registrations of a method and path are left out.
+func buildPostmanCollection(routes []fiber.Route, baseURL string) postmanCollection {
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	var folders []postmanFolder
+	folderIndex := map[string]int{}
+	for i, route := range routes {
+		if route.Method == fiber.MethodHead {
+			continue
+		}
+		if i > 0 && routes[i-1].Method == route.Method && routes[i-1].Path == route.Path {
+			continue
+		}
+
+		folder := postmanFolderName(route.Path)
+		f, ok := folderIndex[folder]
+		if !ok {
+			f = len(folders)
+			folderIndex[folder] = f
+			folders = append(folders, postmanFolder{Name: folder})
+		}
+		folders[f].Item = append(folders[f].Item, postmanItemFor(route))
+	}
+
+	return postmanCollection{
+		Info: postmanInfo{Name: PostmanCollectionName, Schema: PostmanSchema},
+		Item: folders,
+		Auth: postmanAuth{
+			Type: "apikey",
+			APIKey: []postmanVariable{
+				{Key: "key", Value: "x-api-key"},
+				{Key: "value", Value: "{{apiKey}}"},
+				{Key: "in", Value: "header"},
+			},
+		},
+		Variable: []postmanVariable{
+			{Key: "baseUrl", Value: baseURL},
+			{Key: "apiKey", Value: ""},
+		},
+	}
+}
+
+// postmanFolderName returns the folder a route is listed under, e.g. "accounts"
+// for /api/v1/auth/accounts/:account_id; routes outside the API go under "service"
+func postmanFolderName(path string) string {
+	rest, ok := strings.CutPrefix(path, "/api/v1/auth/")
+	if !ok {
+		return "service"
+	}
+	segment, _, _ := strings.Cut(rest, "/")
+	if segment == "" || strings.HasPrefix(segment, ":") {
+		return "auth"
+	}
+	return segment
+}
+
+// postmanItemFor describes one route as a Postman request. Path parameters
+// become Postman path variables, which use the same :name syntax as fiber.
+func postmanItemFor(route fiber.Route) postmanItem {
+	name := route.Name
+	if name == "" {
+		name = route.Method + " " + route.Path
+	}
+
+	path := strings.Split(strings.Trim(route.Path, "/"), "/")
+	url := postmanURL{
+		Raw:  "{{baseUrl}}" + route.Path,
+		Host: []string{"{{baseUrl}}"},
+		Path: path,
+	}
+	for _, param := range route.Params {
+		url.Variable = append(url.Variable, postmanVariable{Key: param, Value: ""})
+	}
+
+	request := postmanRequest{
+		Method: route.Method,
+		Header: []postmanVariable{},
+		URL:    url,
+	}
+
+	switch route.Method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch:
+		request.Header = append(request.Header, postmanVariable{Key: fiber.HeaderContentType, Value: fiber.MIMEApplicationJSON})
+		request.Body = &postmanBody{
+			Mode:    "raw",
+			Raw:     postmanExampleBody(route.Name),
+			Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+		}
+		if _, ok := dto.RequestSchema(route.Name); ok {
+			request.Description = fmt.Sprintf("Body schema: GET /api/v1/auth/schemas/%s", route.Name)
+		}
+	}
+
+	return postmanItem{Name: name, Request: request}
+}
+
+// postmanExampleBody returns a JSON body with placeholder values for the
+// required fields of the named request schema, or "{}" when there is none
+func postmanExampleBody(schemaName string) string {
+	schema, ok := dto.RequestSchema(schemaName)
+	if !ok {
+		return "{}"
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]string)
+	example := make(map[string]interface{}, len(required))
+	for _, name := range required {
+		property, _ := properties[name].(map[string]interface{})
+		switch property["type"] {
+		case "integer", "number":
+			example[name] = 0
+		case "boolean":
+			example[name] = false
+		case "array":
+			example[name] = []interface{}{}
+		case "object":
+			example[name] = map[string]interface{}{}
+		default:
+			example[name] = ""
+		}
+	}
+
+	body, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(body)
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
 	"github.com/aws-payment-gateway/internal/auth/repository"
 	"github.com/gofiber/fiber/v2"
 )
@@ -39,12 +41,58 @@ func (m *RateLimitMiddleware) AddConfig(name string, config *RateLimitConfig) {
 	m.configs[name] = config
 }
 
+// ForConfig creates a rate limiter from a configuration registered with AddConfig.
+// Counters are namespaced by the config name, so separate configs never share buckets.
+func (m *RateLimitMiddleware) ForConfig(name string) fiber.Handler {
+	config, ok := m.configs[name]
+	if !ok {
+		panic(fmt.Sprintf("rate limit config %q is not registered", name))
+	}
+	return m.createHandler(name, config)
+}
+
+// AddPermissionConfig adds the rate limit for routes that require permission
+func (m *RateLimitMiddleware) AddPermissionConfig(permission string, config *RateLimitConfig) {
+	m.AddConfig(permissionConfigName(permission), config)
+}
+
+// ForPermission creates the rate limiter registered with AddPermissionConfig for
+// permission, or returns nil when the permission has no limit. Each permission
+// counts in its own bucket, so a key's reads do not use up its write allowance.
+func (m *RateLimitMiddleware) ForPermission(permission string) fiber.Handler {
+	name := permissionConfigName(permission)
+	config, ok := m.configs[name]
+	if !ok {
+		return nil
+	}
+	return m.createHandler(name, config)
+}
+
+// permissionConfigName namespaces per-permission configs apart from named ones
+func permissionConfigName(permission string) string {
+	return "permission:" + permission
+}
+
+// KeyByAPIKeyID is a key generator that identifies clients by their authenticated
+// API key. Unauthenticated requests get no key and are not limited.
+func KeyByAPIKeyID(c *fiber.Ctx) string {
+	if apiKeyID := c.Locals("api_key_id"); apiKeyID != nil {
+		return fmt.Sprintf("%v", apiKeyID)
+	}
+	return ""
+}
+
+// KeyByIP is a key generator that identifies clients by IP address
+func KeyByIP(c *fiber.Ctx) string {
+	return c.IP()
+}
+
 // ByIP creates a rate limiter that limits by IP address
 func (m *RateLimitMiddleware) ByIP(requests int, window time.Duration) fiber.Handler {
 	config := &RateLimitConfig{
 		Requests:     requests,
 		Window:       window,
-		KeyGenerator: func(c *fiber.Ctx) string { return c.IP() },
+		KeyGenerator: KeyByIP,
 	}
 	return m.createHandler("ip", config)
 }
@@ -93,10 +141,10 @@ func (m *RateLimitMiddleware) createHandler(name string, config *RateLimitConfig
 			config.Window,
 		)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "rate_limit_check_failed",
-				"message": "Failed to check rate limit",
-				"details": err.Error(),
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeRateLimitCheckFailed,
+				Message: "Failed to check rate limit",
+				Details: err.Error(),
 			})
 		}
 
@@ -106,8 +154,8 @@ func (m *RateLimitMiddleware) createHandler(name string, config *RateLimitConfig
 		c.Set("X-RateLimit-Reset", strconv.FormatInt(resetTime, 10))
 
 		if !allowed {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error":          "rate_limit_exceeded",
+			return c.Status(domain.ErrCodeRateLimitExceeded.HTTPStatus()).JSON(fiber.Map{
+				"error":          domain.ErrCodeRateLimitExceeded,
 				"message":        "Rate limit exceeded",
 				"limit":          config.Requests,
 				"window_seconds": int(config.Window.Seconds()),
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReadinessGate decides whether the instance should receive new traffic. Shutdown
+// marks it not ready before the server stops, so load balancers and service
+// discovery can stop routing here while in-flight requests finish. Implementations
+// may also deregister from a registry such as Consul when SetReady(false) is called.
+type ReadinessGate interface {
+	Ready() bool
+	SetReady(ready bool)
+}
+
+// AtomicReadinessGate is an in-process ReadinessGate, observed through /health/ready
+type AtomicReadinessGate struct {
+	ready atomic.Bool
+}
+
+// NewAtomicReadinessGate creates a gate that starts out ready
+func NewAtomicReadinessGate() *AtomicReadinessGate {
+	g := &AtomicReadinessGate{}
+	g.ready.Store(true)
+	return g
+}
+
+// Ready reports whether the instance accepts new traffic
+func (g *AtomicReadinessGate) Ready() bool {
+	return g.ready.Load()
+}
+
+// SetReady marks the instance ready or not ready
+func (g *AtomicReadinessGate) SetReady(ready bool) {
+	g.ready.Store(ready)
+}
+
+// ReadinessCheck handles readiness probes. A nil auditHealth leaves the audit
+// check out of the response.
+// @Summary Readiness check
+// @Description Report whether the instance should receive traffic. Returns 503 while the service is draining before shutdown, and status "degraded" (still 200) while audit events are failing to persist.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /health/ready [get]
+func ReadinessCheck(gate ReadinessGate, auditHealth audit.HealthReporter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !gate.Ready() {
+			return RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeServiceUnavailable,
+				Message: "Service is draining and no longer accepts traffic",
+			})
+		}
+
+		response := dto.HealthResponse{
+			Status:    "ready",
+			Timestamp: time.Now(),
+			Service:   "auth-service",
+			Version:   "1.0.0",
+		}
+
+		// A failing audit table degrades the instance but does not take it out of
+		// rotation: every instance shares the table, so all would go unready at once
+		if auditHealth != nil {
+			health := auditHealth.Health()
+			check := dto.ComponentHealth{
+				Status:            "ok",
+				RecentWriteErrors: health.RecentWriteErrors,
+				LastWriteError:    health.LastWriteError,
+				Spilled:           health.Spilled,
+			}
+			if health.Degraded {
+				check.Status = "degraded"
+				response.Status = "degraded"
+			}
+			response.Checks = map[string]dto.ComponentHealth{"audit": check}
+		}
+
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// RecoverMiddleware turns handler panics into a plain 500 and records them.
+// The panic value and stack trace go to the server log and the audit log only;
+// the client never sees them.
+type RecoverMiddleware struct {
+	auditLogger audit.AuditLoggerInterface
+}
+
+// NewRecoverMiddleware creates a new RecoverMiddleware
+func NewRecoverMiddleware(auditLogger audit.AuditLoggerInterface) *RecoverMiddleware {
+	return &RecoverMiddleware{
+		auditLogger: auditLogger,
+	}
+}
+
+// Handler returns the middleware. Register it first so it wraps every other
+// handler, and after the requestid middleware so the request ID is available.
+func (m *RecoverMiddleware) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			requestID, _ := c.Locals(requestid.ConfigDefault.ContextKey).(string)
+			route := c.Route().Path
+
+			log.Printf("panic recovered: method=%s route=%s path=%s request_id=%s error=%q\n%s",
+				c.Method(), route, c.Path(), requestID, fmt.Sprint(r), debug.Stack())
+
+			event := &audit.AuditEvent{
+				EventType: audit.EventTypePanic,
+				IPAddress: c.IP(),
+				UserAgent: c.Get("User-Agent"),
+				Success:   false,
+				Details: map[string]string{
+					"method":     c.Method(),
+					"route":      route,
+					"request_id": requestID,
+					"error":      fmt.Sprint(r),
+				},
+			}
+			if accountID, err := GetAccountID(c); err == nil {
+				event.AccountID = &accountID
+			}
+			if apiKeyID, err := GetAPIKeyID(c); err == nil {
+				event.APIKeyID = &apiKeyID
+			}
+			m.auditLogger.LogEvent(context.Background(), event)
+
+			err = RespondErrorWith(c, dto.ErrorResponse{
+				Error:   domain.ErrCodeInternalError,
+				Message: domain.ErrCodeInternalError.DefaultMessage(),
+			})
+		}()
+
+		return c.Next()
+	}
+}
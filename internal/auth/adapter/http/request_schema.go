@@ -0,0 +1,45 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationSchemaJSON is the media type JSON Schema documents are served as
+const MIMEApplicationSchemaJSON = "application/schema+json"
+
+// RequestSchema serves the JSON Schema generated from a request DTO, so clients
+// can validate bodies before sending them. Schemas are marshalled directly rather
+// than through c.JSON: property names must match the snake_case request fields
+// even when JSON_NAMING rewrites response keys.
+// @Summary Get a request body JSON Schema
+// @Description Return the JSON Schema for a request body, generated from the request DTO. Available names: register-app, issue-api-key.
+// @Tags schemas
+// @Produce json
+// @Param name path string true "Schema name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/v1/auth/schemas/{name} [get]
+func RequestSchema(c *fiber.Ctx) error {
+	name := c.Params("name")
+	schema, ok := dto.RequestSchema(name)
+	if !ok {
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeNotFound,
+			Message: "Schema not found",
+			Details: "available schemas: " + strings.Join(dto.RequestSchemaNames(), ", "),
+		})
+	}
+
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return RespondError(c, domain.ErrCodeInternalError)
+	}
+
+	c.Set(fiber.HeaderContentType, MIMEApplicationSchemaJSON)
+	return c.Status(fiber.StatusOK).Send(body)
+}
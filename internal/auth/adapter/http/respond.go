@@ -0,0 +1,66 @@
+package http
+
+import (
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationProblemJSON is the RFC 7807 problem details media type
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// MIMEApplicationNDJSON is newline-delimited JSON, used for streamed lists
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// problemTypePrefix namespaces problem type URIs; the error code is appended
+const problemTypePrefix = "urn:auth-service:error:"
+
+// RespondError writes the catalog status and default message for the error code
+func RespondError(c *fiber.Ctx, code domain.ErrorCode) error {
+	return RespondErrorWith(c, dto.ErrorResponse{Error: code})
+}
+
+// RespondErrorWith writes an error response using the catalog status for its code.
+// An empty message is filled in with the code's default message.
+func RespondErrorWith(c *fiber.Ctx, response dto.ErrorResponse) error {
+	return RespondErrorWithStatus(c, response.Error.HTTPStatus(), response)
+}
+
+// RespondErrorWithStatus writes an error response with an explicit status, for
+// errors that do not originate from the catalog (e.g. Fiber routing errors).
+// Clients that prefer application/problem+json in Accept get RFC 7807 problem
+// details; everyone else gets the ErrorResponse JSON shape.
+func RespondErrorWithStatus(c *fiber.Ctx, status int, response dto.ErrorResponse) error {
+	if response.Message == "" {
+		response.Message = response.Error.DefaultMessage()
+	}
+
+	if c.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationProblemJSON) == MIMEApplicationProblemJSON {
+		return c.Status(status).JSON(toProblemDetails(status, response), MIMEApplicationProblemJSON)
+	}
+
+	return c.Status(status).JSON(response)
+}
+
+// NotFound answers requests that matched no route. Register it after every
+// other route so it only sees what Fiber would otherwise turn into a plain 404.
+func NotFound(c *fiber.Ctx) error {
+	return RespondErrorWith(c, dto.ErrorResponse{
+		Error:   domain.ErrCodeNotFound,
+		Details: c.Method() + " " + c.Path(),
+	})
+}
+
+// toProblemDetails maps an ErrorResponse onto RFC 7807 fields. The title is the
+// code's fixed catalog message; the request-specific message becomes the detail.
+func toProblemDetails(status int, response dto.ErrorResponse) dto.ProblemDetails {
+	return dto.ProblemDetails{
+		Type:    problemTypePrefix + string(response.Error),
+		Title:   response.Error.DefaultMessage(),
+		Status:  status,
+		Detail:  response.Message,
+		Code:    response.Error,
+		Details: response.Details,
+		Fields:  response.Fields,
+	}
+}
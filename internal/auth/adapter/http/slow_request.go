@@ -0,0 +1,34 @@
+package http
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SlowRequestLogger logs a warning for every request that takes longer than
+// threshold, naming the route pattern and, once authenticated, the account, so
+// slow dependencies such as throttled DynamoDB calls show up in the logs with
+// enough context to act on. Register it early so the whole chain is timed.
+func SlowRequestLogger(threshold time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		elapsed := time.Since(start)
+		if elapsed <= threshold {
+			return err
+		}
+
+		account := "-"
+		if accountID, idErr := GetAccountID(c); idErr == nil {
+			account = accountID.String()
+		}
+		log.Printf("WARNING: slow request: %s %s took %s (threshold %s, account %s, status %d, request_id %s)",
+			c.Method(), c.Route().Path, elapsed.Round(time.Millisecond), threshold, account,
+			c.Response().StatusCode(), c.GetRespHeader(fiber.HeaderXRequestID))
+
+		return err
+	}
+}
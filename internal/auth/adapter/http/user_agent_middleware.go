@@ -0,0 +1,51 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireUserAgent rejects requests to the given paths that send no User-Agent.
+// Crude bots often omit the header, so this filters them cheaply on public
+// endpoints; any real client library sets one. Other paths pass through.
+// Paths are compared the way the router matches them, so a trailing slash or
+// different case cannot reach a guarded route unchecked.
+func RequireUserAgent(paths ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if strings.TrimSpace(c.Get(fiber.HeaderUserAgent)) != "" || !routeMatchesAny(c.App().Config(), c.Path(), paths) {
+			return c.Next()
+		}
+
+		return RespondErrorWith(c, dto.ErrorResponse{
+			Error:   domain.ErrCodeInvalidRequest,
+			Message: "A User-Agent header is required",
+		})
+	}
+}
+
+// routeMatchesAny reports whether the router would treat path as one of paths
+func routeMatchesAny(cfg fiber.Config, path string, paths []string) bool {
+	path = routingForm(cfg, path)
+	for _, p := range paths {
+		if routingForm(cfg, p) == path {
+			return true
+		}
+	}
+	return false
+}
+
+// routingForm normalizes path as the router does before matching: lowercased
+// unless routing is case-sensitive, and without trailing slashes unless routing
+// is strict
+func routingForm(cfg fiber.Config, path string) string {
+	if !cfg.CaseSensitive {
+		path = strings.ToLower(path)
+	}
+	if !cfg.StrictRouting && len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	return path
+}
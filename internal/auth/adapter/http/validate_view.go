@@ -0,0 +1,41 @@
+package http
+
+import "time"
+
+// ValidateResponseConfig controls how much of a key's permissions the public
+// validate endpoints reveal
+type ValidateResponseConfig struct {
+	// PermissionScope, when set, replaces the raw permissions list in validate
+	// responses with one true/false entry per listed permission, so gateways
+	// learn only what they need to decide. Empty returns the full list.
+	PermissionScope []string
+	// AnonymizeExpired answers for an expired key, once any grace period is over,
+	// with a bare valid:false, the same as for an unknown key, so the response
+	// does not reveal that the key existed or whose it was
+	AnonymizeExpired bool
+}
+
+// hidesKey reports whether a failed validation must not identify the key
+func (c ValidateResponseConfig) hidesKey(valid bool, expiresAt *time.Time) bool {
+	return c.AnonymizeExpired && !valid && expiresAt != nil && time.Now().After(*expiresAt)
+}
+
+// permissionView returns what a validate response shows for the key's
+// permissions: the raw list, or checks for the configured scope only
+func (c ValidateResponseConfig) permissionView(permissions []string) ([]string, map[string]bool) {
+	if len(c.PermissionScope) == 0 {
+		return permissions, nil
+	}
+
+	checks := make(map[string]bool, len(c.PermissionScope))
+	for _, scoped := range c.PermissionScope {
+		checks[scoped] = false
+		for _, perm := range permissions {
+			if perm == scoped {
+				checks[scoped] = true
+				break
+			}
+		}
+	}
+	return nil, checks
+}
@@ -0,0 +1,49 @@
+package audit
+
+// DetailLevel controls how much of a request an audit event's Details keep
+type DetailLevel string
+
+// Audit detail levels
+const (
+	// DetailNone records the event without any details
+	DetailNone DetailLevel = "none"
+	// DetailSummary keeps only the outcome keys in summaryDetailKeys
+	DetailSummary DetailLevel = "summary"
+	// DetailFull keeps every detail the handler recorded, including request values
+	DetailFull DetailLevel = "full"
+)
+
+// summaryDetailKeys describe how a request went rather than what it carried,
+// so they survive DetailSummary. Error messages are dropped because they often
+// quote request values.
+var summaryDetailKeys = []string{"success", "batch", "self", "source"}
+
+// IsValid checks if the level is one of the known detail levels
+func (l DetailLevel) IsValid() bool {
+	switch l {
+	case DetailNone, DetailSummary, DetailFull:
+		return true
+	}
+	return false
+}
+
+// Apply returns the part of details the level keeps. Unknown levels keep
+// everything, like DetailFull.
+func (l DetailLevel) Apply(details map[string]string) map[string]string {
+	switch l {
+	case DetailNone:
+		return nil
+	case DetailSummary:
+		var summary map[string]string
+		for _, key := range summaryDetailKeys {
+			if value, ok := details[key]; ok {
+				if summary == nil {
+					summary = make(map[string]string, len(summaryDetailKeys))
+				}
+				summary[key] = value
+			}
+		}
+		return summary
+	}
+	return details
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
@@ -14,16 +17,108 @@ import (
 	"github.com/aws-payment-gateway/internal/common/db"
 )
 
+// Audit event types
+const (
+	EventTypeAuthentication    = "authentication"
+	EventTypeAPIKeyCreated     = "api_key_created"
+	EventTypeAPIKeyRevoked     = "api_key_revoked"
+	EventTypeAPIKeyPaused      = "api_key_paused"
+	EventTypeAPIKeyResumed     = "api_key_resumed"
+	EventTypeAPIKeyRegenerated = "api_key_regenerated"
+	// EventTypeAPIKeyPermissionsChanged details carry the previous and new
+	// permissions plus what was added and removed, each comma-separated
+	EventTypeAPIKeyPermissionsChanged = "api_key_permissions_changed"
+	EventTypeAccountCreated           = "account_created"
+	EventTypeWebhookReset             = "webhook_reset"
+	EventTypeWebhookDisabled          = "webhook_disabled"
+	EventTypeAccountPurged            = "account_purged"
+	EventTypeKeysDisabled             = "account_keys_disabled"
+	EventTypeKeysEnabled              = "account_keys_enabled"
+	EventTypePanic                    = "panic"
+)
+
+// knownEventTypes lists every event type the service records
+var knownEventTypes = []string{
+	EventTypeAuthentication, EventTypeAPIKeyCreated, EventTypeAPIKeyRevoked,
+	EventTypeAPIKeyPaused, EventTypeAPIKeyResumed, EventTypeAPIKeyRegenerated,
+	EventTypeAPIKeyPermissionsChanged,
+	EventTypeAccountCreated, EventTypeWebhookReset, EventTypeWebhookDisabled,
+	EventTypeAccountPurged, EventTypeKeysDisabled, EventTypeKeysEnabled,
+	EventTypePanic,
+}
+
+// EventRetention is how long persisted audit events are kept before DynamoDB TTL deletes them
+const EventRetention = 90 * 24 * time.Hour
+
 // AuditLoggerInterface defines the interface for audit logging
 type AuditLoggerInterface interface {
+	LogEvent(ctx context.Context, event *AuditEvent)
 	LogAuthentication(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, success bool, details map[string]string)
 	LogAPIKeyCreation(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, details map[string]string)
 	LogAPIKeyRevocation(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, details map[string]string)
 	LogAccountCreation(ctx context.Context, accountID *uuid.UUID, accountName *string, ipAddress, userAgent string, details map[string]string)
 }
 
+// AuthenticationCounter counts successful authentications recorded in the audit log
+type AuthenticationCounter interface {
+	CountAuthentications(ctx context.Context, accountID uuid.UUID, since time.Time) (int, error)
+}
+
+// EventQuery selects audit events of one type within a time window
+type EventQuery struct {
+	EventType string
+	// AccountID restricts results to one account's events; nil returns every account's
+	AccountID *uuid.UUID
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// EventReader lists persisted audit events, newest first
+type EventReader interface {
+	ListEvents(ctx context.Context, query EventQuery) ([]*AuditEvent, error)
+}
+
+// EventPurger hard-deletes persisted audit events
+type EventPurger interface {
+	// PurgeAccountEvents deletes every retained event recorded for the account
+	// and returns how many were removed
+	PurgeAccountEvents(ctx context.Context, accountID uuid.UUID) (int, error)
+}
+
+// HealthWindow is how far back PipelineHealth counts failed writes
+const HealthWindow = 5 * time.Minute
+
+// PipelineHealth describes whether audit events are being persisted
+type PipelineHealth struct {
+	// Degraded is set while the most recent write failed
+	Degraded bool
+	// RecentWriteErrors counts failed writes within HealthWindow
+	RecentWriteErrors int
+	LastWriteError    *time.Time
+	// Spilled counts events waiting in the spillover to be written back
+	Spilled int
+}
+
+// HealthReporter reports the state of the audit pipeline
+type HealthReporter interface {
+	Health() PipelineHealth
+}
+
+// IsKnownEventType checks if an event type is one the service records
+func IsKnownEventType(eventType string) bool {
+	for _, known := range knownEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
 // AuditEvent represents an audit log event
 type AuditEvent struct {
+	// ID is assigned when the event is logged and makes its sort key unique
+	ID         uuid.UUID         `json:"id"`
 	Timestamp  time.Time         `json:"timestamp"`
 	EventType  string            `json:"event_type"`
 	AccountID  *uuid.UUID        `json:"account_id,omitempty"`
@@ -35,15 +130,52 @@ type AuditEvent struct {
 	Details    map[string]string `json:"details,omitempty"`
 }
 
+// AuditLoggerConfig controls which audit events are persisted
+type AuditLoggerConfig struct {
+	// EventTypes is an allowlist of event types to persist. Empty persists all types.
+	EventTypes []string
+	// SkipAuthenticationSuccess drops successful authentication events,
+	// which are high-volume, while still persisting failures
+	SkipAuthenticationSuccess bool
+	// AuthSuccessSampleRate is the fraction (0.0-1.0] of successful authentication
+	// events to persist. Zero means unset and persists all of them, like 1; use
+	// SkipAuthenticationSuccess to persist none. Failures are always persisted.
+	AuthSuccessSampleRate float64
+	// Spillover, if set, keeps events whose DynamoDB write failed so
+	// DrainSpillover can write them later; nil drops them
+	Spillover *Spillover
+}
+
 // DynamoDBAuditLogger handles logging of audit events to DynamoDB
 type DynamoDBAuditLogger struct {
-	client *db.DynamoDBClient
+	client        *db.DynamoDBClient
+	config        AuditLoggerConfig
+	enabledEvents map[string]bool
+	sample        func() float64
+
+	// Write outcomes for Health
+	healthMu      sync.Mutex
+	lastFailed    bool
+	writeFailures []time.Time
 }
 
 // NewDynamoDBAuditLogger creates a new DynamoDBAuditLogger
-func NewDynamoDBAuditLogger(client *db.DynamoDBClient) *DynamoDBAuditLogger {
+func NewDynamoDBAuditLogger(client *db.DynamoDBClient, config AuditLoggerConfig) *DynamoDBAuditLogger {
+	enabledEvents := make(map[string]bool, len(config.EventTypes))
+	for _, eventType := range config.EventTypes {
+		enabledEvents[eventType] = true
+	}
+
+	// A zero-value config must not silently drop every successful authentication
+	if config.AuthSuccessSampleRate <= 0 {
+		config.AuthSuccessSampleRate = 1
+	}
+
 	return &DynamoDBAuditLogger{
-		client: client,
+		client:        client,
+		config:        config,
+		enabledEvents: enabledEvents,
+		sample:        rand.Float64,
 	}
 }
 
@@ -53,112 +185,186 @@ type DynamoDBAuditEvent struct {
 	PK  string `dynamodbav:"pk" json:"pk"`
 	SK  string `dynamodbav:"sk" json:"sk"`
 	TTL int64  `dynamodbav:"ttl" json:"ttl"` // For automatic cleanup (90 days)
+	// Sparse index of successful authentications by account, for counting
+	GSI1PK string `dynamodbav:"gsi1pk,omitempty" json:"gsi1pk,omitempty"`
+	GSI1SK string `dynamodbav:"gsi1sk,omitempty" json:"gsi1sk,omitempty"`
 }
 
-// LogAuthentication logs an authentication event to DynamoDB
-func (a *DynamoDBAuditLogger) LogAuthentication(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, success bool, details map[string]string) {
-	// Create DynamoDB event
-	event := &DynamoDBAuditEvent{
-		AuditEvent: AuditEvent{
-			Timestamp:  time.Now(),
-			EventType:  "authentication",
-			AccountID:  accountID,
-			APIKeyID:   apiKeyID,
-			APIKeyName: apiKeyName,
-			IPAddress:  ipAddress,
-			UserAgent:  userAgent,
-			Success:    success,
-			Details:    details,
-		},
-		PK:  a.createPartitionKey("authentication", time.Now()),
-		SK:  a.createSortKey(time.Now()),
-		TTL: time.Now().Add(90 * 24 * time.Hour).Unix(), // 90-day TTL
+// shouldPersist reports whether an event passes the configured allowlist
+func (a *DynamoDBAuditLogger) shouldPersist(event *AuditEvent) bool {
+	if len(a.enabledEvents) > 0 && !a.enabledEvents[event.EventType] {
+		return false
 	}
 
-	// Store in DynamoDB with error handling
-	if err := a.storeAuditEvent(ctx, event); err != nil {
-		// Log error but don't fail the request
-		log.Printf("Failed to store authentication audit event in DynamoDB: %v", err)
+	if event.EventType == EventTypeAuthentication && event.Success {
+		if a.config.SkipAuthenticationSuccess {
+			return false
+		}
+		if a.config.AuthSuccessSampleRate < 1 && a.sample() >= a.config.AuthSuccessSampleRate {
+			return false
+		}
 	}
+
+	return true
 }
 
-// LogAPIKeyCreation logs an API key creation event to DynamoDB
-func (a *DynamoDBAuditLogger) LogAPIKeyCreation(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, details map[string]string) {
+// LogEvent persists an audit event to DynamoDB if its type is enabled
+func (a *DynamoDBAuditLogger) LogEvent(ctx context.Context, event *AuditEvent) {
+	if !a.shouldPersist(event) {
+		return
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+
 	// Create DynamoDB event
-	event := &DynamoDBAuditEvent{
-		AuditEvent: AuditEvent{
-			Timestamp:  time.Now(),
-			EventType:  "api_key_created",
-			AccountID:  accountID,
-			APIKeyID:   apiKeyID,
-			APIKeyName: apiKeyName,
-			IPAddress:  ipAddress,
-			UserAgent:  userAgent,
-			Success:    true,
-			Details:    details,
-		},
-		PK:  a.createPartitionKey("api_key_created", time.Now()),
-		SK:  a.createSortKey(time.Now()),
-		TTL: time.Now().Add(90 * 24 * time.Hour).Unix(), // 90-day TTL
+	dynamoEvent := &DynamoDBAuditEvent{
+		AuditEvent: *event,
+		PK:         a.createPartitionKey(event.EventType, event.Timestamp),
+		SK:         a.createSortKey(event.Timestamp, event.ID),
+		TTL:        event.Timestamp.Add(EventRetention).Unix(),
+	}
+	if event.EventType == EventTypeAuthentication && event.Success && event.AccountID != nil {
+		dynamoEvent.GSI1PK = authSuccessIndexKey(*event.AccountID)
+		dynamoEvent.GSI1SK = dynamoEvent.SK
+	}
+
+	// Store in DynamoDB with error handling. The SDK has already retried by the
+	// time an error comes back, so the event goes straight to the spillover.
+	err := a.storeAuditEvent(ctx, dynamoEvent)
+	a.recordWrite(err)
+	if err == nil {
+		return
 	}
 
-	// Store in DynamoDB with error handling
-	if err := a.storeAuditEvent(ctx, event); err != nil {
-		// Log error but don't fail request
-		log.Printf("Failed to store account creation audit event in DynamoDB: %v", err)
+	// Log error but don't fail the request
+	if a.config.Spillover == nil {
+		log.Printf("Failed to store %s audit event in DynamoDB: %v", event.EventType, err)
+		return
+	}
+	if spillErr := a.config.Spillover.Append(dynamoEvent); spillErr != nil {
+		log.Printf("Failed to store %s audit event in DynamoDB (%v) or the spillover: %v", event.EventType, err, spillErr)
+		return
 	}
+	log.Printf("Failed to store %s audit event in DynamoDB, spilled locally: %v", event.EventType, err)
 }
 
-// LogAPIKeyRevocation logs an API key revocation event to DynamoDB
-func (a *DynamoDBAuditLogger) LogAPIKeyRevocation(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, details map[string]string) {
-	// Create DynamoDB event
-	event := &DynamoDBAuditEvent{
-		AuditEvent: AuditEvent{
-			Timestamp:  time.Now(),
-			EventType:  "api_key_revoked",
-			AccountID:  accountID,
-			APIKeyID:   apiKeyID,
-			APIKeyName: apiKeyName,
-			IPAddress:  ipAddress,
-			UserAgent:  userAgent,
-			Success:    true,
-			Details:    details,
-		},
-		PK:  a.createPartitionKey("api_key_revoked", time.Now()),
-		SK:  a.createSortKey(time.Now()),
-		TTL: time.Now().Add(90 * 24 * time.Hour).Unix(), // 90-day TTL
+// DrainSpillover writes events kept by the spillover back to DynamoDB, oldest
+// first, and returns how many were written. It stops at the first failed write.
+// Without a spillover there is nothing to drain.
+func (a *DynamoDBAuditLogger) DrainSpillover(ctx context.Context) (int, error) {
+	if a.config.Spillover == nil {
+		return 0, nil
 	}
 
-	// Store in DynamoDB with error handling
-	if err := a.storeAuditEvent(ctx, event); err != nil {
-		// Log error but don't fail request
-		log.Printf("Failed to store API key creation audit event in DynamoDB: %v", err)
+	return a.config.Spillover.Drain(ctx, func(ctx context.Context, event *DynamoDBAuditEvent) error {
+		err := a.storeAuditEvent(ctx, event)
+		a.recordWrite(err)
+		return err
+	})
+}
+
+// recordWrite remembers the outcome of a write for Health
+func (a *DynamoDBAuditLogger) recordWrite(err error) {
+	a.healthMu.Lock()
+	defer a.healthMu.Unlock()
+
+	a.lastFailed = err != nil
+	if err != nil {
+		a.writeFailures = append(pruneBefore(a.writeFailures, time.Now().Add(-HealthWindow)), time.Now())
 	}
 }
 
-// LogAccountCreation logs an account creation event to DynamoDB
-func (a *DynamoDBAuditLogger) LogAccountCreation(ctx context.Context, accountID *uuid.UUID, accountName *string, ipAddress, userAgent string, details map[string]string) {
-	// Create DynamoDB event
-	event := &DynamoDBAuditEvent{
-		AuditEvent: AuditEvent{
-			Timestamp: time.Now(),
-			EventType: "account_created",
-			AccountID: accountID,
-			IPAddress: ipAddress,
-			UserAgent: userAgent,
-			Success:   true,
-			Details:   details,
-		},
-		PK:  a.createPartitionKey("account_created", time.Now()),
-		SK:  a.createSortKey(time.Now()),
-		TTL: time.Now().Add(90 * 24 * time.Hour).Unix(), // 90-day TTL
+// Health reports recent write failures and, with a spillover, how many events
+// are waiting in it. Writes are synchronous, so there is no buffer to report on.
+func (a *DynamoDBAuditLogger) Health() PipelineHealth {
+	a.healthMu.Lock()
+	defer a.healthMu.Unlock()
+
+	a.writeFailures = pruneBefore(a.writeFailures, time.Now().Add(-HealthWindow))
+	health := PipelineHealth{
+		Degraded:          a.lastFailed,
+		RecentWriteErrors: len(a.writeFailures),
 	}
+	if n := len(a.writeFailures); n > 0 {
+		last := a.writeFailures[n-1]
+		health.LastWriteError = &last
+	}
+	if a.config.Spillover != nil {
+		health.Spilled = a.config.Spillover.Pending()
+	}
+	return health
+}
 
-	// Store in DynamoDB with error handling
-	if err := a.storeAuditEvent(ctx, event); err != nil {
-		// Log error but don't fail request
-		log.Printf("Failed to store API key revocation audit event in DynamoDB: %v", err)
+// pruneBefore drops the leading times that are before cutoff; times are in order
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
 	}
+	return times[i:]
+}
+
+// LogAuthentication logs an authentication event to DynamoDB
+func (a *DynamoDBAuditLogger) LogAuthentication(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, success bool, details map[string]string) {
+	a.LogEvent(ctx, &AuditEvent{
+		Timestamp:  time.Now().UTC(),
+		EventType:  EventTypeAuthentication,
+		AccountID:  accountID,
+		APIKeyID:   apiKeyID,
+		APIKeyName: apiKeyName,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    success,
+		Details:    details,
+	})
+}
+
+// LogAPIKeyCreation logs an API key creation event to DynamoDB
+func (a *DynamoDBAuditLogger) LogAPIKeyCreation(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, details map[string]string) {
+	a.LogEvent(ctx, &AuditEvent{
+		Timestamp:  time.Now().UTC(),
+		EventType:  EventTypeAPIKeyCreated,
+		AccountID:  accountID,
+		APIKeyID:   apiKeyID,
+		APIKeyName: apiKeyName,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    true,
+		Details:    details,
+	})
+}
+
+// LogAPIKeyRevocation logs an API key revocation event to DynamoDB
+func (a *DynamoDBAuditLogger) LogAPIKeyRevocation(ctx context.Context, accountID, apiKeyID *uuid.UUID, apiKeyName *string, ipAddress, userAgent string, details map[string]string) {
+	a.LogEvent(ctx, &AuditEvent{
+		Timestamp:  time.Now().UTC(),
+		EventType:  EventTypeAPIKeyRevoked,
+		AccountID:  accountID,
+		APIKeyID:   apiKeyID,
+		APIKeyName: apiKeyName,
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Success:    true,
+		Details:    details,
+	})
+}
+
+// LogAccountCreation logs an account creation event to DynamoDB
+func (a *DynamoDBAuditLogger) LogAccountCreation(ctx context.Context, accountID *uuid.UUID, accountName *string, ipAddress, userAgent string, details map[string]string) {
+	a.LogEvent(ctx, &AuditEvent{
+		Timestamp: time.Now().UTC(),
+		EventType: EventTypeAccountCreated,
+		AccountID: accountID,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Success:   true,
+		Details:   details,
+	})
 }
 
 // QueryAuditLogs queries audit logs with filtering options
@@ -224,23 +430,183 @@ func (a *DynamoDBAuditLogger) QueryAuditLogs(ctx context.Context, eventType stri
 	return events, nil
 }
 
-// createPartitionKey creates a partition key for audit events
+// CountAuthentications counts an account's successful authentications since the given time.
+// It reads only that account's entries in the sparse gsi1 index, so the cost grows with
+// the account's own traffic rather than with every account's authentications that day.
+// Only persisted events are counted: sampled or skipped successes are not included.
+func (a *DynamoDBAuditLogger) CountAuthentications(ctx context.Context, accountID uuid.UUID, since time.Time) (int, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(a.client.GetTableName()),
+		IndexName:              aws.String(db.GSI1IndexName),
+		KeyConditionExpression: aws.String("gsi1pk = :pk AND gsi1sk >= :since"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":    &types.AttributeValueMemberS{Value: authSuccessIndexKey(accountID)},
+			":since": &types.AttributeValueMemberS{Value: a.sortKeyPrefix(since)},
+		},
+	}
+
+	count, err := a.client.QueryCount(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count authentication events: %w", err)
+	}
+
+	return count, nil
+}
+
+// authSuccessIndexKey is the gsi1 partition holding an account's successful authentications
+func authSuccessIndexKey(accountID uuid.UUID) string {
+	return fmt.Sprintf("AUTH_SUCCESS#%s", accountID.String())
+}
+
+// ListEvents returns up to query.Limit events of query.EventType, newest first.
+// Events are partitioned by type and day, so the window is walked one day
+// partition at a time from Until back to Since. Some types share a partition
+// and account filtering happens after the read, so a narrow query can read
+// more items than it returns.
+func (a *DynamoDBAuditLogger) ListEvents(ctx context.Context, query EventQuery) ([]*AuditEvent, error) {
+	var events []*AuditEvent
+	errLimitReached := fmt.Errorf("limit reached")
+
+	for day := query.Until.UTC().Truncate(24 * time.Hour); !day.Before(query.Since.UTC().Truncate(24 * time.Hour)); day = day.Add(-24 * time.Hour) {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(a.client.GetTableName()),
+			KeyConditionExpression: aws.String("pk = :pk AND sk BETWEEN :from AND :to"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":pk":   &types.AttributeValueMemberS{Value: a.createPartitionKey(query.EventType, day)},
+				":from": &types.AttributeValueMemberS{Value: a.sortKeyPrefix(maxTime(query.Since, day))},
+				":to":   &types.AttributeValueMemberS{Value: a.sortKeyUpperBound(minTime(query.Until, day.Add(24*time.Hour-time.Second)))},
+			},
+			ScanIndexForward: aws.Bool(false),
+		}
+
+		err := a.client.QueryPages(ctx, input, func(items []map[string]types.AttributeValue) error {
+			var results []DynamoDBAuditEvent
+			if err := attributevalue.UnmarshalListOfMaps(items, &results); err != nil {
+				return fmt.Errorf("failed to unmarshal audit events: %w", err)
+			}
+
+			for i := range results {
+				event := &results[i].AuditEvent
+				if event.EventType != query.EventType {
+					continue
+				}
+				if query.AccountID != nil && (event.AccountID == nil || *event.AccountID != *query.AccountID) {
+					continue
+				}
+				events = append(events, event)
+				if len(events) >= query.Limit {
+					return errLimitReached
+				}
+			}
+			return nil
+		})
+		if err == errLimitReached {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to query audit events: %w", err)
+		}
+	}
+
+	return events, nil
+}
+
+// PurgeAccountEvents deletes the account's events from every event type's day
+// partitions within EventRetention. There is no index by account, so each
+// partition is read in full and filtered here; expect this to be slow.
+func (a *DynamoDBAuditLogger) PurgeAccountEvents(ctx context.Context, accountID uuid.UUID) (int, error) {
+	now := time.Now().UTC()
+	purged := 0
+
+	for day := now.Truncate(24 * time.Hour); !day.Before(now.Add(-EventRetention).Truncate(24 * time.Hour)); day = day.Add(-24 * time.Hour) {
+		// Some event types share a partition; visit each partition once
+		seen := make(map[string]bool, len(knownEventTypes))
+		for _, eventType := range knownEventTypes {
+			pk := a.createPartitionKey(eventType, day)
+			if seen[pk] {
+				continue
+			}
+			seen[pk] = true
+
+			input := &dynamodb.QueryInput{
+				TableName:              aws.String(a.client.GetTableName()),
+				KeyConditionExpression: aws.String("pk = :pk"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":pk": &types.AttributeValueMemberS{Value: pk},
+				},
+			}
+
+			var results []DynamoDBAuditEvent
+			if err := a.client.QueryAllItems(ctx, input, &results); err != nil {
+				return purged, fmt.Errorf("failed to query audit events: %w", err)
+			}
+
+			for _, result := range results {
+				if result.AccountID == nil || *result.AccountID != accountID {
+					continue
+				}
+				key, err := db.CreateCompositeKey("pk", result.PK, "sk", result.SK)
+				if err != nil {
+					return purged, fmt.Errorf("failed to create key: %w", err)
+				}
+				if err := a.client.DeleteItem(ctx, key); err != nil {
+					return purged, fmt.Errorf("failed to delete audit event: %w", err)
+				}
+				purged++
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+// minTime returns the earlier of two times
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// maxTime returns the later of two times
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// createPartitionKey creates a partition key for audit events. Partitions are
+// UTC days, matching the day boundaries the readers walk.
 func (a *DynamoDBAuditLogger) createPartitionKey(eventType string, timestamp time.Time) string {
+	timestamp = timestamp.UTC()
 	switch eventType {
-	case "authentication":
+	case EventTypeAuthentication:
 		return fmt.Sprintf("AUDIT#AUTH#%s", timestamp.Format("2006-01-02"))
-	case "api_key_created", "api_key_revoked":
+	case EventTypeAPIKeyCreated, EventTypeAPIKeyRevoked:
 		return fmt.Sprintf("AUDIT#APIKEY#%s", timestamp.Format("2006-01-02"))
-	case "account_created":
+	case EventTypeAccountCreated:
 		return fmt.Sprintf("AUDIT#ACCOUNT#%s", timestamp.Format("2006-01-02"))
 	default:
 		return fmt.Sprintf("AUDIT#%s#%s", eventType, timestamp.Format("2006-01-02"))
 	}
 }
 
-// createSortKey creates a sort key for audit events
-func (a *DynamoDBAuditLogger) createSortKey(timestamp time.Time) string {
-	return fmt.Sprintf("%s#%s", timestamp.Format("2006-01-02"), timestamp.Unix())
+// createSortKey creates a sort key for audit events. The event ID keeps events
+// logged in the same second from overwriting each other.
+func (a *DynamoDBAuditLogger) createSortKey(timestamp time.Time, eventID uuid.UUID) string {
+	return fmt.Sprintf("%s#%s", a.sortKeyPrefix(timestamp), eventID.String())
+}
+
+// sortKeyPrefix is the part of the sort key shared by every event logged in the
+// same second. It sorts before all of them, so it serves as a lower bound.
+func (a *DynamoDBAuditLogger) sortKeyPrefix(timestamp time.Time) string {
+	return fmt.Sprintf("%s#%d", timestamp.UTC().Format("2006-01-02"), timestamp.Unix())
+}
+
+// sortKeyUpperBound sorts after every event logged in the timestamp's second
+func (a *DynamoDBAuditLogger) sortKeyUpperBound(timestamp time.Time) string {
+	return a.sortKeyPrefix(timestamp) + "#~"
 }
 
 // storeAuditEvent stores an audit event in DynamoDB with comprehensive error handling
@@ -256,10 +622,20 @@ func (a *DynamoDBAuditLogger) storeAuditEvent(ctx context.Context, event *Dynamo
 // GetEventDescription returns a human-readable description of an event type
 func GetEventDescription(eventType string) string {
 	descriptions := map[string]string{
-		"authentication":  "API key authentication attempt",
-		"api_key_created": "API key created",
-		"api_key_revoked": "API key revoked",
-		"account_created": "Account created",
+		EventTypeAuthentication:           "API key authentication attempt",
+		EventTypeAPIKeyCreated:            "API key created",
+		EventTypeAPIKeyRevoked:            "API key revoked",
+		EventTypeAPIKeyPaused:             "API key paused",
+		EventTypeAPIKeyResumed:            "API key resumed",
+		EventTypeAPIKeyRegenerated:        "API key secret regenerated",
+		EventTypeAPIKeyPermissionsChanged: "API key permissions changed",
+		EventTypeAccountCreated:           "Account created",
+		EventTypeWebhookReset:             "Account webhook URL reset",
+		EventTypeWebhookDisabled:          "Account webhook URL cleared after repeated delivery failures",
+		EventTypeAccountPurged:            "Account data purged",
+		EventTypeKeysDisabled:             "All account API keys disabled",
+		EventTypeKeysEnabled:              "All account API keys re-enabled",
+		EventTypePanic:                    "Request handler panicked",
 	}
 
 	if desc, exists := descriptions[eventType]; exists {
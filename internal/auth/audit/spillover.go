@@ -0,0 +1,209 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// ErrSpilloverFull is returned when appending would grow the spillover past its size limit
+var ErrSpilloverFull = errors.New("audit spillover is full")
+
+// drainingSuffix names the file holding the batch currently being written back
+const drainingSuffix = ".draining"
+
+// Spillover keeps audit events that could not be written to DynamoDB in a local
+// file, one JSON event per line, until they can be written back. Appends go to
+// the file at path; a drain first moves it aside to path.draining, so events
+// spilled during a drain are kept in order behind the batch being written back.
+// Both files survive restarts.
+type Spillover struct {
+	path     string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	pending int
+	// drainMu serializes drains; appends never wait for a drain's writes
+	drainMu sync.Mutex
+}
+
+// NewSpillover opens the spillover at path, counting events left by a previous
+// run. A maxBytes of zero or less leaves the file unbounded.
+func NewSpillover(path string, maxBytes int64) (*Spillover, error) {
+	s := &Spillover{path: path, maxBytes: maxBytes}
+
+	for _, file := range []string{path + drainingSuffix, path} {
+		lines, err := readLines(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			s.size += int64(len(line)) + 1
+			s.pending++
+		}
+	}
+
+	return s, nil
+}
+
+// Pending returns how many events are waiting to be written back
+func (s *Spillover) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending
+}
+
+// Append adds an event to the end of the spillover
+func (s *Spillover) Append(event *DynamoDBAuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.maxBytes > 0 && s.size+int64(len(line)) > s.maxBytes {
+		return ErrSpilloverFull
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit spillover: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit spillover: %w", err)
+	}
+
+	s.size += int64(len(line))
+	s.pending++
+	return nil
+}
+
+// Drain writes spilled events back with write, oldest first, and returns how
+// many were written. It stops at the first failed write, since the store is
+// most likely still down, and keeps that event and everything after it.
+func (s *Spillover) Drain(ctx context.Context, write func(context.Context, *DynamoDBAuditEvent) error) (int, error) {
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+
+	draining := s.path + drainingSuffix
+	drained := 0
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return drained, err
+		}
+
+		// Take the spilled events, unless a previous drain left a batch behind
+		if _, err := os.Stat(draining); errors.Is(err, os.ErrNotExist) {
+			moved, err := s.takeBatch(draining)
+			if err != nil || !moved {
+				return drained, err
+			}
+		}
+
+		lines, err := readLines(draining)
+		if err != nil {
+			return drained, err
+		}
+
+		for i, line := range lines {
+			var event DynamoDBAuditEvent
+			if err := json.Unmarshal(line, &event); err != nil {
+				// A torn line from a crash mid-append cannot be recovered
+				log.Printf("Discarding unreadable spilled audit event: %v", err)
+				s.release(line)
+				continue
+			}
+
+			if err := write(ctx, &event); err != nil {
+				if keepErr := writeLines(draining, lines[i:]); keepErr != nil {
+					return drained, keepErr
+				}
+				return drained, fmt.Errorf("failed to write back spilled audit event: %w", err)
+			}
+
+			s.release(line)
+			drained++
+		}
+
+		if err := os.Remove(draining); err != nil {
+			return drained, fmt.Errorf("failed to remove drained audit spillover: %w", err)
+		}
+	}
+}
+
+// takeBatch moves the spillover file aside for draining. It reports false when
+// nothing has been spilled.
+func (s *Spillover) takeBatch(draining string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Rename(s.path, draining); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to move audit spillover aside: %w", err)
+	}
+	return true, nil
+}
+
+// release accounts for a line that has left the spillover
+func (s *Spillover) release(line []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size -= int64(len(line)) + 1
+	s.pending--
+}
+
+// readLines returns the non-empty lines of a file; a missing file has none
+func readLines(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit spillover: %w", err)
+	}
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// writeLines replaces a file's contents with lines, going through a temporary
+// file so a crash cannot leave it half written
+func writeLines(path string, lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write audit spillover: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace audit spillover: %w", err)
+	}
+	return nil
+}
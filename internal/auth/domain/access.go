@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// AccessDenialReason explains why a key may not perform an operation
+type AccessDenialReason string
+
+const (
+	AccessDeniedKeyInactive       AccessDenialReason = "key_inactive"
+	AccessDeniedKeyExpired        AccessDenialReason = "key_expired"
+	AccessDeniedAccountSuspended  AccessDenialReason = "account_suspended"
+	AccessDeniedAccountInactive   AccessDenialReason = "account_inactive"
+	AccessDeniedKeysDisabled      AccessDenialReason = "keys_disabled"
+	AccessDeniedMissingPermission AccessDenialReason = "missing_permission"
+)
+
+// AccessDecision is the outcome of checking a key against an operation
+type AccessDecision struct {
+	Allowed bool
+	// Reason is empty when Allowed is true
+	Reason AccessDenialReason
+}
+
+// AccessPolicy decides whether an API key may use a permission, taking both the
+// key's and the account's state into account
+type AccessPolicy struct {
+	// SuspendedAllowedPermissions remain usable while the account is suspended
+	SuspendedAllowedPermissions []string
+	// ExpiryGracePeriod is how long expired keys keep working after ExpiresAt
+	ExpiryGracePeriod time.Duration
+	// PermissionAliases resolves alternative permission names before checking
+	PermissionAliases PermissionAliases
+}
+
+// Check returns whether key may use permission on behalf of account.
+// Key problems are reported before account problems, and both before permissions,
+// so the reason names the first thing the caller would have to fix.
+func (p AccessPolicy) Check(key *ApiKey, account *Account, permission string) AccessDecision {
+	permission = p.PermissionAliases.Canonical(permission)
+
+	switch {
+	case key.Status != ApiKeyStatusActive:
+		return AccessDecision{Reason: AccessDeniedKeyInactive}
+	case key.IsExpired() && !key.InGracePeriod(p.ExpiryGracePeriod):
+		return AccessDecision{Reason: AccessDeniedKeyExpired}
+	case account == nil || account.Status == AccountStatusDeleted:
+		return AccessDecision{Reason: AccessDeniedAccountInactive}
+	case account.KeysDisabled:
+		return AccessDecision{Reason: AccessDeniedKeysDisabled}
+	case account.Status == AccountStatusSuspended && !p.allowedWhileSuspended(permission):
+		return AccessDecision{Reason: AccessDeniedAccountSuspended}
+	case !key.HasPermission(permission):
+		return AccessDecision{Reason: AccessDeniedMissingPermission}
+	}
+
+	return AccessDecision{Allowed: true}
+}
+
+// allowedWhileSuspended checks the suspended-account allowlist
+func (p AccessPolicy) allowedWhileSuspended(permission string) bool {
+	return ApiKeyPermissions(p.SuspendedAllowedPermissions).Contains(permission)
+}
@@ -1,7 +1,12 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 )
@@ -23,6 +28,69 @@ type Account struct {
 	WebhookURL *string       `json:"webhook_url,omitempty" db:"webhook_url"`
 	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+	// Version is incremented on every update and guards against concurrent writes
+	Version int `json:"version" db:"version"`
+	// OwnerID is the tenant that owns the account; root accounts own themselves
+	OwnerID uuid.UUID `json:"owner_id" db:"owner_id"`
+	// KeyPrefix replaces the service-wide prefix on keys issued for this account; empty uses the default
+	KeyPrefix string `json:"key_prefix,omitempty" db:"key_prefix"`
+	// KeysDisabled rejects every key of the account regardless of its own status;
+	// clearing it restores the keys as they were
+	KeysDisabled bool `json:"keys_disabled" db:"keys_disabled"`
+	// RequireClientCert rejects the account's keys unless the request comes with
+	// a client certificate whose fingerprint is in ClientCertFingerprints
+	RequireClientCert bool `json:"require_client_cert" db:"require_client_cert"`
+	// ClientCertFingerprints are normalized SHA-256 certificate fingerprints
+	ClientCertFingerprints []string `json:"client_cert_fingerprints,omitempty" db:"client_cert_fingerprints"`
+	// AllowedPermissions caps what the account's keys may be issued with; empty means no cap
+	AllowedPermissions []string `json:"allowed_permissions,omitempty" db:"allowed_permissions"`
+}
+
+// ErrPermissionOutsideAccountScope is returned when a key would be issued with a
+// permission the account itself is not allowed
+var ErrPermissionOutsideAccountScope = errors.New("permission is outside the account's allowed permissions")
+
+// PermissionsOutsideScope returns the permissions not covered by the account's
+// AllowedPermissions, in order; wildcards in AllowedPermissions cover what they
+// match. Accounts without a cap allow everything.
+func (a *Account) PermissionsOutsideScope(permissions []string) []string {
+	if len(a.AllowedPermissions) == 0 {
+		return nil
+	}
+
+	allowed := ApiKeyPermissions(a.AllowedPermissions)
+	var outside []string
+	for _, perm := range permissions {
+		if !(ApiKeyPermissions{perm}).IsSubsetOf(allowed) {
+			outside = append(outside, perm)
+		}
+	}
+	return outside
+}
+
+// AllowsClientCert reports whether a request presenting the certificate
+// fingerprint may use the account's keys. Accounts without RequireClientCert
+// allow any request, including one without a certificate.
+func (a *Account) AllowsClientCert(fingerprint string) bool {
+	if !a.RequireClientCert {
+		return true
+	}
+	return MatchCertFingerprint(a.ClientCertFingerprints, fingerprint)
+}
+
+// MatchCertFingerprint reports whether fingerprint, once normalized, is in the
+// allowlist of normalized fingerprints. An empty fingerprint never matches.
+func MatchCertFingerprint(allowlist []string, fingerprint string) bool {
+	fingerprint = NormalizeCertFingerprint(fingerprint)
+	if fingerprint == "" {
+		return false
+	}
+	for _, allowed := range allowlist {
+		if allowed == fingerprint {
+			return true
+		}
+	}
+	return false
 }
 
 // IsValid checks if the account is in a valid state
@@ -30,14 +98,98 @@ func (a *Account) IsValid() bool {
 	return a.Status == AccountStatusActive
 }
 
+// ErrNameNotAllowed is returned when an account name has characters the name policy forbids
+var ErrNameNotAllowed = errors.New("name contains characters that are not allowed")
+
+// AccountNamePolicy restricts which characters account names may use
+type AccountNamePolicy struct {
+	// Pattern must match the whole name; nil allows any characters
+	Pattern *regexp.Regexp
+}
+
+// Check returns ErrNameNotAllowed if the name does not match the policy
+func (p AccountNamePolicy) Check(name string) error {
+	if p.Pattern != nil && !p.Pattern.MatchString(name) {
+		return ErrNameNotAllowed
+	}
+	return nil
+}
+
+// Key prefix limits. MaxKeyPrefixLength is the longest prefix, in bytes, a
+// generated key may start with; prefixes only make keys recognizable, so they
+// are kept short.
+const (
+	MinKeyPrefixLength = 2
+	MaxKeyPrefixLength = 8
+)
+
+// maxAccountKeyPrefixLength leaves room for the "_" that follows account prefixes
+const maxAccountKeyPrefixLength = MaxKeyPrefixLength - len("_")
+
+// ErrInvalidKeyPrefix is returned when an account key prefix is not a safe, short identifier
+var ErrInvalidKeyPrefix = fmt.Errorf("key prefix must be %d-%d lowercase letters, digits or underscores, starting with a letter", MinKeyPrefixLength, maxAccountKeyPrefixLength)
+
+// ValidateKeyPrefix checks an account key prefix. Prefixes end up in every key
+// the account is issued, so they are limited to characters that are safe in
+// headers, URLs and logs.
+func ValidateKeyPrefix(prefix string) error {
+	if len(prefix) < MinKeyPrefixLength || len(prefix) > maxAccountKeyPrefixLength {
+		return ErrInvalidKeyPrefix
+	}
+	for i, r := range prefix {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '_'):
+		default:
+			return ErrInvalidKeyPrefix
+		}
+	}
+	return nil
+}
+
+// ErrInvalidCertFingerprint is returned when a client certificate fingerprint is not a SHA-256 digest
+var ErrInvalidCertFingerprint = errors.New("client certificate fingerprint must be a SHA-256 digest in hex, optionally colon-separated")
+
+// ErrClientCertAllowlistEmpty is returned when client certificates would be
+// required without any certificate being allowed, locking every key out
+var ErrClientCertAllowlistEmpty = errors.New("client_cert_fingerprints must not be empty while require_client_cert is set")
+
+// NormalizeCertFingerprint lowercases a fingerprint and drops the colons some
+// tools print between bytes, so "AB:CD:..." and "abcd..." compare equal
+func NormalizeCertFingerprint(fingerprint string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(fingerprint), ":", ""))
+}
+
+// ValidateCertFingerprint checks that fingerprint is a SHA-256 digest once normalized
+func ValidateCertFingerprint(fingerprint string) error {
+	normalized := NormalizeCertFingerprint(fingerprint)
+	if len(normalized) != 64 {
+		return ErrInvalidCertFingerprint
+	}
+	for _, r := range normalized {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return ErrInvalidCertFingerprint
+		}
+	}
+	return nil
+}
+
 // ApiKeyStatus represents the status of an API key
 type ApiKeyStatus string
 
 const (
 	ApiKeyStatusActive   ApiKeyStatus = "active"
 	ApiKeyStatusInactive ApiKeyStatus = "inactive"
+	// ApiKeyStatusPaused is a temporary suspension; unlike inactive it can be resumed
+	ApiKeyStatusPaused ApiKeyStatus = "paused"
 )
 
+// ErrInvalidStatusTransition is returned when an API key cannot move to the requested status
+var ErrInvalidStatusTransition = errors.New("invalid API key status transition")
+
+// MaxRevokeBatchSize is the largest number of keys accepted in one batch revocation
+const MaxRevokeBatchSize = 100
+
 // ApiKeyPermissions represents the permissions granted to an API key
 type ApiKeyPermissions []string
 
@@ -47,8 +199,114 @@ const (
 	PermissionReadKeys       = "read:keys"
 	PermissionWriteKeys      = "write:keys"
 	PermissionManageWebhooks = "manage:webhooks"
+	PermissionAdminKeys      = "admin:keys"
+	PermissionAdminAccounts  = "admin:accounts"
+	PermissionReadAudit      = "read:audit"
+	PermissionReadOwnAudit   = "read:own-audit"
+)
+
+// ErrInvalidPermission is returned when a permission is not one of the known permissions
+var ErrInvalidPermission = errors.New("invalid permission")
+
+// ErrInvalidName is returned when a name has surrounding whitespace or control characters
+var ErrInvalidName = errors.New("name must not have leading or trailing whitespace or control characters")
+
+// CheckStrictName rejects names that would display ambiguously: leading or
+// trailing whitespace, or any control character
+func CheckStrictName(name string) error {
+	if strings.TrimSpace(name) != name {
+		return ErrInvalidName
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return ErrInvalidName
+		}
+	}
+	return nil
+}
+
+// ErrAccountHasLedgerPostings is returned when purging an account whose ledger
+// has postings, which must be retained
+var ErrAccountHasLedgerPostings = errors.New("account has ledger postings")
+
+// ErrKeyQuotaExceeded is returned when an account already has its maximum number of live API keys
+var ErrKeyQuotaExceeded = errors.New("API key quota exceeded")
+
+// ErrExternalIDExists is returned when an account already has a key with the external ID
+var ErrExternalIDExists = errors.New("an API key with this external ID already exists")
+
+// ErrKeyNameExists is returned when a live key in the uniqueness scope already has the name
+var ErrKeyNameExists = errors.New("an API key with this name already exists")
+
+// KeyNameScope is where API key names must be unique
+type KeyNameScope string
+
+const (
+	// KeyNameScopeNone allows any number of keys with the same name
+	KeyNameScopeNone KeyNameScope = "none"
+	// KeyNameScopeAccount requires names to be unique among an account's live keys
+	KeyNameScopeAccount KeyNameScope = "account"
+	// KeyNameScopeGlobal requires names to be unique among every live key
+	KeyNameScopeGlobal KeyNameScope = "global"
 )
 
+// ErrPermissionsRequired is returned when an API key is issued without any permissions
+var ErrPermissionsRequired = errors.New("at least one permission is required")
+
+// validPermissions lists every permission that can be granted to an API key
+var validPermissions = []string{
+	PermissionReadAccounts,
+	PermissionWriteAccounts,
+	PermissionReadKeys,
+	PermissionWriteKeys,
+	PermissionManageWebhooks,
+	PermissionAdminKeys,
+	PermissionAdminAccounts,
+	PermissionReadAudit,
+	PermissionReadOwnAudit,
+}
+
+// IsValidPermission checks if a permission is one of the known permissions
+func IsValidPermission(permission string) bool {
+	for _, valid := range validPermissions {
+		if permission == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionAliases maps alternative permission names, e.g. "accounts:read", to
+// the canonical permissions they stand for. Keys always store canonical names.
+type PermissionAliases map[string]string
+
+// Canonical returns the canonical permission for an alias, or permission unchanged
+func (a PermissionAliases) Canonical(permission string) string {
+	if canonical, ok := a[permission]; ok {
+		return canonical
+	}
+	return permission
+}
+
+// CanonicalList resolves every alias in permissions, dropping duplicates that
+// resolve to the same permission while keeping the original order
+func (a PermissionAliases) CanonicalList(permissions []string) []string {
+	if len(a) == 0 || permissions == nil {
+		return permissions
+	}
+
+	seen := make(map[string]bool, len(permissions))
+	canonical := make([]string, 0, len(permissions))
+	for _, perm := range permissions {
+		perm = a.Canonical(perm)
+		if !seen[perm] {
+			seen[perm] = true
+			canonical = append(canonical, perm)
+		}
+	}
+	return canonical
+}
+
 // ApiKey represents an API key for external client access
 type ApiKey struct {
 	ID          uuid.UUID         `json:"id" db:"id"`
@@ -60,24 +318,147 @@ type ApiKey struct {
 	LastUsedAt  *time.Time        `json:"last_used_at,omitempty" db:"last_used_at"`
 	ExpiresAt   time.Time         `json:"expires_at" db:"expires_at"`
 	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	// ExpiryWarningSentAt records when the expiry-warning webhook was sent, to avoid repeats
+	ExpiryWarningSentAt *time.Time `json:"expiry_warning_sent_at,omitempty" db:"expiry_warning_sent_at"`
+	// ExternalID is an optional client-supplied reference, unique within the account
+	ExternalID string `json:"external_id,omitempty" db:"external_id"`
+	// KeyHint is a masked form of the key, e.g. "pk_live_…9f3a", for telling keys
+	// apart; empty for keys issued before hints were stored and for imported keys
+	KeyHint string `json:"key_hint,omitempty" db:"key_hint"`
+	// RevokedAt records when the key was first revoked; nil for live keys and keys revoked before it was tracked
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	// Version is incremented on every update and guards against concurrent writes
+	Version int `json:"version" db:"version"`
+	// ResourceScopes limits the key to these resources, e.g. account IDs, on routes
+	// that check them; empty leaves the key unrestricted. Permissions still apply.
+	ResourceScopes []string `json:"resource_scopes,omitempty" db:"resource_scopes"`
+}
+
+// CanonicalResourceScope returns the form resources are scoped and matched in:
+// UUIDs, such as account IDs, in their canonical lowercase form, and anything
+// else unchanged
+func CanonicalResourceScope(resource string) string {
+	if id, err := uuid.Parse(resource); err == nil {
+		return id.String()
+	}
+	return resource
+}
+
+// ResourceInScope reports whether resource is one of scopes. An empty scope list
+// allows every resource; matching is exact, so IDs must use their canonical form.
+func ResourceInScope(scopes []string, resource string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if scope == resource {
+			return true
+		}
+	}
+	return false
 }
 
-// IsValid checks if the API key is in a valid state
+// ErrInvalidApiKey is returned when a new API key would break one of its invariants
+var ErrInvalidApiKey = errors.New("invalid API key")
+
+// NewApiKey builds an active API key with a fresh ID and creation time. It
+// enforces the invariants every stored key relies on: an owning account, a
+// non-blank name, at least one known permission and an expiry. KeyHash and the
+// optional fields are left for the caller to set.
+func NewApiKey(accountID uuid.UUID, name string, permissions []string, expiresAt time.Time) (*ApiKey, error) {
+	if accountID == uuid.Nil {
+		return nil, fmt.Errorf("%w: account ID is required", ErrInvalidApiKey)
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidApiKey)
+	}
+	if len(permissions) == 0 {
+		return nil, ErrPermissionsRequired
+	}
+	if err := ApiKeyPermissions(permissions).Validate(); err != nil {
+		return nil, err
+	}
+	if expiresAt.IsZero() {
+		return nil, fmt.Errorf("%w: expiry is required", ErrInvalidApiKey)
+	}
+
+	return &ApiKey{
+		ID:          uuid.New(),
+		AccountID:   accountID,
+		Name:        name,
+		Permissions: append(ApiKeyPermissions(nil), permissions...),
+		Status:      ApiKeyStatusActive,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// IsValid checks if the API key is in a valid state. Paused and inactive keys are never valid.
 func (k *ApiKey) IsValid() bool {
 	return k.Status == ApiKeyStatusActive && time.Now().Before(k.ExpiresAt)
 }
 
 // HasPermission checks if the API key has a specific permission
 func (k *ApiKey) HasPermission(permission string) bool {
-	for _, p := range k.Permissions {
-		if p == permission {
-			return true
-		}
+	return k.Permissions.Contains(permission)
+}
+
+// Pause temporarily suspends an active API key
+func (k *ApiKey) Pause() error {
+	if k.Status != ApiKeyStatusActive {
+		return fmt.Errorf("%w: cannot pause a %s key", ErrInvalidStatusTransition, k.Status)
 	}
-	return false
+	k.Status = ApiKeyStatusPaused
+	return nil
+}
+
+// Resume reactivates a paused API key. Revoked (inactive) keys cannot be resumed.
+func (k *ApiKey) Resume() error {
+	if k.Status != ApiKeyStatusPaused {
+		return fmt.Errorf("%w: cannot resume a %s key", ErrInvalidStatusTransition, k.Status)
+	}
+	k.Status = ApiKeyStatusActive
+	return nil
+}
+
+// Rehash replaces the stored hash with a new secret's hash. Revoked (inactive)
+// keys cannot be regenerated; name, permissions, status and expiry are kept.
+func (k *ApiKey) Rehash(keyHash string) error {
+	if k.Status == ApiKeyStatusInactive {
+		return fmt.Errorf("%w: cannot regenerate a %s key", ErrInvalidStatusTransition, k.Status)
+	}
+	k.KeyHash = keyHash
+	return nil
+}
+
+// SetPermissions replaces the key's permissions. Revoked (inactive) keys cannot
+// be changed; callers validate the permissions first.
+func (k *ApiKey) SetPermissions(permissions ApiKeyPermissions) error {
+	if k.Status == ApiKeyStatusInactive {
+		return fmt.Errorf("%w: cannot change the permissions of a %s key", ErrInvalidStatusTransition, k.Status)
+	}
+	k.Permissions = permissions
+	return nil
+}
+
+// Expire marks an active key that is past its expiry as inactive, so its status
+// agrees with IsExpired. Unlike revocation it leaves RevokedAt unset.
+func (k *ApiKey) Expire() error {
+	if k.Status != ApiKeyStatusActive || !k.IsExpired() {
+		return fmt.Errorf("%w: cannot expire a %s key that expires at %s", ErrInvalidStatusTransition, k.Status, k.ExpiresAt.Format(time.RFC3339))
+	}
+	k.Status = ApiKeyStatusInactive
+	return nil
 }
 
 // IsExpired checks if the API key has expired
 func (k *ApiKey) IsExpired() bool {
 	return time.Now().After(k.ExpiresAt)
 }
+
+// InGracePeriod reports whether the key has expired but is still within grace
+// of its expiry. A zero grace means there is no grace period.
+func (k *ApiKey) InGracePeriod(grace time.Duration) bool {
+	now := time.Now()
+	return now.After(k.ExpiresAt) && !now.After(k.ExpiresAt.Add(grace))
+}
@@ -1,21 +1,49 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 )
 
-// ErrorCode represents a specific error code for auth operations
+// ErrorCode represents a specific error code for auth operations.
+// Every error response body carries one of these values in its "error" field.
 type ErrorCode string
 
 const (
+	// Request errors
+	ErrCodeInvalidRequest     ErrorCode = "invalid_request"
+	ErrCodeValidationError    ErrorCode = "validation_error"
+	ErrCodeInvalidAccountID   ErrorCode = "invalid_account_id"
+	ErrCodeInvalidAPIKeyID    ErrorCode = "invalid_api_key_id"
+	ErrCodePreconditionFailed ErrorCode = "precondition_failed"
+	ErrCodeInvalidPagination  ErrorCode = "invalid_pagination"
+	ErrCodeNotFound           ErrorCode = "not_found"
+
+	// Resource errors
+	ErrCodeAccountNotFound  ErrorCode = "account_not_found"
+	ErrCodeAccountExists    ErrorCode = "account_exists"
+	ErrCodeAPIKeyNotFound   ErrorCode = "api_key_not_found"
+	ErrCodeAPIKeyState      ErrorCode = "invalid_api_key_state"
+	ErrCodeExternalIDExists ErrorCode = "external_id_exists"
+	ErrCodeKeyNameExists    ErrorCode = "key_name_exists"
+	ErrCodeKeyQuotaExceeded ErrorCode = "key_quota_exceeded"
+	ErrCodeAccountHasLedger ErrorCode = "account_has_ledger"
+	ErrCodeVersionConflict  ErrorCode = "version_conflict"
+
+	// Registration challenge errors
+	ErrCodeChallengeRequired ErrorCode = "challenge_required"
+	ErrCodeChallengeFailed   ErrorCode = "challenge_failed"
+
 	// Authentication errors
-	ErrCodeMissingAPIKey    ErrorCode = "missing_api_key"
-	ErrCodeInvalidAPIKey    ErrorCode = "invalid_api_key"
-	ErrCodeExpiredAPIKey    ErrorCode = "expired_api_key"
-	ErrCodeInactiveAPIKey   ErrorCode = "inactive_api_key"
-	ErrCodeInactiveAccount  ErrorCode = "inactive_account"
-	ErrCodeValidationFailed ErrorCode = "validation_failed"
+	ErrCodeMissingAPIKey      ErrorCode = "missing_api_key"
+	ErrCodeInvalidAPIKey      ErrorCode = "invalid_api_key"
+	ErrCodeExpiredAPIKey      ErrorCode = "expired_api_key"
+	ErrCodeInactiveAPIKey     ErrorCode = "inactive_api_key"
+	ErrCodeInactiveAccount    ErrorCode = "inactive_account"
+	ErrCodeValidationFailed   ErrorCode = "validation_failed"
+	ErrCodeClientCertRequired ErrorCode = "client_cert_required"
 
 	// Rate limiting errors
 	ErrCodeRateLimitExceeded    ErrorCode = "rate_limit_exceeded"
@@ -27,15 +55,18 @@ const (
 	ErrCodeIdempotencyCheckFailed    ErrorCode = "idempotency_check_failed"
 	ErrCodeIdempotencyCreateFailed   ErrorCode = "idempotency_create_failed"
 	ErrCodeIdempotencyCompleteFailed ErrorCode = "idempotency_complete_failed"
+	ErrCodeIdempotencyLimitExceeded  ErrorCode = "idempotency_limit_exceeded"
 
 	// Permission errors
 	ErrCodeInsufficientPermissions ErrorCode = "insufficient_permissions"
 	ErrCodeNotAuthenticated        ErrorCode = "not_authenticated"
+	ErrCodeResourceOutOfScope      ErrorCode = "resource_out_of_scope"
 
 	// System errors
 	ErrCodeInternalError      ErrorCode = "internal_error"
 	ErrCodeDatabaseError      ErrorCode = "database_error"
 	ErrCodeServiceUnavailable ErrorCode = "service_unavailable"
+	ErrCodeMaintenance        ErrorCode = "maintenance"
 )
 
 // AuthError represents a structured error with code and details
@@ -73,30 +104,102 @@ func NewAuthErrorWithDetails(code ErrorCode, message string, details map[string]
 	}
 }
 
+// ErrorDefinition describes how an error code is presented to clients
+type ErrorDefinition struct {
+	Status  int
+	Message string
+}
+
+// errorCatalog maps every error code to its HTTP status and default message
+var errorCatalog = map[ErrorCode]ErrorDefinition{
+	// Request errors
+	ErrCodeInvalidRequest:     {http.StatusBadRequest, "Invalid request body"},
+	ErrCodeValidationError:    {http.StatusBadRequest, "Invalid request data"},
+	ErrCodeInvalidAccountID:   {http.StatusBadRequest, "Invalid account ID format"},
+	ErrCodeInvalidAPIKeyID:    {http.StatusBadRequest, "Invalid API key ID format"},
+	ErrCodePreconditionFailed: {http.StatusPreconditionFailed, "The resource was modified by another request"},
+	ErrCodeInvalidPagination:  {http.StatusBadRequest, "Invalid limit or offset"},
+	ErrCodeNotFound:           {http.StatusNotFound, "No route matches the request"},
+
+	// Resource errors
+	ErrCodeAccountNotFound:  {http.StatusNotFound, "Account not found or inactive"},
+	ErrCodeAccountExists:    {http.StatusConflict, "Account with this name already exists"},
+	ErrCodeAPIKeyNotFound:   {http.StatusNotFound, "API key not found"},
+	ErrCodeAPIKeyState:      {http.StatusConflict, "API key cannot change to the requested status"},
+	ErrCodeExternalIDExists: {http.StatusConflict, "An API key with this external ID already exists"},
+	ErrCodeKeyNameExists:    {http.StatusConflict, "An API key with this name already exists"},
+	ErrCodeKeyQuotaExceeded: {http.StatusConflict, "The account has reached its maximum number of API keys"},
+	ErrCodeAccountHasLedger: {http.StatusConflict, "The account has ledger postings, which must be retained"},
+	ErrCodeVersionConflict:  {http.StatusConflict, "The resource was modified by another request; retry"},
+
+	// Registration challenge errors
+	ErrCodeChallengeRequired: {http.StatusBadRequest, "A registration challenge token is required"},
+	ErrCodeChallengeFailed:   {http.StatusBadRequest, "The registration challenge token is invalid or expired"},
+
+	// Authentication errors
+	ErrCodeMissingAPIKey:      {http.StatusUnauthorized, "API key is required"},
+	ErrCodeInvalidAPIKey:      {http.StatusUnauthorized, "API key is invalid or expired"},
+	ErrCodeExpiredAPIKey:      {http.StatusUnauthorized, "API key has expired"},
+	ErrCodeInactiveAPIKey:     {http.StatusUnauthorized, "API key is not active"},
+	ErrCodeInactiveAccount:    {http.StatusForbidden, "Account is not active"},
+	ErrCodeValidationFailed:   {http.StatusInternalServerError, "Failed to validate API key"},
+	ErrCodeClientCertRequired: {http.StatusUnauthorized, "A trusted client certificate is required for this account"},
+
+	// Rate limiting errors
+	ErrCodeRateLimitExceeded:    {http.StatusTooManyRequests, "Rate limit exceeded"},
+	ErrCodeRateLimitCheckFailed: {http.StatusInternalServerError, "Failed to check rate limit"},
+
+	// Idempotency errors
+	ErrCodeIdempotencyKeyPending:     {http.StatusConflict, "Request with this idempotency key is still being processed"},
+	ErrCodeIdempotencyKeyExpired:     {http.StatusConflict, "Idempotency key has expired"},
+	ErrCodeIdempotencyCheckFailed:    {http.StatusInternalServerError, "Failed to check idempotency"},
+	ErrCodeIdempotencyCreateFailed:   {http.StatusInternalServerError, "Failed to create idempotency key"},
+	ErrCodeIdempotencyCompleteFailed: {http.StatusInternalServerError, "Failed to complete idempotency key"},
+	ErrCodeIdempotencyLimitExceeded:  {http.StatusTooManyRequests, "Too many outstanding idempotency keys for this account"},
+
+	// Permission errors
+	ErrCodeInsufficientPermissions: {http.StatusForbidden, "Insufficient permissions"},
+	ErrCodeNotAuthenticated:        {http.StatusUnauthorized, "Authentication required"},
+	ErrCodeResourceOutOfScope:      {http.StatusForbidden, "This API key is not scoped to the requested resource"},
+
+	// System errors
+	ErrCodeInternalError:      {http.StatusInternalServerError, "An internal error occurred"},
+	ErrCodeDatabaseError:      {http.StatusInternalServerError, "A database error occurred"},
+	ErrCodeServiceUnavailable: {http.StatusServiceUnavailable, "Service temporarily unavailable"},
+	ErrCodeMaintenance:        {http.StatusServiceUnavailable, "The service is in maintenance mode; write operations are temporarily unavailable"},
+}
+
+// Definition returns the catalog entry for the code; unknown codes map to internal_error
+func (c ErrorCode) Definition() ErrorDefinition {
+	if def, ok := errorCatalog[c]; ok {
+		return def
+	}
+	return errorCatalog[ErrCodeInternalError]
+}
+
+// HTTPStatus returns the HTTP status for the code
+func (c ErrorCode) HTTPStatus() int {
+	return c.Definition().Status
+}
+
+// DefaultMessage returns the default client-facing message for the code
+func (c ErrorCode) DefaultMessage() string {
+	return c.Definition().Message
+}
+
+// ErrorCodes returns every documented error code
+func ErrorCodes() []ErrorCode {
+	codes := make([]ErrorCode, 0, len(errorCatalog))
+	for code := range errorCatalog {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
 // getHTTPStatusForError returns appropriate HTTP status code for error
 func getHTTPStatusForError(code ErrorCode) int {
-	switch code {
-	case ErrCodeMissingAPIKey, ErrCodeInvalidAPIKey, ErrCodeExpiredAPIKey, ErrCodeInactiveAPIKey:
-		return http.StatusUnauthorized
-	case ErrCodeInactiveAccount:
-		return http.StatusForbidden
-	case ErrCodeInsufficientPermissions:
-		return http.StatusForbidden
-	case ErrCodeNotAuthenticated:
-		return http.StatusUnauthorized
-	case ErrCodeRateLimitExceeded:
-		return http.StatusTooManyRequests
-	case ErrCodeIdempotencyKeyPending:
-		return http.StatusConflict
-	case ErrCodeValidationFailed:
-		return http.StatusBadRequest
-	case ErrCodeInternalError, ErrCodeDatabaseError:
-		return http.StatusInternalServerError
-	case ErrCodeServiceUnavailable:
-		return http.StatusServiceUnavailable
-	default:
-		return http.StatusInternalServerError
-	}
+	return code.HTTPStatus()
 }
 
 // Common error instances
@@ -110,3 +213,12 @@ var (
 	ErrNotAuthenticated        = NewAuthError(ErrCodeNotAuthenticated, "Authentication required")
 	ErrInternalError           = NewAuthError(ErrCodeInternalError, "Internal server error")
 )
+
+// Registration challenge errors
+var (
+	ErrChallengeRequired = errors.New("registration challenge is required")
+	ErrChallengeFailed   = errors.New("registration challenge failed")
+)
+
+// ErrVersionConflict is returned when a write is based on a stale version of a record
+var ErrVersionConflict = errors.New("version conflict")
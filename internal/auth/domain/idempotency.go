@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +16,17 @@ const (
 	IdempotencyKeyStatusExpired   IdempotencyKeyStatus = "expired"
 )
 
+// ErrIdempotencyKeyLimitExceeded is returned when an account already holds its
+// maximum number of unexpired idempotency keys
+var ErrIdempotencyKeyLimitExceeded = errors.New("idempotency key limit exceeded")
+
+// ErrIdempotencyKeyInProgress is returned when another request has already
+// claimed the same idempotency key and request and has not expired
+var ErrIdempotencyKeyInProgress = errors.New("idempotency key is already in use")
+
+// TruncatedResponseMarker replaces responses too large to store with an idempotency key
+const TruncatedResponseMarker = "[response truncated]"
+
 // IdempotencyKey represents an idempotency key for preventing duplicate processing
 type IdempotencyKey struct {
 	ID          uuid.UUID            `json:"id" db:"id"`
@@ -22,8 +34,14 @@ type IdempotencyKey struct {
 	RequestHash string               `json:"request_hash" db:"request_hash"`
 	Status      IdempotencyKeyStatus `json:"status" db:"status"`
 	Response    string               `json:"response,omitempty" db:"response,omitempty"`
-	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
-	ExpiresAt   time.Time            `json:"expires_at" db:"expires_at"`
+	// ResponseTruncated is set when the response was too large to store and
+	// Response holds TruncatedResponseMarker instead
+	ResponseTruncated bool `json:"response_truncated,omitempty" db:"response_truncated"`
+	// ResponseStatus is the HTTP status the original request was answered with;
+	// zero for keys completed before it was recorded
+	ResponseStatus int       `json:"response_status,omitempty" db:"response_status"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at" db:"expires_at"`
 }
 
 // IsExpired checks if the idempotency key has expired
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PermissionWildcard matches any action or resource in a permission pattern,
+// e.g. "read:*" or "*:keys". On its own it matches every permission.
+const PermissionWildcard = "*"
+
+// Contains checks whether permission is in the list
+func (p ApiKeyPermissions) Contains(permission string) bool {
+	for _, perm := range p {
+		if perm == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends each permission not already in the list, keeping the order
+func (p *ApiKeyPermissions) Add(permissions ...string) {
+	for _, perm := range permissions {
+		if !p.Contains(perm) {
+			*p = append(*p, perm)
+		}
+	}
+}
+
+// IsSubsetOf reports whether other grants everything p grants. Wildcards are
+// honoured on both sides: {read:accounts} is a subset of {read:*}, and {read:*}
+// is a subset of {read:accounts, read:keys, ...} once that lists every known
+// read permission. An empty p is a subset of anything.
+func (p ApiKeyPermissions) IsSubsetOf(other ApiKeyPermissions) bool {
+	for _, perm := range p.Expand() {
+		if !other.grants(perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// grants reports whether any entry of p, taken as a pattern, matches permission
+func (p ApiKeyPermissions) grants(permission string) bool {
+	for _, pattern := range p {
+		if matchesPattern(pattern, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate reports every permission that is not a known permission at once.
+// An empty list is valid; callers that require permissions check that themselves.
+func (p ApiKeyPermissions) Validate() error {
+	var invalid []string
+	for _, perm := range p {
+		if !IsValidPermission(perm) {
+			invalid = append(invalid, perm)
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidPermission, strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// Expand replaces wildcard patterns with the known permissions they match and
+// drops duplicates, keeping the original order. Entries that are not wildcards,
+// and wildcards matching nothing, are kept as they are so Validate reports them.
+func (p ApiKeyPermissions) Expand() ApiKeyPermissions {
+	if p == nil {
+		return nil
+	}
+
+	expanded := make(ApiKeyPermissions, 0, len(p))
+	for _, perm := range p {
+		matches := wildcardMatches(perm)
+		if len(matches) == 0 {
+			expanded.Add(perm)
+			continue
+		}
+		expanded.Add(matches...)
+	}
+	return expanded
+}
+
+// wildcardMatches returns the known permissions a wildcard pattern matches, or
+// nil when pattern is not a wildcard
+func wildcardMatches(pattern string) []string {
+	if pattern == PermissionWildcard {
+		return append([]string(nil), validPermissions...)
+	}
+
+	action, resource, ok := strings.Cut(pattern, ":")
+	if !ok || (action != PermissionWildcard && resource != PermissionWildcard) {
+		return nil
+	}
+
+	var matches []string
+	for _, perm := range validPermissions {
+		if matchesPattern(pattern, perm) {
+			matches = append(matches, perm)
+		}
+	}
+	return matches
+}
+
+// matchesPattern reports whether permission is pattern itself or is matched by
+// it as a wildcard
+func matchesPattern(pattern, permission string) bool {
+	if pattern == permission || pattern == PermissionWildcard {
+		return true
+	}
+
+	action, resource, ok := strings.Cut(pattern, ":")
+	if !ok {
+		return false
+	}
+	permAction, permResource, _ := strings.Cut(permission, ":")
+	return (action == PermissionWildcard || action == permAction) && (resource == PermissionWildcard || resource == permResource)
+}
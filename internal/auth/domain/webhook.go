@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidWebhookURL is returned when a webhook URL violates the webhook policy
+var ErrInvalidWebhookURL = errors.New("invalid webhook URL")
+
+// DefaultWebhookSchemes are accepted when a policy lists no schemes
+var DefaultWebhookSchemes = []string{"http", "https"}
+
+// WebhookURLPolicy defines the rules a webhook URL must satisfy
+type WebhookURLPolicy struct {
+	// RequireHTTPS rejects plaintext http:// webhooks (production setting)
+	RequireHTTPS bool
+	// AllowedSchemes lists the accepted URL schemes, e.g. an internal "https+self".
+	// Empty accepts DefaultWebhookSchemes. RequireHTTPS still rejects http.
+	AllowedSchemes []string
+	// AllowedPorts lists the ports webhooks may target; URLs without an explicit
+	// port use the scheme's default. Empty allows any port.
+	AllowedPorts []int
+	// MaxLength is the longest URL accepted, in bytes. Zero means no limit.
+	MaxLength int
+}
+
+// Validate checks a webhook URL against the policy
+func (p WebhookURLPolicy) Validate(rawURL string) error {
+	if p.MaxLength > 0 && len(rawURL) > p.MaxLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidWebhookURL, p.MaxLength)
+	}
+
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: malformed URL", ErrInvalidWebhookURL)
+	}
+
+	if !p.schemeAllowed(u.Scheme) {
+		return fmt.Errorf("%w: unsupported scheme '%s'", ErrInvalidWebhookURL, u.Scheme)
+	}
+	if u.Scheme == "http" && p.RequireHTTPS {
+		return fmt.Errorf("%w: https is required", ErrInvalidWebhookURL)
+	}
+
+	if len(p.AllowedPorts) > 0 {
+		port, err := webhookPort(u)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+		}
+		if !p.portAllowed(port) {
+			return fmt.Errorf("%w: port %d is not allowed", ErrInvalidWebhookURL, port)
+		}
+	}
+
+	return nil
+}
+
+// schemeAllowed checks the scheme allowlist, falling back to DefaultWebhookSchemes
+func (p WebhookURLPolicy) schemeAllowed(scheme string) bool {
+	allowed := p.AllowedSchemes
+	if len(allowed) == 0 {
+		allowed = DefaultWebhookSchemes
+	}
+	for _, s := range allowed {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookPort returns the URL's explicit port, or the default port of its scheme.
+// Custom schemes have no default, so they must name a port.
+func webhookPort(u *url.URL) (int, error) {
+	raw := u.Port()
+	if raw == "" {
+		switch u.Scheme {
+		case "https":
+			return 443, nil
+		case "http":
+			return 80, nil
+		default:
+			return 0, fmt.Errorf("scheme '%s' requires an explicit port", u.Scheme)
+		}
+	}
+
+	port, err := strconv.Atoi(raw)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port '%s'", raw)
+	}
+	return port, nil
+}
+
+// portAllowed checks the port allowlist
+func (p WebhookURLPolicy) portAllowed(port int) bool {
+	for _, allowed := range p.AllowedPorts {
+		if port == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPeriodically runs fn every interval until the context is cancelled.
+// Errors are logged and do not stop the schedule.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Starting background job %s (interval %s)", name, interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopping background job %s", name)
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("Background job %s failed: %v", name, err)
+			}
+		}
+	}
+}
@@ -14,18 +14,37 @@ type ApiKeyRepository interface {
 	// Create creates a new API key
 	Create(ctx context.Context, apiKey *domain.ApiKey) error
 
+	// CreateWithinQuota creates a new API key only if the account has fewer than
+	// maxActive live keys, checked atomically with the write. It returns
+	// domain.ErrKeyQuotaExceeded when the account is full; 0 disables the quota.
+	CreateWithinQuota(ctx context.Context, apiKey *domain.ApiKey, maxActive int) error
+
 	// GetByID retrieves an API key by its ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.ApiKey, error)
 
 	// GetByKeyHash retrieves an API key by its hash
 	GetByKeyHash(ctx context.Context, keyHash string) (*domain.ApiKey, error)
 
+	// FindByKeyHash retrieves an API key by its hash without recording usage
+	FindByKeyHash(ctx context.Context, keyHash string) (*domain.ApiKey, error)
+
+	// GetByExternalID retrieves an account's API key by its client-supplied external ID
+	GetByExternalID(ctx context.Context, accountID uuid.UUID, externalID string) (*domain.ApiKey, error)
+
+	// FindLiveByName retrieves a live (not revoked) API key with the name from the
+	// account's keys, or from every account's keys when accountID is uuid.Nil
+	FindLiveByName(ctx context.Context, accountID uuid.UUID, name string) (*domain.ApiKey, error)
+
 	// GetByAccountID retrieves all API keys for an account
 	GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]*domain.ApiKey, error)
 
-	// ValidateByKey validates an API key by comparing the raw key with stored hashes
+	// ValidateByKey validates an API key by comparing the raw key with stored hashes.
+	// Expired keys are returned so callers can apply an expiry grace period.
 	ValidateByKey(ctx context.Context, rawKey string) (*domain.ApiKey, error)
 
+	// FindByKey retrieves the API key for a raw key without recording usage
+	FindByKey(ctx context.Context, rawKey string) (*domain.ApiKey, error)
+
 	// Update updates an existing API key
 	Update(ctx context.Context, apiKey *domain.ApiKey) error
 
@@ -35,15 +54,45 @@ type ApiKeyRepository interface {
 	// Revoke revokes an API key immediately
 	Revoke(ctx context.Context, id uuid.UUID) error
 
+	// RevokeVersion revokes an API key only while it is still at the given
+	// version, returning domain.ErrVersionConflict otherwise
+	RevokeVersion(ctx context.Context, id uuid.UUID, version int) error
+
 	// List retrieves API keys with pagination
 	List(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*domain.ApiKey, error)
+
+	// ListRevokedSince retrieves an account's keys revoked after since, most recent first
+	ListRevokedSince(ctx context.Context, accountID uuid.UUID, since time.Time) ([]*domain.ApiKey, error)
+
+	// ForEachByAccountID calls fn with each page of an account's API keys, reading
+	// at most pageSize keys from the store at a time. An error from fn stops iteration.
+	ForEachByAccountID(ctx context.Context, accountID uuid.UUID, pageSize int, fn func([]*domain.ApiKey) error) error
+
+	// ListExpiringBefore retrieves active API keys that expire before the cutoff
+	ListExpiringBefore(ctx context.Context, cutoff time.Time) ([]*domain.ApiKey, error)
+
+	// CountActiveKeys returns the account's live key counter without reading its keys
+	CountActiveKeys(ctx context.Context, accountID uuid.UUID) (int, error)
+
+	// RecountActiveKeys rebuilds the account's live key counter from its keys
+	RecountActiveKeys(ctx context.Context, accountID uuid.UUID) (int, error)
+
+	// PurgeByAccountID hard deletes all of an account's API keys, revoked ones
+	// included, and returns how many were removed
+	PurgeByAccountID(ctx context.Context, accountID uuid.UUID) (int, error)
 }
 
 // IdempotencyKeyRepository defines the interface for idempotency key persistence operations
 type IdempotencyKeyRepository interface {
-	// Create creates a new idempotency key
+	// Create creates a new idempotency key. It returns domain.ErrIdempotencyKeyInProgress
+	// when an unexpired key with the same request hash already exists.
 	Create(ctx context.Context, key *domain.IdempotencyKey) error
 
+	// CreateWithinLimit creates a new idempotency key like Create. With maxKeys > 0
+	// it returns domain.ErrIdempotencyKeyLimitExceeded instead when the account
+	// already holds maxKeys unexpired keys; the check is atomic with the write.
+	CreateWithinLimit(ctx context.Context, key *domain.IdempotencyKey, maxKeys int) error
+
 	// GetByID retrieves an idempotency key by its ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.IdempotencyKey, error)
 
@@ -61,6 +110,10 @@ type IdempotencyKeyRepository interface {
 
 	// CleanupExpired removes expired idempotency keys
 	CleanupExpired(ctx context.Context) error
+
+	// PurgeByAccountID hard deletes all of an account's idempotency keys and
+	// returns how many were removed
+	PurgeByAccountID(ctx context.Context, accountID uuid.UUID) (int, error)
 }
 
 // RateLimitRepository defines the interface for rate limiting operations
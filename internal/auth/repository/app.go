@@ -13,6 +13,13 @@ type AppRepository interface {
 	// Create creates a new account
 	Create(ctx context.Context, account *domain.Account) error
 
+	// CreateMany inserts accounts in a single transaction, keeping their IDs.
+	// Accounts are inserted in order, and one whose owner is an earlier account
+	// of the batch that failed is not inserted, so owners must come first.
+	// The returned slice holds one error per account (nil when inserted); a
+	// non-nil second return means nothing was inserted.
+	CreateMany(ctx context.Context, accounts []*domain.Account) ([]error, error)
+
 	// GetByID retrieves an account by its ID
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error)
 
@@ -25,6 +32,16 @@ type AppRepository interface {
 	// Delete soft deletes an account by setting status to deleted
 	Delete(ctx context.Context, id uuid.UUID) error
 
+	// Purge hard deletes an account. Purging a missing account is not an error.
+	Purge(ctx context.Context, id uuid.UUID) error
+
+	// HasLedgerPostings reports whether any of the account's ledger accounts has
+	// postings, which keep the account from being purged
+	HasLedgerPostings(ctx context.Context, id uuid.UUID) (bool, error)
+
 	// List retrieves accounts with pagination
 	List(ctx context.Context, limit, offset int) ([]*domain.Account, error)
+
+	// ListByOwner retrieves accounts owned by a tenant with pagination
+	ListByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*domain.Account, error)
 }
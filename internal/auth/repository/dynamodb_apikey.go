@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
@@ -29,21 +32,42 @@ func NewDynamoDBApiKeyRepository(client *db.DynamoDBClient) *DynamoDBApiKeyRepos
 	}
 }
 
+// ExpiredKeyRetention is how long an expired API key is kept before DynamoDB TTL
+// may delete it, so an expiry grace period can still find the key
+const ExpiredKeyRetention = 7 * 24 * time.Hour
+
+// ttlFor returns the ttl attribute for a key expiring at expiresAt
+func ttlFor(expiresAt time.Time) int64 {
+	return expiresAt.Add(ExpiredKeyRetention).Unix()
+}
+
 // DynamoDBApiKey represents the ApiKey entity in DynamoDB
 type DynamoDBApiKey struct {
 	domain.ApiKey
 	PK     string `dynamodbav:"pk" json:"pk"`
 	SK     string `dynamodbav:"sk" json:"sk"`
-	GSI1PK string `dynamodbav:"gsi1pk" json:"gsi1pk"` // For lookup by key hash
-	GSI2PK string `dynamodbav:"gsi2pk" json:"gsi2pk"` // For lookup by API key ID
-	TTL    int64  `dynamodbav:"ttl" json:"ttl"`       // For automatic expiration
+	GSI1PK string `dynamodbav:"gsi1pk" json:"gsi1pk"`                     // For lookup by key hash
+	GSI2PK string `dynamodbav:"gsi2pk" json:"gsi2pk"`                     // For lookup by API key ID
+	GSI3PK string `dynamodbav:"gsi3pk,omitempty" json:"gsi3pk,omitempty"` // For lookup by external ID; only set when present
+	GSI4PK string `dynamodbav:"gsi4pk,omitempty" json:"gsi4pk,omitempty"` // For lookup by name across accounts
+	TTL    int64  `dynamodbav:"ttl" json:"ttl"`                           // For automatic expiration
 }
 
-// Create creates a new API key
+// Create creates a new API key and counts it against the account's key counter
 func (r *DynamoDBApiKeyRepository) Create(ctx context.Context, apiKey *domain.ApiKey) error {
+	return r.CreateWithinQuota(ctx, apiKey, 0)
+}
+
+// CreateWithinQuota creates a new API key and increments the account's key
+// counter in one transaction. With maxActive > 0 the transaction also requires
+// the counter to be below maxActive, so two concurrent issuances cannot both
+// take the last slot; when the account is full domain.ErrKeyQuotaExceeded is
+// returned. A missing or over-counted counter is recounted before rejecting.
+func (r *DynamoDBApiKeyRepository) CreateWithinQuota(ctx context.Context, apiKey *domain.ApiKey, maxActive int) error {
 	// Set timestamps before creation
 	now := time.Now()
 	apiKey.CreatedAt = now
+	apiKey.Version = 1
 
 	// Create DynamoDB entity with composite key and TTL
 	dynamoApiKey := &DynamoDBApiKey{
@@ -52,10 +76,48 @@ func (r *DynamoDBApiKeyRepository) Create(ctx context.Context, apiKey *domain.Ap
 		SK:     fmt.Sprintf("APIKEY#%s", apiKey.ID.String()),
 		GSI1PK: fmt.Sprintf("KEYHASH#%s", apiKey.KeyHash),
 		GSI2PK: fmt.Sprintf("APIKEY#%s", apiKey.ID.String()),
-		TTL:    apiKey.ExpiresAt.Unix(), // Set TTL to expiration time
+		GSI4PK: keyNameKey(apiKey.Name),
+		TTL:    ttlFor(apiKey.ExpiresAt),
+	}
+	if apiKey.ExternalID != "" {
+		dynamoApiKey.GSI3PK = externalIDKey(apiKey.AccountID, apiKey.ExternalID)
 	}
 
-	return r.client.PutItem(ctx, dynamoApiKey)
+	item, err := attributevalue.MarshalMap(dynamoApiKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := r.client.TransactWriteItems(ctx, []types.TransactWriteItem{
+			{Put: &types.Put{Item: item, ConditionExpression: aws.String("attribute_not_exists(pk)")}},
+			{Update: counterIncrement(apiKey.AccountID, maxActive)},
+		})
+
+		var conditionErr *db.TransactionConditionError
+		if !errors.As(err, &conditionErr) || !conditionErr.ItemFailed(1) {
+			if err != nil {
+				return fmt.Errorf("failed to create API key: %w", err)
+			}
+			return nil
+		}
+		if attempt == maxCounterAttempts {
+			return domain.ErrVersionConflict
+		}
+
+		// The counter is missing or at the quota; it may over-count expired keys,
+		// so recount before deciding
+		count, err := r.recountActiveKeys(ctx, apiKey.AccountID)
+		if errors.Is(err, db.ErrConditionFailed) {
+			continue // Changed during the recount; try again
+		}
+		if err != nil {
+			return err
+		}
+		if maxActive > 0 && count >= maxActive {
+			return domain.ErrKeyQuotaExceeded
+		}
+	}
 }
 
 // GetByID retrieves an API key by its ID using a GSI for efficient lookup
@@ -84,8 +146,8 @@ func (r *DynamoDBApiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*
 	return &results[0].ApiKey, nil
 }
 
-// GetByKeyHash retrieves an API key by its hash
-func (r *DynamoDBApiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*domain.ApiKey, error) {
+// queryByKeyHash looks up the stored item for a key hash
+func (r *DynamoDBApiKeyRepository) queryByKeyHash(ctx context.Context, keyHash string) (*DynamoDBApiKey, error) {
 	// Query using GSI on key hash
 	input := &dynamodb.QueryInput{
 		TableName:              aws.String(r.client.GetTableName()),
@@ -107,17 +169,37 @@ func (r *DynamoDBApiKeyRepository) GetByKeyHash(ctx context.Context, keyHash str
 		return nil, nil // API key not found
 	}
 
+	return &results[0], nil
+}
+
+// FindByKeyHash retrieves an API key by its hash without touching last_used_at
+func (r *DynamoDBApiKeyRepository) FindByKeyHash(ctx context.Context, keyHash string) (*domain.ApiKey, error) {
+	result, err := r.queryByKeyHash(ctx, keyHash)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	return &result.ApiKey, nil
+}
+
+// GetByKeyHash retrieves an API key by its hash and records it as used
+func (r *DynamoDBApiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*domain.ApiKey, error) {
+	result, err := r.queryByKeyHash(ctx, keyHash)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
 	// Update last used at
 	now := time.Now()
-	results[0].LastUsedAt = &now
+	result.LastUsedAt = &now
 
 	// Update the last used timestamp
-	key, err := db.CreateCompositeKey("pk", results[0].PK, "sk", results[0].SK)
+	key, err := db.CreateCompositeKey("pk", result.PK, "sk", result.SK)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create key for update: %w", err)
 	}
 
-	updateExpr := "SET last_used_at = :l"
+	updateExpr := "SET LastUsedAt = :l"
 	exprAttrValues := map[string]types.AttributeValue{
 		":l": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339)},
 	}
@@ -128,6 +210,81 @@ func (r *DynamoDBApiKeyRepository) GetByKeyHash(ctx context.Context, keyHash str
 		fmt.Printf("Failed to update last_used_at for API key: %v\n", err)
 	}
 
+	return &result.ApiKey, nil
+}
+
+// externalIDKey builds the gsi3 partition key; external IDs are scoped to their account
+func externalIDKey(accountID uuid.UUID, externalID string) string {
+	return fmt.Sprintf("EXTID#%s#%s", accountID.String(), externalID)
+}
+
+// GetByExternalID retrieves an account's API key by its external ID
+func (r *DynamoDBApiKeyRepository) GetByExternalID(ctx context.Context, accountID uuid.UUID, externalID string) (*domain.ApiKey, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.client.GetTableName()),
+		IndexName:              aws.String(db.GSI3IndexName),
+		KeyConditionExpression: aws.String("gsi3pk = :gsi3pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":gsi3pk": &types.AttributeValueMemberS{Value: externalIDKey(accountID, externalID)},
+		},
+		Limit: aws.Int32(1),
+	}
+
+	var results []DynamoDBApiKey
+	err := r.client.QueryItems(ctx, input, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API key by external ID: %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil, nil // API key not found
+	}
+
+	return &results[0].ApiKey, nil
+}
+
+// keyNameKey is the gsi4 partition key finding keys by name across accounts
+func keyNameKey(name string) string {
+	return fmt.Sprintf("KEYNAME#%s", name)
+}
+
+// FindLiveByName retrieves a live API key with the name. Within an account this
+// reads the account's partition; across accounts (uuid.Nil) it queries the
+// name index, which only holds keys written since it was added.
+func (r *DynamoDBApiKeyRepository) FindLiveByName(ctx context.Context, accountID uuid.UUID, name string) (*domain.ApiKey, error) {
+	exprAttrNames := map[string]string{
+		"#n": "Name",
+		"#s": "Status",
+	}
+	exprAttrValues := map[string]types.AttributeValue{
+		":name":    &types.AttributeValueMemberS{Value: name},
+		":revoked": &types.AttributeValueMemberS{Value: string(domain.ApiKeyStatusInactive)},
+	}
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.client.GetTableName()),
+		FilterExpression:          aws.String("#n = :name AND #s <> :revoked"),
+		ExpressionAttributeNames:  exprAttrNames,
+		ExpressionAttributeValues: exprAttrValues,
+	}
+	if accountID != uuid.Nil {
+		input.KeyConditionExpression = aws.String("pk = :pk AND begins_with(sk, :sk_prefix)")
+		exprAttrValues[":pk"] = &types.AttributeValueMemberS{Value: fmt.Sprintf("ACCOUNT#%s", accountID.String())}
+		exprAttrValues[":sk_prefix"] = &types.AttributeValueMemberS{Value: "APIKEY#"}
+	} else {
+		input.IndexName = aws.String(db.GSI4IndexName)
+		input.KeyConditionExpression = aws.String("gsi4pk = :gsi4pk")
+		exprAttrValues[":gsi4pk"] = &types.AttributeValueMemberS{Value: keyNameKey(name)}
+	}
+
+	var results []DynamoDBApiKey
+	if err := r.client.QueryAllItems(ctx, input, &results); err != nil {
+		return nil, fmt.Errorf("failed to query API keys by name: %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil, nil // No live key with this name
+	}
+
 	return &results[0].ApiKey, nil
 }
 
@@ -144,7 +301,7 @@ func (r *DynamoDBApiKeyRepository) GetByAccountID(ctx context.Context, accountID
 	}
 
 	var results []DynamoDBApiKey
-	err := r.client.QueryItems(ctx, input, &results)
+	err := r.client.QueryAllItems(ctx, input, &results)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query API keys by account: %w", err)
 	}
@@ -158,8 +315,59 @@ func (r *DynamoDBApiKeyRepository) GetByAccountID(ctx context.Context, accountID
 }
 
 // ValidateByKey validates an API key by comparing the raw key with stored hashes
-// This method uses SHA256 for consistent hashing and efficient GSI lookup
+// This method uses SHA256 for consistent hashing and efficient GSI lookup.
+// Expired keys are returned; whether they are still accepted is up to the caller.
+// Usage is recorded with one conditional write that also re-reads the key from
+// the base table, since the index may still show a key revoked or purged moments
+// ago. If that write fails for another reason, the indexed copy is returned.
 func (r *DynamoDBApiKeyRepository) ValidateByKey(ctx context.Context, rawKey string) (*domain.ApiKey, error) {
+	result, err := r.queryByRawKey(ctx, rawKey)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	key, err := db.CreateCompositeKey("pk", result.PK, "sk", result.SK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key for update: %w", err)
+	}
+
+	now := time.Now()
+	updateExpr := "SET LastUsedAt = :l"
+	conditionExpr := "attribute_exists(pk) AND KeyHash = :h"
+	exprAttrValues := map[string]types.AttributeValue{
+		":l": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339)},
+		":h": &types.AttributeValueMemberS{Value: result.KeyHash},
+	}
+
+	var stored DynamoDBApiKey
+	err = r.client.UpdateAndGetItem(ctx, key, updateExpr, conditionExpr, nil, exprAttrValues, &stored)
+	switch {
+	case errors.Is(err, db.ErrConditionFailed):
+		// Gone or rehashed since the index was written
+		return nil, nil
+	case err != nil:
+		// Log error but don't fail the request
+		fmt.Printf("Failed to update last_used_at for API key: %v\n", err)
+		result.LastUsedAt = &now
+		return &result.ApiKey, nil
+	}
+
+	return &stored.ApiKey, nil
+}
+
+// FindByKey retrieves the API key for a raw key like ValidateByKey, but without
+// touching last_used_at
+func (r *DynamoDBApiKeyRepository) FindByKey(ctx context.Context, rawKey string) (*domain.ApiKey, error) {
+	result, err := r.queryByRawKey(ctx, rawKey)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	return &result.ApiKey, nil
+}
+
+// queryByRawKey looks up the stored item for a raw key
+func (r *DynamoDBApiKeyRepository) queryByRawKey(ctx context.Context, rawKey string) (*DynamoDBApiKey, error) {
 	// Use SHA256 for consistent hashing (bcrypt generates different hashes each time)
 	hash := sha256.Sum256([]byte(rawKey))
 	hashStr := hex.EncodeToString(hash[:])
@@ -191,92 +399,156 @@ func (r *DynamoDBApiKeyRepository) ValidateByKey(ctx context.Context, rawKey str
 		return nil, nil // Hash mismatch, treat as not found
 	}
 
-	// Check if the key is expired
-	if results[0].IsExpired() {
-		return nil, nil // Key is expired, treat as not found
-	}
-
-	// Update last used timestamp
-	now := time.Now()
-	results[0].LastUsedAt = &now
-
-	// Update the last used timestamp
-	key, err := db.CreateCompositeKey("pk", results[0].PK, "sk", results[0].SK)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create key for update: %w", err)
-	}
-
-	updateExpr := "SET last_used_at = :l"
-	exprAttrValues := map[string]types.AttributeValue{
-		":l": &types.AttributeValueMemberS{Value: now.Format(time.RFC3339)},
-	}
+	return &results[0], nil
+}
 
-	err = r.client.UpdateItem(ctx, key, updateExpr, nil, exprAttrValues, nil)
-	if err != nil {
-		// Log error but don't fail the request
-		fmt.Printf("Failed to update last_used_at for API key: %v\n", err)
+// versionCondition builds a condition that the stored version still matches the
+// version that was read. Items written before versioning have no version attribute.
+// Attribute names match the untagged domain.ApiKey fields as marshalled by PutItem.
+func versionCondition(version int, exprAttrNames map[string]string, exprAttrValues map[string]types.AttributeValue) string {
+	exprAttrNames["#v"] = "Version"
+	if version == 0 {
+		return "attribute_not_exists(#v)"
 	}
-
-	return &results[0].ApiKey, nil
+	exprAttrValues[":expected_v"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", version)}
+	return "#v = :expected_v"
 }
 
-// Update updates an existing API key
+// Update updates an existing API key, including its hash and the key hash index.
+// Returns domain.ErrVersionConflict if the key changed since apiKey was read.
 func (r *DynamoDBApiKeyRepository) Update(ctx context.Context, apiKey *domain.ApiKey) error {
 	key, err := db.CreateCompositeKey("pk", fmt.Sprintf("ACCOUNT#%s", apiKey.AccountID.String()), "sk", fmt.Sprintf("APIKEY#%s", apiKey.ID.String()))
 	if err != nil {
 		return fmt.Errorf("failed to create key: %w", err)
 	}
 
-	updateExpr := "SET #n = :n, #p = :p, #s = :s, #e = :e, #t = :t"
+	updateExpr := "SET #n = :n, #p = :p, #s = :s, #e = :e, #t = :t, #v = :v, #h = :h, #g = :g, #gn = :gn"
 	exprAttrNames := map[string]string{
-		"#n": "name",
-		"#p": "permissions",
-		"#s": "status",
-		"#e": "expires_at",
-		"#t": "ttl",
+		"#h":  "KeyHash",
+		"#g":  "gsi1pk",
+		"#gn": "gsi4pk",
+		"#n":  "Name",
+		"#p":  "Permissions",
+		"#s":  "Status",
+		"#e":  "ExpiresAt",
+		"#t":  "ttl",
 	}
 	exprAttrValues := map[string]types.AttributeValue{
-		":n": &types.AttributeValueMemberS{Value: apiKey.Name},
-		":p": &types.AttributeValueMemberSS{Value: apiKey.Permissions},
-		":s": &types.AttributeValueMemberS{Value: string(apiKey.Status)},
-		":e": &types.AttributeValueMemberS{Value: apiKey.ExpiresAt.Format(time.RFC3339)},
-		":t": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", apiKey.ExpiresAt.Unix())}, // Update TTL when expiration changes
+		":n":  &types.AttributeValueMemberS{Value: apiKey.Name},
+		":p":  &types.AttributeValueMemberSS{Value: apiKey.Permissions},
+		":s":  &types.AttributeValueMemberS{Value: string(apiKey.Status)},
+		":e":  &types.AttributeValueMemberS{Value: apiKey.ExpiresAt.Format(time.RFC3339)},
+		":t":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", ttlFor(apiKey.ExpiresAt))}, // Update TTL when expiration changes
+		":v":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", apiKey.Version+1)},
+		":h":  &types.AttributeValueMemberS{Value: apiKey.KeyHash},
+		":g":  &types.AttributeValueMemberS{Value: fmt.Sprintf("KEYHASH#%s", apiKey.KeyHash)}, // Keep hash lookups pointing at the current secret
+		":gn": &types.AttributeValueMemberS{Value: keyNameKey(apiKey.Name)},
+	}
+	conditionExpr := versionCondition(apiKey.Version, exprAttrNames, exprAttrValues)
+
+	if apiKey.ExpiryWarningSentAt != nil {
+		updateExpr += ", #w = :w"
+		exprAttrNames["#w"] = "ExpiryWarningSentAt"
+		exprAttrValues[":w"] = &types.AttributeValueMemberS{Value: apiKey.ExpiryWarningSentAt.Format(time.RFC3339)}
+	}
+	if apiKey.KeyHint != "" {
+		updateExpr += ", #kh = :kh"
+		exprAttrNames["#kh"] = "KeyHint"
+		exprAttrValues[":kh"] = &types.AttributeValueMemberS{Value: apiKey.KeyHint}
 	}
 
 	var updatedApiKey DynamoDBApiKey
-	err = r.client.UpdateItem(ctx, key, updateExpr, exprAttrNames, exprAttrValues, &updatedApiKey)
+	err = r.client.UpdateItemWithCondition(ctx, key, updateExpr, conditionExpr, exprAttrNames, exprAttrValues, &updatedApiKey)
+	if errors.Is(err, db.ErrConditionFailed) {
+		return domain.ErrVersionConflict
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update API key: %w", err)
 	}
 
+	apiKey.Version++
 	return nil
 }
 
-// Delete soft deletes an API key by setting status to inactive
+// maxRevokeAttempts bounds retries when a revocation races a concurrent update
+const maxRevokeAttempts = 3
+
+// Delete soft deletes an API key by setting status to inactive.
+// The write is conditional on the version read, and is retried on conflict so a
+// concurrent update can neither clobber nor be silently overwritten by the revocation.
 func (r *DynamoDBApiKeyRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	// First get the API key to get account ID
-	apiKey, err := r.GetByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("failed to get API key for deletion: %w", err)
-	}
-	if apiKey == nil {
-		return fmt.Errorf("API key not found")
+	for attempt := 1; ; attempt++ {
+		// First get the API key to get account ID and current version
+		apiKey, err := r.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get API key for deletion: %w", err)
+		}
+		if apiKey == nil {
+			return fmt.Errorf("API key not found")
+		}
+
+		err = r.revokeAtVersion(ctx, apiKey)
+		if errors.Is(err, domain.ErrVersionConflict) && attempt < maxRevokeAttempts {
+			continue // Re-read the latest version and try again
+		}
+		return err
 	}
+}
 
-	key, err := db.CreateCompositeKey("pk", fmt.Sprintf("ACCOUNT#%s", apiKey.AccountID.String()), "sk", fmt.Sprintf("APIKEY#%s", id.String()))
+// revokeAtVersion revokes the key as read, conditional on its version.
+// Returns domain.ErrVersionConflict if the key changed since apiKey was read.
+func (r *DynamoDBApiKeyRepository) revokeAtVersion(ctx context.Context, apiKey *domain.ApiKey) error {
+	key, err := db.CreateCompositeKey("pk", fmt.Sprintf("ACCOUNT#%s", apiKey.AccountID.String()), "sk", fmt.Sprintf("APIKEY#%s", apiKey.ID.String()))
 	if err != nil {
 		return fmt.Errorf("failed to create key: %w", err)
 	}
 
-	updateExpr := "SET #s = :s"
+	// Keep the first revocation time when an already revoked key is revoked again
+	updateExpr := "SET #s = :s, #v = :v, RevokedAt = if_not_exists(RevokedAt, :r)"
 	exprAttrNames := map[string]string{
-		"#s": "status",
+		"#s": "Status",
 	}
 	exprAttrValues := map[string]types.AttributeValue{
 		":s": &types.AttributeValueMemberS{Value: string(domain.ApiKeyStatusInactive)},
+		":v": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", apiKey.Version+1)},
+		":r": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339Nano)},
+	}
+	conditionExpr := versionCondition(apiKey.Version, exprAttrNames, exprAttrValues)
+
+	// Revoking a live key frees its slot in the account's key counter. Already
+	// revoked keys were uncounted when they were revoked, so repeat revocations
+	// leave the counter alone; expired keys are left out of every recount, so
+	// decrementing for them would free a slot that was never held.
+	if apiKey.Status != domain.ApiKeyStatusInactive && !apiKey.IsExpired() {
+		err = r.client.TransactWriteItems(ctx, []types.TransactWriteItem{
+			{Update: &types.Update{
+				Key:                       key,
+				UpdateExpression:          aws.String(updateExpr),
+				ConditionExpression:       aws.String(conditionExpr),
+				ExpressionAttributeNames:  exprAttrNames,
+				ExpressionAttributeValues: exprAttrValues,
+			}},
+			{Update: counterDecrement(apiKey.AccountID)},
+		})
+
+		var conditionErr *db.TransactionConditionError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &conditionErr) && conditionErr.ItemFailed(0):
+			return domain.ErrVersionConflict
+		case errors.As(err, &conditionErr):
+			// No counter yet (or already zero); the next recount will be exact,
+			// so revoke without touching it
+		default:
+			return fmt.Errorf("failed to delete API key: %w", err)
+		}
+	}
+
+	err = r.client.UpdateItemWithCondition(ctx, key, updateExpr, conditionExpr, exprAttrNames, exprAttrValues, nil)
+	if errors.Is(err, db.ErrConditionFailed) {
+		return domain.ErrVersionConflict
 	}
-
-	err = r.client.UpdateItem(ctx, key, updateExpr, exprAttrNames, exprAttrValues, nil)
 	if err != nil {
 		return fmt.Errorf("failed to delete API key: %w", err)
 	}
@@ -290,6 +562,23 @@ func (r *DynamoDBApiKeyRepository) Revoke(ctx context.Context, id uuid.UUID) err
 	return r.Delete(ctx, id)
 }
 
+// RevokeVersion revokes an API key only while it is still at the given version.
+// Unlike Delete it does not retry, since any concurrent change is a conflict.
+func (r *DynamoDBApiKeyRepository) RevokeVersion(ctx context.Context, id uuid.UUID, version int) error {
+	apiKey, err := r.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get API key for deletion: %w", err)
+	}
+	if apiKey == nil {
+		return fmt.Errorf("API key not found")
+	}
+	if apiKey.Version != version {
+		return domain.ErrVersionConflict
+	}
+
+	return r.revokeAtVersion(ctx, apiKey)
+}
+
 // List retrieves API keys with pagination
 func (r *DynamoDBApiKeyRepository) List(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]*domain.ApiKey, error) {
 	// Query API keys for an account with pagination
@@ -322,3 +611,130 @@ func (r *DynamoDBApiKeyRepository) List(ctx context.Context, accountID uuid.UUID
 
 	return apiKeys, nil
 }
+
+// ListRevokedSince retrieves an account's keys revoked after since, most recent first.
+// RevokedAt is compared after loading because stored timestamps don't sort as strings.
+func (r *DynamoDBApiKeyRepository) ListRevokedSince(ctx context.Context, accountID uuid.UUID, since time.Time) ([]*domain.ApiKey, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.client.GetTableName()),
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk_prefix)"),
+		FilterExpression:       aws.String("#s = :inactive AND attribute_exists(RevokedAt)"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":        &types.AttributeValueMemberS{Value: fmt.Sprintf("ACCOUNT#%s", accountID.String())},
+			":sk_prefix": &types.AttributeValueMemberS{Value: "APIKEY#"},
+			":inactive":  &types.AttributeValueMemberS{Value: string(domain.ApiKeyStatusInactive)},
+		},
+	}
+
+	var results []DynamoDBApiKey
+	if err := r.client.QueryAllItems(ctx, input, &results); err != nil {
+		return nil, fmt.Errorf("failed to query revoked API keys: %w", err)
+	}
+
+	apiKeys := make([]*domain.ApiKey, 0, len(results))
+	for i := range results {
+		if results[i].RevokedAt != nil && results[i].RevokedAt.After(since) {
+			apiKeys = append(apiKeys, &results[i].ApiKey)
+		}
+	}
+	sort.Slice(apiKeys, func(i, j int) bool {
+		return apiKeys[i].RevokedAt.After(*apiKeys[j].RevokedAt)
+	})
+
+	return apiKeys, nil
+}
+
+// PurgeByAccountID deletes every API key item in the account's partition and
+// then its key counter. The account item shares the partition and is left alone.
+func (r *DynamoDBApiKeyRepository) PurgeByAccountID(ctx context.Context, accountID uuid.UUID) (int, error) {
+	apiKeys, err := r.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, apiKey := range apiKeys {
+		key, err := db.CreateCompositeKey("pk", fmt.Sprintf("ACCOUNT#%s", accountID.String()), "sk", fmt.Sprintf("APIKEY#%s", apiKey.ID.String()))
+		if err != nil {
+			return purged, fmt.Errorf("failed to create key: %w", err)
+		}
+		if err := r.client.DeleteItem(ctx, key); err != nil {
+			return purged, fmt.Errorf("failed to purge API key: %w", err)
+		}
+		purged++
+	}
+
+	if err := r.client.DeleteItem(ctx, keyCounterKey(accountID)); err != nil {
+		return purged, fmt.Errorf("failed to purge key counter: %w", err)
+	}
+
+	return purged, nil
+}
+
+// ForEachByAccountID pages through an account's API keys, following
+// LastEvaluatedKey so only one page is loaded at a time
+func (r *DynamoDBApiKeyRepository) ForEachByAccountID(ctx context.Context, accountID uuid.UUID, pageSize int, fn func([]*domain.ApiKey) error) error {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.client.GetTableName()),
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk_prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":        &types.AttributeValueMemberS{Value: fmt.Sprintf("ACCOUNT#%s", accountID.String())},
+			":sk_prefix": &types.AttributeValueMemberS{Value: "APIKEY#"},
+		},
+		Limit: aws.Int32(int32(pageSize)),
+	}
+
+	return r.client.QueryPages(ctx, input, func(items []map[string]types.AttributeValue) error {
+		var results []DynamoDBApiKey
+		if err := attributevalue.UnmarshalListOfMaps(items, &results); err != nil {
+			return fmt.Errorf("failed to unmarshal API keys: %w", err)
+		}
+
+		apiKeys := make([]*domain.ApiKey, len(results))
+		for i := range results {
+			apiKeys[i] = &results[i].ApiKey
+		}
+
+		return fn(apiKeys)
+	})
+}
+
+// ListExpiringBefore retrieves active API keys that expire before the cutoff.
+// Keys that have already expired but still carry active status are included.
+func (r *DynamoDBApiKeyRepository) ListExpiringBefore(ctx context.Context, cutoff time.Time) ([]*domain.ApiKey, error) {
+	// The ttl attribute is expires_at plus ExpiredKeyRetention as epoch seconds, so
+	// filter on it numerically. Keys written before the retention was added have
+	// ttl equal to expires_at; the scan over-selects those and the exact cutoff is
+	// applied below.
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(r.client.GetTableName()),
+		FilterExpression: aws.String("begins_with(sk, :sk_prefix) AND #s = :s AND #t < :cutoff"),
+		ExpressionAttributeNames: map[string]string{
+			"#s": "Status",
+			"#t": "ttl",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":sk_prefix": &types.AttributeValueMemberS{Value: "APIKEY#"},
+			":s":         &types.AttributeValueMemberS{Value: string(domain.ApiKeyStatusActive)},
+			":cutoff":    &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", ttlFor(cutoff))},
+		},
+	}
+
+	var results []DynamoDBApiKey
+	err := r.client.ScanAllItems(ctx, input, &results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for expiring API keys: %w", err)
+	}
+
+	apiKeys := make([]*domain.ApiKey, 0, len(results))
+	for i := range results {
+		if results[i].ExpiresAt.Before(cutoff) {
+			apiKeys = append(apiKeys, &results[i].ApiKey)
+		}
+	}
+
+	return apiKeys, nil
+}
@@ -159,6 +159,20 @@ func (r *DynamoDBAppRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// Purge hard deletes an account item
+func (r *DynamoDBAppRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	key, err := db.CreateCompositeKey("pk", fmt.Sprintf("ACCOUNT#%s", id.String()), "sk", "ACCOUNT")
+	if err != nil {
+		return fmt.Errorf("failed to create key: %w", err)
+	}
+
+	if err := r.client.DeleteItem(ctx, key); err != nil {
+		return fmt.Errorf("failed to purge account: %w", err)
+	}
+
+	return nil
+}
+
 // List retrieves accounts with pagination
 func (r *DynamoDBAppRepository) List(ctx context.Context, limit, offset int) ([]*domain.Account, error) {
 	// Query all accounts with pagination
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
@@ -29,13 +32,71 @@ func NewDynamoDBIdempotencyKeyRepository(client *db.DynamoDBClient) *DynamoDBIde
 // DynamoDBIdempotencyKey represents the IdempotencyKey entity in DynamoDB
 type DynamoDBIdempotencyKey struct {
 	domain.IdempotencyKey
-	PK  string `dynamodbav:"pk" json:"pk"`
-	SK  string `dynamodbav:"sk" json:"sk"`
-	TTL int64  `dynamodbav:"ttl" json:"ttl"` // For automatic expiration
+	PK     string `dynamodbav:"pk" json:"pk"`
+	SK     string `dynamodbav:"sk" json:"sk"`
+	GSI1PK string `dynamodbav:"gsi1pk" json:"gsi1pk"` // For lookup by request hash
+	GSI2PK string `dynamodbav:"gsi2pk" json:"gsi2pk"` // For lookup by account ID
+	TTL    int64  `dynamodbav:"ttl" json:"ttl"`       // For automatic expiration
 }
 
-// Create creates a new idempotency key
+// requestClaimKey is the item that makes request hashes unique. Keys themselves
+// are stored under their own ID, so a condition on their item alone cannot stop
+// two requests with the same hash from both creating one.
+func requestClaimKey(requestHash string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("IDEMPOTENCY_REQUEST#%s", requestHash)},
+		"sk": &types.AttributeValueMemberS{Value: "CLAIM"},
+	}
+}
+
+// idempotencyCounterSK is the sort key of the per-account item counting
+// unexpired idempotency keys
+const idempotencyCounterSK = "COUNT"
+
+// dynamoDBIdempotencyCounter is the stored counter item. LiveKeys counts the
+// account's idempotency keys; keys stay counted after they expire until a
+// recount drops them, so the counter may over-count but never under-counts.
+type dynamoDBIdempotencyCounter struct {
+	PK       string `dynamodbav:"pk"`
+	SK       string `dynamodbav:"sk"`
+	LiveKeys int    `dynamodbav:"LiveKeys"`
+}
+
+// idempotencyCounterKey returns the primary key of an account's counter item
+func idempotencyCounterKey(accountID uuid.UUID) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("IDEMPOTENCY_COUNT#%s", accountID.String())},
+		"sk": &types.AttributeValueMemberS{Value: idempotencyCounterSK},
+	}
+}
+
+// idempotencyCounterIncrement adds one to an existing counter that is below maxKeys
+func idempotencyCounterIncrement(accountID uuid.UUID, maxKeys int) *types.Update {
+	return &types.Update{
+		Key:                 idempotencyCounterKey(accountID),
+		UpdateExpression:    aws.String("ADD LiveKeys :one"),
+		ConditionExpression: aws.String("attribute_exists(LiveKeys) AND LiveKeys < :max"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one": &types.AttributeValueMemberN{Value: "1"},
+			":max": &types.AttributeValueMemberN{Value: strconv.Itoa(maxKeys)},
+		},
+	}
+}
+
+// Create creates a new idempotency key together with a claim on its request
+// hash, in one transaction. The claim is conditional, so of two concurrent
+// creates for the same request only one succeeds; the other gets
+// domain.ErrIdempotencyKeyInProgress. An expired claim can be taken over.
 func (r *DynamoDBIdempotencyKeyRepository) Create(ctx context.Context, key *domain.IdempotencyKey) error {
+	return r.CreateWithinLimit(ctx, key, 0)
+}
+
+// CreateWithinLimit works like Create. With maxKeys > 0 the transaction also
+// increments the account's counter on condition that it is below maxKeys, so
+// two concurrent creates cannot both take the last slot; when the account is
+// full domain.ErrIdempotencyKeyLimitExceeded is returned. A missing or
+// over-counted counter is recounted before rejecting.
+func (r *DynamoDBIdempotencyKeyRepository) CreateWithinLimit(ctx context.Context, key *domain.IdempotencyKey, maxKeys int) error {
 	// Set timestamps before creation
 	now := time.Now()
 	key.CreatedAt = now
@@ -48,10 +109,116 @@ func (r *DynamoDBIdempotencyKeyRepository) Create(ctx context.Context, key *doma
 		IdempotencyKey: *key,
 		PK:             fmt.Sprintf("IDEMPOTENCY#%s", key.ID.String()),
 		SK:             fmt.Sprintf("KEY#%s", key.ID.String()),
+		GSI1PK:         fmt.Sprintf("REQUEST#%s", key.RequestHash),
+		GSI2PK:         fmt.Sprintf("IDEMPOTENCY_ACCOUNT#%s", key.AccountID.String()),
 		TTL:            key.ExpiresAt.Unix(), // Set TTL to expiration time
 	}
+	item, err := attributevalue.MarshalMap(dynamoKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency key: %w", err)
+	}
+
+	expiresAt := &types.AttributeValueMemberN{Value: strconv.FormatInt(key.ExpiresAt.Unix(), 10)}
+	claim := requestClaimKey(key.RequestHash)
+	claim["idempotency_key_id"] = &types.AttributeValueMemberS{Value: key.ID.String()}
+	claim["ttl"] = expiresAt
+
+	for attempt := 1; ; attempt++ {
+		items := []types.TransactWriteItem{
+			{Put: &types.Put{
+				Item: claim,
+				// DynamoDB TTL deletes lazily, so an expired claim may still be present
+				ConditionExpression: aws.String("attribute_not_exists(pk) OR #ttl < :now"),
+				ExpressionAttributeNames: map[string]string{
+					"#ttl": "ttl",
+				},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+				},
+			}},
+			{Put: &types.Put{Item: item, ConditionExpression: aws.String("attribute_not_exists(pk)")}},
+		}
+		if maxKeys > 0 {
+			items = append(items, types.TransactWriteItem{Update: idempotencyCounterIncrement(key.AccountID, maxKeys)})
+		}
+
+		err := r.client.TransactWriteItems(ctx, items)
+		var conditionErr *db.TransactionConditionError
+		if !errors.As(err, &conditionErr) {
+			if err != nil {
+				return fmt.Errorf("failed to create idempotency key: %w", err)
+			}
+			return nil
+		}
+		if !conditionErr.ItemFailed(2) || conditionErr.ItemFailed(0) || conditionErr.ItemFailed(1) {
+			return domain.ErrIdempotencyKeyInProgress
+		}
+		if attempt == maxCounterAttempts {
+			return domain.ErrVersionConflict
+		}
+
+		// The counter is missing or at the limit; it may over-count expired keys,
+		// so recount before deciding
+		count, err := r.recountLiveKeys(ctx, key.AccountID)
+		if errors.Is(err, db.ErrConditionFailed) {
+			continue // Changed during the recount; try again
+		}
+		if err != nil {
+			return err
+		}
+		if count >= maxKeys {
+			return domain.ErrIdempotencyKeyLimitExceeded
+		}
+	}
+}
+
+// recountLiveKeys counts the account's unexpired idempotency keys and stores the
+// result in the counter. The write is conditional on the counter value read
+// beforehand, so a create that lands during the recount makes it fail with
+// db.ErrConditionFailed instead of being lost.
+func (r *DynamoDBIdempotencyKeyRepository) recountLiveKeys(ctx context.Context, accountID uuid.UUID) (int, error) {
+	var current dynamoDBIdempotencyCounter
+	if err := r.client.GetItem(ctx, idempotencyCounterKey(accountID), &current); err != nil {
+		return 0, fmt.Errorf("failed to get idempotency key counter: %w", err)
+	}
+
+	keys, err := r.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, key := range keys {
+		if !key.IsExpired() {
+			count++
+		}
+	}
+
+	condition := "attribute_not_exists(LiveKeys)"
+	values := map[string]types.AttributeValue{}
+	if current.SK != "" {
+		condition = "LiveKeys = :old"
+		values[":old"] = &types.AttributeValueMemberN{Value: strconv.Itoa(current.LiveKeys)}
+	}
+
+	item := idempotencyCounterKey(accountID)
+	item["LiveKeys"] = &types.AttributeValueMemberN{Value: strconv.Itoa(count)}
+	put := types.TransactWriteItem{Put: &types.Put{
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+	}}
+	if len(values) > 0 {
+		put.Put.ExpressionAttributeValues = values
+	}
+
+	if err := r.client.TransactWriteItems(ctx, []types.TransactWriteItem{put}); err != nil {
+		if errors.Is(err, db.ErrConditionFailed) {
+			return 0, db.ErrConditionFailed
+		}
+		return 0, fmt.Errorf("failed to store idempotency key counter: %w", err)
+	}
 
-	return r.client.PutItem(ctx, dynamoKey)
+	return count, nil
 }
 
 // GetByID retrieves an idempotency key by its ID
@@ -108,18 +275,18 @@ func (r *DynamoDBIdempotencyKeyRepository) GetByRequestHash(ctx context.Context,
 
 // GetByAccountID retrieves all idempotency keys for an account
 func (r *DynamoDBIdempotencyKeyRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID) ([]*domain.IdempotencyKey, error) {
-	// Query all idempotency keys for an account
+	// Query all idempotency keys for an account using GSI2
 	input := &dynamodb.QueryInput{
 		TableName:              aws.String(r.client.GetTableName()),
-		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk_prefix)"),
+		IndexName:              aws.String("gsi2"), // GSI for account lookup
+		KeyConditionExpression: aws.String("gsi2pk = :gsi2pk"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":pk":        &types.AttributeValueMemberS{Value: fmt.Sprintf("IDEMPOTENCY#%s", accountID.String())},
-			":sk_prefix": &types.AttributeValueMemberS{Value: "KEY#"},
+			":gsi2pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("IDEMPOTENCY_ACCOUNT#%s", accountID.String())},
 		},
 	}
 
 	var results []DynamoDBIdempotencyKey
-	err := r.client.QueryItems(ctx, input, &results)
+	err := r.client.QueryAllItems(ctx, input, &results)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query idempotency keys by account: %w", err)
 	}
@@ -139,14 +306,20 @@ func (r *DynamoDBIdempotencyKeyRepository) Update(ctx context.Context, key *doma
 		return fmt.Errorf("failed to create key: %w", err)
 	}
 
-	updateExpr := "SET #s = :s, #r = :r"
+	// Create writes the untagged domain struct, so these attributes carry its
+	// field names; snake_case names would not be read back into the struct
+	updateExpr := "SET #s = :s, #r = :r, #rt = :rt, #rs = :rs"
 	exprAttrNames := map[string]string{
-		"#s": "status",
-		"#r": "response",
+		"#s":  "Status",
+		"#r":  "Response",
+		"#rt": "ResponseTruncated",
+		"#rs": "ResponseStatus",
 	}
 	exprAttrValues := map[string]types.AttributeValue{
-		":s": &types.AttributeValueMemberS{Value: string(key.Status)},
-		":r": &types.AttributeValueMemberS{Value: key.Response},
+		":s":  &types.AttributeValueMemberS{Value: string(key.Status)},
+		":r":  &types.AttributeValueMemberS{Value: key.Response},
+		":rt": &types.AttributeValueMemberBOOL{Value: key.ResponseTruncated},
+		":rs": &types.AttributeValueMemberN{Value: strconv.Itoa(key.ResponseStatus)},
 	}
 
 	var updatedKey DynamoDBIdempotencyKey
@@ -167,7 +340,7 @@ func (r *DynamoDBIdempotencyKeyRepository) Delete(ctx context.Context, id uuid.U
 
 	updateExpr := "SET #s = :s"
 	exprAttrNames := map[string]string{
-		"#s": "status",
+		"#s": "Status",
 	}
 	exprAttrValues := map[string]types.AttributeValue{
 		":s": &types.AttributeValueMemberS{Value: string(domain.IdempotencyKeyStatusExpired)},
@@ -214,3 +387,33 @@ func (r *DynamoDBIdempotencyKeyRepository) CleanupExpired(ctx context.Context) e
 
 	return nil
 }
+
+// PurgeByAccountID hard deletes all of an account's idempotency keys, expired ones
+// included, their request claims and the account's counter
+func (r *DynamoDBIdempotencyKeyRepository) PurgeByAccountID(ctx context.Context, accountID uuid.UUID) (int, error) {
+	keys, err := r.GetByAccountID(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, key := range keys {
+		compositeKey, err := db.CreateCompositeKey("pk", fmt.Sprintf("IDEMPOTENCY#%s", key.ID.String()), "sk", fmt.Sprintf("KEY#%s", key.ID.String()))
+		if err != nil {
+			return purged, fmt.Errorf("failed to create key: %w", err)
+		}
+		if err := r.client.DeleteItem(ctx, compositeKey); err != nil {
+			return purged, fmt.Errorf("failed to purge idempotency key: %w", err)
+		}
+		if err := r.client.DeleteItem(ctx, requestClaimKey(key.RequestHash)); err != nil {
+			return purged, fmt.Errorf("failed to purge idempotency request claim: %w", err)
+		}
+		purged++
+	}
+
+	if err := r.client.DeleteItem(ctx, idempotencyCounterKey(accountID)); err != nil {
+		return purged, fmt.Errorf("failed to purge idempotency key counter: %w", err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/common/db"
+)
+
+// keyCounterSK is the sort key of the per-account item counting live API keys.
+// It lives in the account's partition but never matches the APIKEY# prefix, so
+// key queries do not see it.
+const keyCounterSK = "KEYCOUNT"
+
+// maxCounterAttempts bounds recount-and-retry rounds when the counter is missing,
+// at the quota, or changed underneath a recount
+const maxCounterAttempts = 3
+
+// dynamoDBKeyCounter is the stored counter item. ActiveKeys counts keys that are
+// not revoked. Expired keys stay counted until they are revoked or a recount
+// drops them, so the counter may over-count but never under-counts.
+type dynamoDBKeyCounter struct {
+	PK         string `dynamodbav:"pk"`
+	SK         string `dynamodbav:"sk"`
+	ActiveKeys int    `dynamodbav:"ActiveKeys"`
+}
+
+// keyCounterKey returns the primary key of an account's counter item
+func keyCounterKey(accountID uuid.UUID) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"pk": &types.AttributeValueMemberS{Value: fmt.Sprintf("ACCOUNT#%s", accountID.String())},
+		"sk": &types.AttributeValueMemberS{Value: keyCounterSK},
+	}
+}
+
+// counterIncrement adds one to an existing counter. With maxActive > 0 it also
+// requires the counter to be below maxActive.
+func counterIncrement(accountID uuid.UUID, maxActive int) *types.Update {
+	condition := "attribute_exists(ActiveKeys)"
+	values := map[string]types.AttributeValue{
+		":one": &types.AttributeValueMemberN{Value: "1"},
+	}
+	if maxActive > 0 {
+		condition += " AND ActiveKeys < :max"
+		values[":max"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", maxActive)}
+	}
+
+	return &types.Update{
+		Key:                       keyCounterKey(accountID),
+		UpdateExpression:          aws.String("ADD ActiveKeys :one"),
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+	}
+}
+
+// counterDecrement subtracts one from an existing, positive counter
+func counterDecrement(accountID uuid.UUID) *types.Update {
+	return &types.Update{
+		Key:                 keyCounterKey(accountID),
+		UpdateExpression:    aws.String("ADD ActiveKeys :minus_one"),
+		ConditionExpression: aws.String("ActiveKeys > :zero"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":minus_one": &types.AttributeValueMemberN{Value: "-1"},
+			":zero":      &types.AttributeValueMemberN{Value: "0"},
+		},
+	}
+}
+
+// recountActiveKeys counts the account's keys that are neither revoked nor
+// expired and stores the result in the counter. The write is conditional on the
+// counter value read beforehand, so an issuance or revocation that lands during
+// the recount makes it fail with db.ErrConditionFailed instead of being lost.
+func (r *DynamoDBApiKeyRepository) recountActiveKeys(ctx context.Context, accountID uuid.UUID) (int, error) {
+	var current dynamoDBKeyCounter
+	if err := r.client.GetItem(ctx, keyCounterKey(accountID), &current); err != nil {
+		return 0, fmt.Errorf("failed to get key counter: %w", err)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.client.GetTableName()),
+		KeyConditionExpression: aws.String("pk = :pk AND begins_with(sk, :sk_prefix)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk":        &types.AttributeValueMemberS{Value: fmt.Sprintf("ACCOUNT#%s", accountID.String())},
+			":sk_prefix": &types.AttributeValueMemberS{Value: "APIKEY#"},
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	var results []DynamoDBApiKey
+	if err := r.client.QueryAllItems(ctx, input, &results); err != nil {
+		return 0, fmt.Errorf("failed to query API keys for recount: %w", err)
+	}
+
+	count := 0
+	for i := range results {
+		if results[i].Status != domain.ApiKeyStatusInactive && !results[i].IsExpired() {
+			count++
+		}
+	}
+
+	condition := "attribute_not_exists(ActiveKeys)"
+	values := map[string]types.AttributeValue{}
+	if current.SK != "" {
+		condition = "ActiveKeys = :old"
+		values[":old"] = &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", current.ActiveKeys)}
+	}
+
+	item := map[string]types.AttributeValue{
+		"pk":         &types.AttributeValueMemberS{Value: fmt.Sprintf("ACCOUNT#%s", accountID.String())},
+		"sk":         &types.AttributeValueMemberS{Value: keyCounterSK},
+		"ActiveKeys": &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", count)},
+	}
+	put := types.TransactWriteItem{Put: &types.Put{
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+	}}
+	if len(values) > 0 {
+		put.Put.ExpressionAttributeValues = values
+	}
+
+	if err := r.client.TransactWriteItems(ctx, []types.TransactWriteItem{put}); err != nil {
+		if errors.Is(err, db.ErrConditionFailed) {
+			return 0, db.ErrConditionFailed
+		}
+		return 0, fmt.Errorf("failed to store key counter: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountActiveKeys returns the account's counter value, seeding the counter by
+// recounting when the account has none yet. The value may include keys that
+// expired since the last recount.
+func (r *DynamoDBApiKeyRepository) CountActiveKeys(ctx context.Context, accountID uuid.UUID) (int, error) {
+	var counter dynamoDBKeyCounter
+	if err := r.client.GetItem(ctx, keyCounterKey(accountID), &counter); err != nil {
+		return 0, fmt.Errorf("failed to get key counter: %w", err)
+	}
+	if counter.SK != "" {
+		return counter.ActiveKeys, nil
+	}
+
+	return r.RecountActiveKeys(ctx, accountID)
+}
+
+// RecountActiveKeys rebuilds the account's counter from its keys, retrying when
+// an issuance or revocation races the recount
+func (r *DynamoDBApiKeyRepository) RecountActiveKeys(ctx context.Context, accountID uuid.UUID) (int, error) {
+	for attempt := 1; ; attempt++ {
+		count, err := r.recountActiveKeys(ctx, accountID)
+		if !errors.Is(err, db.ErrConditionFailed) {
+			return count, err
+		}
+		if attempt == maxCounterAttempts {
+			return 0, domain.ErrVersionConflict
+		}
+	}
+}
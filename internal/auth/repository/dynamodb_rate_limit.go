@@ -3,12 +3,20 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
 	"github.com/aws-payment-gateway/internal/common/db"
 )
 
-// DynamoDBRateLimitRepository implements RateLimitRepository using DynamoDB
+// DynamoDBRateLimitRepository implements RateLimitRepository using DynamoDB, so
+// limits are shared by every replica. Counters live in the auth table under
+// RATELIMIT#<key>, one item per fixed window, and are incremented atomically;
+// a new window starts from a fresh item rather than resetting the old one.
 type DynamoDBRateLimitRepository struct {
 	client *db.DynamoDBClient
 }
@@ -20,157 +28,93 @@ func NewDynamoDBRateLimitRepository(client *db.DynamoDBClient) *DynamoDBRateLimi
 	}
 }
 
-// DynamoDBRateLimit represents the rate limit entity in DynamoDB
+// DynamoDBRateLimit represents a rate limit window counter in DynamoDB
 type DynamoDBRateLimit struct {
-	Key       string `dynamodbav:"key" json:"key"`
-	Count     int64  `dynamodbav:"count" json:"count"`
-	Window    int64  `dynamodbav:"window" json:"window"`
-	ExpiresAt int64  `dynamodbav:"expires_at" json:"expires_at"`
-	TTL       int64  `dynamodbav:"ttl" json:"ttl"`
+	PK    string `dynamodbav:"pk" json:"pk"`
+	SK    string `dynamodbav:"sk" json:"sk"`
+	Count int64  `dynamodbav:"count" json:"count"`
+	TTL   int64  `dynamodbav:"ttl" json:"ttl"` // For automatic expiration once the window has passed
 }
 
-// CheckRateLimit checks if a request exceeds the rate limit
-func (r *DynamoDBRateLimitRepository) CheckRateLimit(ctx context.Context, key string, requests int, window time.Duration) (bool, int, int64, error) {
-	// Calculate window expiry time
-	now := time.Now()
-	resetTime := now.Add(window).Unix()
-
-	// Get current rate limit entry
-	keyMap, err := db.CreateKey("key", key)
-	if err != nil {
-		return false, requests, resetTime, fmt.Errorf("failed to create key: %w", err)
-	}
-
-	var result DynamoDBRateLimit
-	err = r.client.GetItem(ctx, keyMap, &result)
-	if err != nil {
-		return false, requests, resetTime, fmt.Errorf("failed to get rate limit: %w", err)
-	}
-
-	// If no entry exists, allow the request
-	if result.Key == "" {
-		// Create new entry
-		newEntry := &DynamoDBRateLimit{
-			Key:       key,
-			Count:     1,
-			Window:    int64(window.Seconds()),
-			ExpiresAt: resetTime,
-			TTL:       resetTime,
-		}
-
-		err = r.client.PutItem(ctx, newEntry)
-		if err != nil {
-			return false, requests, resetTime, fmt.Errorf("failed to create rate limit entry: %w", err)
-		}
-
-		return true, requests - 1, resetTime, nil
-	}
-
-	// Check if the entry is still within the current window
-	if result.ExpiresAt < now.Unix() {
-		// Entry expired, reset count
-		updatedEntry := &DynamoDBRateLimit{
-			Key:       key,
-			Count:     1,
-			Window:    result.Window,
-			ExpiresAt: resetTime,
-			TTL:       resetTime,
-		}
-
-		err = r.client.PutItem(ctx, updatedEntry)
-		if err != nil {
-			return false, requests, resetTime, fmt.Errorf("failed to reset rate limit entry: %w", err)
-		}
-
-		return true, requests - 1, resetTime, nil
-	}
-
-	// Check if rate limit exceeded
-	if result.Count >= int64(requests) {
-		remaining := 0
-		if result.Count > int64(requests) {
-			remaining = int(result.Count - int64(requests))
-		}
-		return false, remaining, resetTime, nil
-	}
-
-	// Increment count
-	newCount := result.Count + 1
-	updatedEntry := &DynamoDBRateLimit{
-		Key:       result.Key,
-		Count:     newCount,
-		Window:    result.Window,
-		ExpiresAt: result.ExpiresAt,
-		TTL:       result.ExpiresAt,
-	}
+// rateLimitPK is the partition holding every window of a key
+func rateLimitPK(key string) string {
+	return fmt.Sprintf("RATELIMIT#%s", key)
+}
 
-	err = r.client.PutItem(ctx, updatedEntry)
+// CheckRateLimit counts the request against the key's current window and reports
+// whether it is within the limit. Rejected requests are counted too.
+func (r *DynamoDBRateLimitRepository) CheckRateLimit(ctx context.Context, key string, requests int, window time.Duration) (bool, int, int64, error) {
+	count, resetAt, err := r.increment(ctx, key, window)
 	if err != nil {
-		return false, requests, resetTime, fmt.Errorf("failed to update rate limit entry: %w", err)
+		return false, requests, resetAt.Unix(), err
 	}
 
-	remaining := requests - int(newCount)
+	remaining := requests - int(count)
 	if remaining < 0 {
 		remaining = 0
 	}
-
-	return true, remaining, resetTime, nil
+	return count <= int64(requests), remaining, resetAt.Unix(), nil
 }
 
 // IncrementRateLimit increments the counter for a key
 func (r *DynamoDBRateLimitRepository) IncrementRateLimit(ctx context.Context, key string, window time.Duration) error {
-	now := time.Now()
-	resetTime := now.Add(window).Unix()
+	_, _, err := r.increment(ctx, key, window)
+	return err
+}
 
-	// Get current entry
-	keyMap, err := db.CreateKey("key", key)
-	if err != nil {
-		return fmt.Errorf("failed to create key: %w", err)
-	}
+// increment atomically adds one to the key's counter for the current window and
+// returns the new count and when the window ends
+func (r *DynamoDBRateLimitRepository) increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	windowStart := time.Now().Truncate(window)
+	resetAt := windowStart.Add(window)
 
-	var result DynamoDBRateLimit
-	err = r.client.GetItem(ctx, keyMap, &result)
+	itemKey, err := db.CreateCompositeKey("pk", rateLimitPK(key), "sk", fmt.Sprintf("WINDOW#%d", windowStart.Unix()))
 	if err != nil {
-		return fmt.Errorf("failed to get rate limit: %w", err)
+		return 0, resetAt, fmt.Errorf("failed to create key: %w", err)
 	}
 
-	// Create or update entry
-	if result.Key == "" {
-		// New entry
-		newEntry := &DynamoDBRateLimit{
-			Key:       key,
-			Count:     1,
-			Window:    int64(window.Seconds()),
-			ExpiresAt: resetTime,
-			TTL:       resetTime,
-		}
-
-		return r.client.PutItem(ctx, newEntry)
+	// ADD creates the counter at zero when the window's item does not exist yet
+	updateExpr := "ADD #c :one SET #ttl = if_not_exists(#ttl, :ttl)"
+	exprAttrNames := map[string]string{
+		"#c":   "count",
+		"#ttl": "ttl",
+	}
+	exprAttrValues := map[string]types.AttributeValue{
+		":one": &types.AttributeValueMemberN{Value: "1"},
+		":ttl": &types.AttributeValueMemberN{Value: strconv.FormatInt(resetAt.Unix(), 10)},
 	}
 
-	// Update existing entry
-	updatedEntry := &DynamoDBRateLimit{
-		Key:       result.Key,
-		Count:     result.Count + 1,
-		Window:    result.Window,
-		ExpiresAt: result.ExpiresAt,
-		TTL:       result.ExpiresAt,
+	var result DynamoDBRateLimit
+	if err := r.client.UpdateItem(ctx, itemKey, updateExpr, exprAttrNames, exprAttrValues, &result); err != nil {
+		return 0, resetAt, fmt.Errorf("failed to increment rate limit: %w", err)
 	}
 
-	return r.client.PutItem(ctx, updatedEntry)
+	return result.Count, resetAt, nil
 }
 
-// ResetRateLimit resets the counter for a key
+// ResetRateLimit resets the counter for a key by deleting all of its windows
 func (r *DynamoDBRateLimitRepository) ResetRateLimit(ctx context.Context, key string) error {
-	// Delete the rate limit entry
-	keyMap, err := db.CreateKey("key", key)
-	if err != nil {
-		return fmt.Errorf("failed to create key: %w", err)
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.client.GetTableName()),
+		KeyConditionExpression: aws.String("pk = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: rateLimitPK(key)},
+		},
 	}
 
-	err = r.client.DeleteItem(ctx, keyMap)
-	if err != nil {
-		return fmt.Errorf("failed to reset rate limit: %w", err)
+	var windows []DynamoDBRateLimit
+	if err := r.client.QueryAllItems(ctx, input, &windows); err != nil {
+		return fmt.Errorf("failed to query rate limit windows: %w", err)
+	}
+
+	for _, w := range windows {
+		itemKey, err := db.CreateCompositeKey("pk", w.PK, "sk", w.SK)
+		if err != nil {
+			return fmt.Errorf("failed to create key: %w", err)
+		}
+		if err := r.client.DeleteItem(ctx, itemKey); err != nil {
+			return fmt.Errorf("failed to reset rate limit: %w", err)
+		}
 	}
 
 	return nil
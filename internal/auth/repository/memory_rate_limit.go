@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxInMemoryRateLimitEntries bounds the map before expired entries are swept
+const maxInMemoryRateLimitEntries = 10000
+
+// InMemoryRateLimitRepository implements RateLimitRepository in process memory.
+// Limits are per replica; use a shared store when running multiple instances.
+type InMemoryRateLimitRepository struct {
+	mu      sync.Mutex
+	entries map[string]*inMemoryRateLimitEntry
+}
+
+type inMemoryRateLimitEntry struct {
+	count     int
+	expiresAt time.Time
+}
+
+// NewInMemoryRateLimitRepository creates a new InMemoryRateLimitRepository
+func NewInMemoryRateLimitRepository() *InMemoryRateLimitRepository {
+	return &InMemoryRateLimitRepository{
+		entries: make(map[string]*inMemoryRateLimitEntry),
+	}
+}
+
+// CheckRateLimit checks if a request exceeds the rate limit
+func (r *InMemoryRateLimitRepository) CheckRateLimit(ctx context.Context, key string, requests int, window time.Duration) (bool, int, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if len(r.entries) >= maxInMemoryRateLimitEntries {
+		r.sweepExpired(now)
+	}
+
+	entry, exists := r.entries[key]
+	if !exists || !now.Before(entry.expiresAt) {
+		// Start a new window
+		entry = &inMemoryRateLimitEntry{count: 1, expiresAt: now.Add(window)}
+		r.entries[key] = entry
+		return true, requests - 1, entry.expiresAt.Unix(), nil
+	}
+
+	if entry.count >= requests {
+		return false, 0, entry.expiresAt.Unix(), nil
+	}
+
+	entry.count++
+	return true, requests - entry.count, entry.expiresAt.Unix(), nil
+}
+
+// IncrementRateLimit increments the counter for a key
+func (r *InMemoryRateLimitRepository) IncrementRateLimit(ctx context.Context, key string, window time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	entry, exists := r.entries[key]
+	if !exists || !now.Before(entry.expiresAt) {
+		r.entries[key] = &inMemoryRateLimitEntry{count: 1, expiresAt: now.Add(window)}
+		return nil
+	}
+
+	entry.count++
+	return nil
+}
+
+// ResetRateLimit resets the counter for a key
+func (r *InMemoryRateLimitRepository) ResetRateLimit(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, key)
+	return nil
+}
+
+// sweepExpired removes entries whose window has passed. Caller must hold the lock.
+func (r *InMemoryRateLimitRepository) sweepExpired(now time.Time) {
+	for key, entry := range r.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(r.entries, key)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/aws-payment-gateway/internal/auth/domain"
 	"github.com/aws-payment-gateway/internal/common/db"
@@ -30,10 +31,11 @@ func (r *PostgreSQLAppRepository) Create(ctx context.Context, account *domain.Ac
 	now := time.Now()
 	account.CreatedAt = now
 	account.UpdatedAt = now
+	account.Version = 1
 
 	query := `
-		INSERT INTO accounts (id, name, status, webhook_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO accounts (id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
 	_, err := r.client.ExecContext(ctx, query,
@@ -43,6 +45,13 @@ func (r *PostgreSQLAppRepository) Create(ctx context.Context, account *domain.Ac
 		account.WebhookURL,
 		account.CreatedAt,
 		account.UpdatedAt,
+		account.Version,
+		account.OwnerID,
+		account.KeyPrefix,
+		account.KeysDisabled,
+		account.RequireClientCert,
+		textArray(account.ClientCertFingerprints),
+		textArray(account.AllowedPermissions),
 	)
 
 	if err != nil {
@@ -52,10 +61,83 @@ func (r *PostgreSQLAppRepository) Create(ctx context.Context, account *domain.Ac
 	return nil
 }
 
+// CreateMany inserts accounts in one transaction. Each insert runs under its own
+// savepoint so a failing row (e.g. a duplicate ID) is rolled back on its own and
+// the remaining rows are still committed together. Accounts owned by a failed
+// row are skipped rather than left owned by an account that does not exist.
+func (r *PostgreSQLAppRepository) CreateMany(ctx context.Context, accounts []*domain.Account) ([]error, error) {
+	tx, err := r.client.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO accounts (id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	now := time.Now()
+	rowErrs := make([]error, len(accounts))
+	failed := make(map[uuid.UUID]bool)
+	for i, account := range accounts {
+		if account.OwnerID != account.ID && failed[account.OwnerID] {
+			rowErrs[i] = fmt.Errorf("owner account failed to import")
+			failed[account.ID] = true
+			continue
+		}
+
+		// Keep imported creation times; default to now
+		if account.CreatedAt.IsZero() {
+			account.CreatedAt = now
+		}
+		account.UpdatedAt = now
+		account.Version = 1
+
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT import_row"); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		_, err := tx.ExecContext(ctx, query,
+			account.ID,
+			account.Name,
+			string(account.Status),
+			account.WebhookURL,
+			account.CreatedAt,
+			account.UpdatedAt,
+			account.Version,
+			account.OwnerID,
+			account.KeyPrefix,
+			account.KeysDisabled,
+			account.RequireClientCert,
+			textArray(account.ClientCertFingerprints),
+			textArray(account.AllowedPermissions),
+		)
+		if err != nil {
+			rowErrs[i] = fmt.Errorf("failed to create account: %w", err)
+			failed[account.ID] = true
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row"); err != nil {
+				return nil, fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rowErrs, nil
+}
+
 // GetByID retrieves an account by its ID
 func (r *PostgreSQLAppRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Account, error) {
 	query := `
-		SELECT id, name, status, webhook_url, created_at, updated_at
+		SELECT id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions
 		FROM accounts
 		WHERE id = $1
 	`
@@ -70,6 +152,13 @@ func (r *PostgreSQLAppRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 		&webhookURL,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.Version,
+		&account.OwnerID,
+		&account.KeyPrefix,
+		&account.KeysDisabled,
+		&account.RequireClientCert,
+		pq.Array(&account.ClientCertFingerprints),
+		pq.Array(&account.AllowedPermissions),
 	)
 
 	if err != nil {
@@ -90,7 +179,7 @@ func (r *PostgreSQLAppRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 // GetByName retrieves an account by its name
 func (r *PostgreSQLAppRepository) GetByName(ctx context.Context, name string) (*domain.Account, error) {
 	query := `
-		SELECT id, name, status, webhook_url, created_at, updated_at
+		SELECT id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions
 		FROM accounts
 		WHERE name = $1
 	`
@@ -105,6 +194,13 @@ func (r *PostgreSQLAppRepository) GetByName(ctx context.Context, name string) (*
 		&webhookURL,
 		&account.CreatedAt,
 		&account.UpdatedAt,
+		&account.Version,
+		&account.OwnerID,
+		&account.KeyPrefix,
+		&account.KeysDisabled,
+		&account.RequireClientCert,
+		pq.Array(&account.ClientCertFingerprints),
+		pq.Array(&account.AllowedPermissions),
 	)
 
 	if err != nil {
@@ -122,37 +218,44 @@ func (r *PostgreSQLAppRepository) GetByName(ctx context.Context, name string) (*
 	return &account, nil
 }
 
-// Update updates an existing account
+// Update updates an existing account.
+// Returns domain.ErrVersionConflict if the account changed since it was read.
 func (r *PostgreSQLAppRepository) Update(ctx context.Context, account *domain.Account) error {
 	// Update timestamp
 	account.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE accounts
-		SET name = $2, status = $3, webhook_url = $4, updated_at = $5
-		WHERE id = $1
+		SET name = $2, status = $3, webhook_url = $4, updated_at = $5, key_prefix = $7, keys_disabled = $8, require_client_cert = $9, client_cert_fingerprints = $10, allowed_permissions = $11, version = version + 1
+		WHERE id = $1 AND version = $6
 	`
 
-	_, err := r.client.ExecContext(ctx, query,
+	result, err := r.client.ExecContext(ctx, query,
 		account.ID,
 		account.Name,
 		string(account.Status),
 		account.WebhookURL,
 		account.UpdatedAt,
+		account.Version,
+		account.KeyPrefix,
+		account.KeysDisabled,
+		account.RequireClientCert,
+		textArray(account.ClientCertFingerprints),
+		textArray(account.AllowedPermissions),
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to update account: %w", err)
 	}
 
-	return nil
+	return applyVersionedUpdate(result, account)
 }
 
 // Delete soft deletes an account by setting status to deleted
 func (r *PostgreSQLAppRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `
 		UPDATE accounts
-		SET status = $2, updated_at = $3
+		SET status = $2, updated_at = $3, version = version + 1
 		WHERE id = $1
 	`
 
@@ -169,16 +272,65 @@ func (r *PostgreSQLAppRepository) Delete(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+// Purge hard deletes an account. Rows that reference it cascade, except ledger
+// postings, which restrict the delete so financial records are never lost.
+func (r *PostgreSQLAppRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM accounts WHERE id = $1`
+
+	if _, err := r.client.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to purge account: %w", err)
+	}
+
+	return nil
+}
+
+// HasLedgerPostings reports whether any of the account's ledger accounts has postings
+func (r *PostgreSQLAppRepository) HasLedgerPostings(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM postings p
+			JOIN ledger_accounts la ON la.id = p.ledger_account_id
+			WHERE la.account_id = $1
+		)
+	`
+
+	var exists bool
+	if err := r.client.QueryRowContext(ctx, query, id).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check ledger postings: %w", err)
+	}
+
+	return exists, nil
+}
+
 // List retrieves accounts with pagination
 func (r *PostgreSQLAppRepository) List(ctx context.Context, limit, offset int) ([]*domain.Account, error) {
 	query := `
-		SELECT id, name, status, webhook_url, created_at, updated_at
+		SELECT id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions
 		FROM accounts
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.client.QueryContext(ctx, query, limit, offset)
+	return r.queryAccounts(ctx, query, limit, offset)
+}
+
+// ListByOwner retrieves accounts owned by a tenant with pagination
+func (r *PostgreSQLAppRepository) ListByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*domain.Account, error) {
+	query := `
+		SELECT id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions
+		FROM accounts
+		WHERE owner_id = $3
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.queryAccounts(ctx, query, limit, offset, ownerID)
+}
+
+// queryAccounts runs an account query and scans every row
+func (r *PostgreSQLAppRepository) queryAccounts(ctx context.Context, query string, args ...interface{}) ([]*domain.Account, error) {
+	rows, err := r.client.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list accounts: %w", err)
 	}
@@ -197,6 +349,13 @@ func (r *PostgreSQLAppRepository) List(ctx context.Context, limit, offset int) (
 			&webhookURL,
 			&account.CreatedAt,
 			&account.UpdatedAt,
+			&account.Version,
+			&account.OwnerID,
+			&account.KeyPrefix,
+			&account.KeysDisabled,
+			&account.RequireClientCert,
+			pq.Array(&account.ClientCertFingerprints),
+			pq.Array(&account.AllowedPermissions),
 		)
 
 		if err != nil {
@@ -224,10 +383,11 @@ func (r *PostgreSQLAppRepository) CreateTx(ctx context.Context, tx *sql.Tx, acco
 	now := time.Now()
 	account.CreatedAt = now
 	account.UpdatedAt = now
+	account.Version = 1
 
 	query := `
-		INSERT INTO accounts (id, name, status, webhook_url, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO accounts (id, name, status, webhook_url, created_at, updated_at, version, owner_id, key_prefix, keys_disabled, require_client_cert, client_cert_fingerprints, allowed_permissions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
 	_, err := tx.ExecContext(ctx, query,
@@ -237,6 +397,13 @@ func (r *PostgreSQLAppRepository) CreateTx(ctx context.Context, tx *sql.Tx, acco
 		account.WebhookURL,
 		account.CreatedAt,
 		account.UpdatedAt,
+		account.Version,
+		account.OwnerID,
+		account.KeyPrefix,
+		account.KeysDisabled,
+		account.RequireClientCert,
+		textArray(account.ClientCertFingerprints),
+		textArray(account.AllowedPermissions),
 	)
 
 	if err != nil {
@@ -246,28 +413,59 @@ func (r *PostgreSQLAppRepository) CreateTx(ctx context.Context, tx *sql.Tx, acco
 	return nil
 }
 
-// UpdateTx updates an existing account within a transaction
+// UpdateTx updates an existing account within a transaction.
+// Returns domain.ErrVersionConflict if the account changed since it was read.
 func (r *PostgreSQLAppRepository) UpdateTx(ctx context.Context, tx *sql.Tx, account *domain.Account) error {
 	// Update timestamp
 	account.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE accounts
-		SET name = $2, status = $3, webhook_url = $4, updated_at = $5
-		WHERE id = $1
+		SET name = $2, status = $3, webhook_url = $4, updated_at = $5, key_prefix = $7, keys_disabled = $8, require_client_cert = $9, client_cert_fingerprints = $10, allowed_permissions = $11, version = version + 1
+		WHERE id = $1 AND version = $6
 	`
 
-	_, err := tx.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		account.ID,
 		account.Name,
 		string(account.Status),
 		account.WebhookURL,
 		account.UpdatedAt,
+		account.Version,
+		account.KeyPrefix,
+		account.KeysDisabled,
+		account.RequireClientCert,
+		textArray(account.ClientCertFingerprints),
+		textArray(account.AllowedPermissions),
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to update account in transaction: %w", err)
 	}
 
+	return applyVersionedUpdate(result, account)
+}
+
+// textArray binds a slice to a NOT NULL TEXT[] column; pq.Array sends a nil
+// slice as NULL, so nil is sent as an empty array instead
+func textArray(values []string) interface{} {
+	if values == nil {
+		values = []string{}
+	}
+	return pq.Array(values)
+}
+
+// applyVersionedUpdate checks that a version-conditioned UPDATE matched a row and
+// advances the in-memory version to the stored one
+func applyVersionedUpdate(result sql.Result, account *domain.Account) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check updated rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrVersionConflict
+	}
+
+	account.Version++
 	return nil
 }
@@ -0,0 +1,86 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+)
+
+// ChallengeVerifier verifies a pre-registration challenge token (CAPTCHA, proof-of-work, ...)
+type ChallengeVerifier interface {
+	// Verify returns domain.ErrChallengeRequired when no token was supplied and
+	// domain.ErrChallengeFailed when the token is invalid
+	Verify(ctx context.Context, subject, token string) error
+}
+
+// NoopChallengeVerifier accepts every request
+type NoopChallengeVerifier struct{}
+
+// Verify always succeeds
+func (NoopChallengeVerifier) Verify(ctx context.Context, subject, token string) error {
+	return nil
+}
+
+// ProofOfWorkVerifier verifies hashcash-style tokens of the form "<unix_timestamp>:<nonce>".
+// A token is valid when SHA-256("<subject>:<unix_timestamp>:<nonce>") has at least
+// Difficulty leading zero bits and the timestamp is within MaxAge of now.
+type ProofOfWorkVerifier struct {
+	Difficulty int
+	MaxAge     time.Duration
+}
+
+// NewProofOfWorkVerifier creates a new ProofOfWorkVerifier
+func NewProofOfWorkVerifier(difficulty int, maxAge time.Duration) *ProofOfWorkVerifier {
+	return &ProofOfWorkVerifier{
+		Difficulty: difficulty,
+		MaxAge:     maxAge,
+	}
+}
+
+// Verify checks the proof-of-work token for the subject
+func (v *ProofOfWorkVerifier) Verify(ctx context.Context, subject, token string) error {
+	if token == "" {
+		return domain.ErrChallengeRequired
+	}
+
+	parts := strings.SplitN(token, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return fmt.Errorf("%w: malformed token", domain.ErrChallengeFailed)
+	}
+
+	timestamp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", domain.ErrChallengeFailed)
+	}
+
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > v.MaxAge || age < -v.MaxAge {
+		return fmt.Errorf("%w: token is stale", domain.ErrChallengeFailed)
+	}
+
+	hash := sha256.Sum256([]byte(subject + ":" + token))
+	if leadingZeroBits(hash[:]) < v.Difficulty {
+		return fmt.Errorf("%w: insufficient work", domain.ErrChallengeFailed)
+	}
+
+	return nil
+}
+
+// leadingZeroBits counts the leading zero bits of a byte slice
+func leadingZeroBits(b []byte) int {
+	count := 0
+	for _, by := range b {
+		if by == 0 {
+			count += 8
+			continue
+		}
+		return count + bits.LeadingZeros8(by)
+	}
+	return count
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// CheckAccessInput represents the input for checking whether a key may use a permission
+type CheckAccessInput struct {
+	APIKeyID   uuid.UUID `json:"api_key_id" validate:"required"`
+	Permission string    `json:"permission" validate:"required"`
+	// AccountID, when set, reports keys belonging to other accounts as not found
+	AccountID uuid.UUID `json:"account_id"`
+}
+
+// CheckAccessOutput represents the outcome of an access check
+type CheckAccessOutput struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// CheckAccess handles the business logic for explaining whether a key can perform an operation
+type CheckAccess struct {
+	apiKeyRepo repository.ApiKeyRepository
+	appRepo    repository.AppRepository
+	policy     domain.AccessPolicy
+}
+
+// NewCheckAccess creates a new CheckAccess use case
+func NewCheckAccess(apiKeyRepo repository.ApiKeyRepository, appRepo repository.AppRepository, policy domain.AccessPolicy) *CheckAccess {
+	return &CheckAccess{
+		apiKeyRepo: apiKeyRepo,
+		appRepo:    appRepo,
+		policy:     policy,
+	}
+}
+
+// Execute loads the key and its account and applies the access policy
+func (uc *CheckAccess) Execute(ctx context.Context, input CheckAccessInput) (*CheckAccessOutput, error) {
+	// Validate input
+	if input.APIKeyID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: api_key_id is required")
+	}
+	permission := uc.CanonicalPermission(input.Permission)
+	if !domain.IsValidPermission(permission) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPermission, input.Permission)
+	}
+
+	apiKey, err := uc.apiKeyRepo.GetByID(ctx, input.APIKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil || (input.AccountID != uuid.Nil && apiKey.AccountID != input.AccountID) {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	account, err := uc.appRepo.GetByID(ctx, apiKey.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	decision := uc.policy.Check(apiKey, account, permission)
+
+	return &CheckAccessOutput{
+		Allowed: decision.Allowed,
+		Reason:  string(decision.Reason),
+	}, nil
+}
+
+// CanonicalPermission resolves a configured permission alias to its canonical name
+func (uc *CheckAccess) CanonicalPermission(permission string) string {
+	return uc.policy.PermissionAliases.Canonical(permission)
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,16 +14,21 @@ import (
 
 // CheckIdempotencyInput represents the input for checking idempotency
 type CheckIdempotencyInput struct {
-	IdempotencyKey string `json:"idempotency_key" validate:"required"`
-	RequestHash    string `json:"request_hash" validate:"required"`
+	IdempotencyKey string    `json:"idempotency_key" validate:"required"`
+	RequestHash    string    `json:"request_hash" validate:"required"`
+	AccountID      uuid.UUID `json:"account_id,omitempty"` // Optional: scopes the lookup to an account
 }
 
 // CheckIdempotencyOutput represents the output of checking idempotency
 type CheckIdempotencyOutput struct {
-	Exists    bool       `json:"exists"`
-	Status    string     `json:"status,omitempty"`
-	Response  string     `json:"response,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Exists   bool   `json:"exists"`
+	Status   string `json:"status,omitempty"`
+	Response string `json:"response,omitempty"`
+	// ResponseTruncated means the original response was too large to store, so
+	// Response is empty and the cached result cannot be replayed
+	ResponseTruncated bool       `json:"response_truncated,omitempty"`
+	ResponseStatus    int        `json:"response_status,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
 }
 
 // CheckIdempotency handles checking if an idempotency key exists and its status
@@ -50,7 +56,8 @@ func (uc *CheckIdempotency) Execute(ctx context.Context, input CheckIdempotencyI
 		return nil, fmt.Errorf("failed to check idempotency key: %w", err)
 	}
 
-	if key == nil {
+	// Keys belonging to another account are never visible to the caller
+	if key == nil || (input.AccountID != uuid.Nil && key.AccountID != input.AccountID) {
 		// No existing key, this is a new request
 		return &CheckIdempotencyOutput{
 			Exists: false,
@@ -67,12 +74,18 @@ func (uc *CheckIdempotency) Execute(ctx context.Context, input CheckIdempotencyI
 
 	// Return the key status and response if completed
 	if key.Status == domain.IdempotencyKeyStatusCompleted {
-		return &CheckIdempotencyOutput{
-			Exists:    true,
-			Status:    string(key.Status),
-			Response:  key.Response,
-			CreatedAt: &key.CreatedAt,
-		}, nil
+		output := &CheckIdempotencyOutput{
+			Exists:            true,
+			Status:            string(key.Status),
+			Response:          key.Response,
+			ResponseTruncated: key.ResponseTruncated,
+			ResponseStatus:    key.ResponseStatus,
+			CreatedAt:         &key.CreatedAt,
+		}
+		if key.ResponseTruncated {
+			output.Response = ""
+		}
+		return output, nil
 	}
 
 	// Key exists but is still pending
@@ -109,15 +122,24 @@ type CreateIdempotencyOutput struct {
 	ExpiresAt      time.Time `json:"expires_at"`
 }
 
+// CreateIdempotencyConfig defines configurable behaviour for creating idempotency keys
+type CreateIdempotencyConfig struct {
+	// MaxKeysPerAccount caps the unexpired idempotency keys an account may hold.
+	// Zero disables the cap.
+	MaxKeysPerAccount int
+}
+
 // CreateIdempotency handles creating new idempotency keys
 type CreateIdempotency struct {
-	idempotencyRepo repository.IdempotencyKeyRepository
+	idempotencyRepo   repository.IdempotencyKeyRepository
+	maxKeysPerAccount int
 }
 
 // NewCreateIdempotency creates a new CreateIdempotency use case
-func NewCreateIdempotency(idempotencyRepo repository.IdempotencyKeyRepository) *CreateIdempotency {
+func NewCreateIdempotency(idempotencyRepo repository.IdempotencyKeyRepository, config CreateIdempotencyConfig) *CreateIdempotency {
 	return &CreateIdempotency{
-		idempotencyRepo: idempotencyRepo,
+		idempotencyRepo:   idempotencyRepo,
+		maxKeysPerAccount: config.MaxKeysPerAccount,
 	}
 }
 
@@ -131,8 +153,10 @@ func (uc *CreateIdempotency) Execute(ctx context.Context, input CreateIdempotenc
 	// Create idempotency key
 	now := time.Now()
 	accountID := input.AccountID
+	maxKeys := uc.maxKeysPerAccount
 	if accountID == uuid.Nil {
 		accountID = uuid.New() // Fallback for testing/unauthenticated contexts
+		maxKeys = 0
 	}
 
 	key := &domain.IdempotencyKey{
@@ -145,7 +169,10 @@ func (uc *CreateIdempotency) Execute(ctx context.Context, input CreateIdempotenc
 		ExpiresAt:   now.Add(24 * time.Hour), // 24-hour TTL
 	}
 
-	err := uc.idempotencyRepo.Create(ctx, key)
+	err := uc.idempotencyRepo.CreateWithinLimit(ctx, key, maxKeys)
+	if errors.Is(err, domain.ErrIdempotencyKeyInProgress) || errors.Is(err, domain.ErrIdempotencyKeyLimitExceeded) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create idempotency key: %w", err)
 	}
@@ -161,6 +188,7 @@ func (uc *CreateIdempotency) Execute(ctx context.Context, input CreateIdempotenc
 type CompleteIdempotencyInput struct {
 	IdempotencyKey string `json:"idempotency_key" validate:"required"`
 	Response       string `json:"response" validate:"required"`
+	ResponseStatus int    `json:"response_status,omitempty"` // HTTP status of the stored response
 }
 
 // CompleteIdempotencyOutput represents the output of completing idempotency
@@ -168,17 +196,38 @@ type CompleteIdempotencyOutput struct {
 	IdempotencyKey string    `json:"idempotency_key"`
 	Status         string    `json:"status"`
 	CompletedAt    time.Time `json:"completed_at"`
+	// ResponseTruncated reports that the response exceeded the size limit and was not stored
+	ResponseTruncated bool `json:"response_truncated,omitempty"`
+}
+
+// DefaultMaxIdempotencyResponseBytes keeps stored responses well under
+// DynamoDB's 400KB item limit, leaving room for the rest of the item
+const DefaultMaxIdempotencyResponseBytes = 350 * 1024
+
+// CompleteIdempotencyConfig defines configurable behaviour for completing idempotency keys
+type CompleteIdempotencyConfig struct {
+	// MaxResponseBytes is the largest response stored with a key. Larger responses
+	// are replaced with domain.TruncatedResponseMarker. Defaults to
+	// DefaultMaxIdempotencyResponseBytes.
+	MaxResponseBytes int
 }
 
 // CompleteIdempotency handles completing idempotency keys
 type CompleteIdempotency struct {
-	idempotencyRepo repository.IdempotencyKeyRepository
+	idempotencyRepo  repository.IdempotencyKeyRepository
+	maxResponseBytes int
 }
 
 // NewCompleteIdempotency creates a new CompleteIdempotency use case
-func NewCompleteIdempotency(idempotencyRepo repository.IdempotencyKeyRepository) *CompleteIdempotency {
+func NewCompleteIdempotency(idempotencyRepo repository.IdempotencyKeyRepository, config CompleteIdempotencyConfig) *CompleteIdempotency {
+	maxResponseBytes := config.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = DefaultMaxIdempotencyResponseBytes
+	}
+
 	return &CompleteIdempotency{
-		idempotencyRepo: idempotencyRepo,
+		idempotencyRepo:  idempotencyRepo,
+		maxResponseBytes: maxResponseBytes,
 	}
 }
 
@@ -213,6 +262,14 @@ func (uc *CompleteIdempotency) Execute(ctx context.Context, input CompleteIdempo
 	now := time.Now()
 	key.Status = domain.IdempotencyKeyStatusCompleted
 	key.Response = input.Response
+	key.ResponseStatus = input.ResponseStatus
+	key.ResponseTruncated = false
+
+	// Store a marker rather than failing the write on oversized items
+	if len(input.Response) > uc.maxResponseBytes {
+		key.Response = domain.TruncatedResponseMarker
+		key.ResponseTruncated = true
+	}
 
 	err = uc.idempotencyRepo.Update(ctx, key)
 	if err != nil {
@@ -220,9 +277,10 @@ func (uc *CompleteIdempotency) Execute(ctx context.Context, input CompleteIdempo
 	}
 
 	return &CompleteIdempotencyOutput{
-		IdempotencyKey: key.ID.String(),
-		Status:         string(key.Status),
-		CompletedAt:    now,
+		IdempotencyKey:    key.ID.String(),
+		Status:            string(key.Status),
+		CompletedAt:       now,
+		ResponseTruncated: key.ResponseTruncated,
 	}, nil
 }
 
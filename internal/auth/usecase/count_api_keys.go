@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// CountApiKeysInput represents the input for counting an account's live API keys
+type CountApiKeysInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+}
+
+// CountApiKeysOutput represents an account's live API key count
+type CountApiKeysOutput struct {
+	AccountID  uuid.UUID `json:"account_id"`
+	ActiveKeys int       `json:"active_keys"`
+}
+
+// CountApiKeys handles the business logic for reading an account's key counter
+type CountApiKeys struct {
+	accountRepo repository.AppRepository
+	apiKeyRepo  repository.ApiKeyRepository
+}
+
+// NewCountApiKeys creates a new CountApiKeys use case
+func NewCountApiKeys(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository) *CountApiKeys {
+	return &CountApiKeys{
+		accountRepo: accountRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+// Execute returns the number of the account's keys that have not been revoked,
+// read from the counter maintained on issuance and revocation
+func (uc *CountApiKeys) Execute(ctx context.Context, input CountApiKeysInput) (*CountApiKeysOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+
+	// Verify account exists and is active
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || !account.IsValid() {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	count, err := uc.apiKeyRepo.CountActiveKeys(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+
+	return &CountApiKeysOutput{
+		AccountID:  input.AccountID,
+		ActiveKeys: count,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/repository"
+)
+
+// DeactivateExpiredKeysOutput represents the output of an expired-key sweep
+type DeactivateExpiredKeysOutput struct {
+	Deactivated int `json:"deactivated"`
+	Failed      int `json:"failed"`
+}
+
+// DeactivateExpiredKeys marks expired keys that still carry active status as
+// inactive, so status-based listings and counts match what validation does
+type DeactivateExpiredKeys struct {
+	apiKeyRepo  repository.ApiKeyRepository
+	gracePeriod time.Duration
+}
+
+// NewDeactivateExpiredKeys creates a new DeactivateExpiredKeys use case.
+// Keys still within gracePeriod of their expiry are left active.
+func NewDeactivateExpiredKeys(apiKeyRepo repository.ApiKeyRepository, gracePeriod time.Duration) *DeactivateExpiredKeys {
+	return &DeactivateExpiredKeys{
+		apiKeyRepo:  apiKeyRepo,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// Execute finds active keys past their expiry and grace period and deactivates them
+func (uc *DeactivateExpiredKeys) Execute(ctx context.Context) (*DeactivateExpiredKeysOutput, error) {
+	apiKeys, err := uc.apiKeyRepo.ListExpiringBefore(ctx, time.Now().Add(-uc.gracePeriod))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired API keys: %w", err)
+	}
+
+	output := &DeactivateExpiredKeysOutput{}
+	for _, apiKey := range apiKeys {
+		if apiKey.InGracePeriod(uc.gracePeriod) {
+			continue
+		}
+		if err := apiKey.Expire(); err != nil {
+			continue // Not expired after all
+		}
+
+		// The key counter already ignores expired keys once recounted, so only
+		// the status changes. A concurrent update is picked up on the next run.
+		if err := uc.apiKeyRepo.Update(ctx, apiKey); err != nil {
+			log.Printf("Failed to deactivate expired API key %s: %v", apiKey.ID, err)
+			output.Failed++
+			continue
+		}
+
+		output.Deactivated++
+	}
+
+	return output, nil
+}
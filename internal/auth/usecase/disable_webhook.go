@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// DisableFailingWebhookInput identifies a webhook whose deliveries keep failing
+type DisableFailingWebhookInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	// WebhookURL is the URL deliveries failed for
+	WebhookURL string `json:"webhook_url" validate:"required"`
+	// Failures is the number of consecutive failed deliveries
+	Failures int `json:"failures"`
+}
+
+// DisableFailingWebhookOutput reports whether the webhook was cleared
+type DisableFailingWebhookOutput struct {
+	Disabled bool `json:"disabled"`
+}
+
+// DisableFailingWebhook handles clearing an account's webhook URL after its
+// deliveries keep failing, so the account stops being retried forever
+type DisableFailingWebhook struct {
+	accountRepo repository.AppRepository
+	auditLogger audit.AuditLoggerInterface
+}
+
+// NewDisableFailingWebhook creates a new DisableFailingWebhook use case
+func NewDisableFailingWebhook(accountRepo repository.AppRepository, auditLogger audit.AuditLoggerInterface) *DisableFailingWebhook {
+	return &DisableFailingWebhook{
+		accountRepo: accountRepo,
+		auditLogger: auditLogger,
+	}
+}
+
+// Execute clears the webhook and records a webhook_disabled audit event. Nothing
+// changes if the account has meanwhile set a different URL.
+func (uc *DisableFailingWebhook) Execute(ctx context.Context, input DisableFailingWebhookInput) (*DisableFailingWebhookOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || account.WebhookURL == nil || *account.WebhookURL != input.WebhookURL {
+		return &DisableFailingWebhookOutput{}, nil
+	}
+
+	account.WebhookURL = nil
+	if err := uc.accountRepo.Update(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+
+	// Keep the old URL so it can be restored once the endpoint is fixed
+	uc.auditLogger.LogEvent(ctx, &audit.AuditEvent{
+		EventType: audit.EventTypeWebhookDisabled,
+		AccountID: &input.AccountID,
+		Success:   true,
+		Details: map[string]string{
+			"previous_webhook_url": input.WebhookURL,
+			"consecutive_failures": strconv.Itoa(input.Failures),
+		},
+	})
+
+	return &DisableFailingWebhookOutput{Disabled: true}, nil
+}
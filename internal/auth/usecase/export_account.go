@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// ExportAccountInput represents the input for exporting an account's key inventory
+type ExportAccountInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+}
+
+// ExportAccountOutput represents the exported account and its API key metadata
+type ExportAccountOutput struct {
+	Account    *domain.Account  `json:"account"`
+	APIKeys    []*domain.ApiKey `json:"api_keys"`
+	ExportedAt time.Time        `json:"exported_at"`
+}
+
+// ExportAccount handles the business logic for exporting an account's API key inventory
+type ExportAccount struct {
+	accountRepo repository.AppRepository
+	apiKeyRepo  repository.ApiKeyRepository
+}
+
+// NewExportAccount creates a new ExportAccount use case
+func NewExportAccount(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository) *ExportAccount {
+	return &ExportAccount{
+		accountRepo: accountRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+// Execute collects the account details and all of its API keys
+func (uc *ExportAccount) Execute(ctx context.Context, input ExportAccountInput) (*ExportAccountOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+
+	// Verify account exists
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	// GetByAccountID pages through the store internally
+	apiKeys, err := uc.apiKeyRepo.GetByAccountID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys: %w", err)
+	}
+
+	// Create output
+	output := &ExportAccountOutput{
+		Account:    account,
+		APIKeys:    apiKeys,
+		ExportedAt: time.Now(),
+	}
+
+	return output, nil
+}
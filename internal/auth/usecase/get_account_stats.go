@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// statsWindow is the period "recent" authentication counts cover
+const statsWindow = 24 * time.Hour
+
+// GetAccountStatsInput represents the input for getting an account's usage stats
+type GetAccountStatsInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+}
+
+// GetAccountStatsOutput represents aggregate usage stats for an account
+type GetAccountStatsOutput struct {
+	TotalKeys         int        `json:"total_keys"`
+	ActiveKeys        int        `json:"active_keys"`
+	LastAuthenticated *time.Time `json:"last_authenticated_at,omitempty"`
+	RequestsLast24h   int        `json:"requests_last_24h"`
+	GeneratedAt       time.Time  `json:"generated_at"`
+}
+
+// accountStatsEntry is a cached stats result
+type accountStatsEntry struct {
+	output    *GetAccountStatsOutput
+	expiresAt time.Time
+}
+
+// GetAccountStats handles the business logic for assembling an account's usage stats.
+// Results are cached per account for cacheTTL since the stats are read-heavy and
+// counting authentications queries the audit log.
+type GetAccountStats struct {
+	accountRepo repository.AppRepository
+	apiKeyRepo  repository.ApiKeyRepository
+	authCounter audit.AuthenticationCounter
+	cacheTTL    time.Duration
+	mu          sync.Mutex
+	cache       map[uuid.UUID]accountStatsEntry
+}
+
+// NewGetAccountStats creates a new GetAccountStats use case. A zero cacheTTL disables caching.
+func NewGetAccountStats(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository, authCounter audit.AuthenticationCounter, cacheTTL time.Duration) *GetAccountStats {
+	return &GetAccountStats{
+		accountRepo: accountRepo,
+		apiKeyRepo:  apiKeyRepo,
+		authCounter: authCounter,
+		cacheTTL:    cacheTTL,
+		cache:       make(map[uuid.UUID]accountStatsEntry),
+	}
+}
+
+// Execute returns the account's key counts, last authentication time and recent request count
+func (uc *GetAccountStats) Execute(ctx context.Context, input GetAccountStatsInput) (*GetAccountStatsOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+
+	// Serve from cache if fresh
+	now := time.Now()
+	if output := uc.cached(input.AccountID, now); output != nil {
+		return output, nil
+	}
+
+	// Verify account exists
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	apiKeys, err := uc.apiKeyRepo.GetByAccountID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys: %w", err)
+	}
+
+	output := &GetAccountStatsOutput{
+		TotalKeys:   len(apiKeys),
+		GeneratedAt: now,
+	}
+
+	// Count active keys and find the most recent use across all keys
+	for _, apiKey := range apiKeys {
+		if apiKey.Status == domain.ApiKeyStatusActive && !apiKey.IsExpired() {
+			output.ActiveKeys++
+		}
+		if apiKey.LastUsedAt != nil && (output.LastAuthenticated == nil || apiKey.LastUsedAt.After(*output.LastAuthenticated)) {
+			output.LastAuthenticated = apiKey.LastUsedAt
+		}
+	}
+
+	output.RequestsLast24h, err = uc.authCounter.CountAuthentications(ctx, input.AccountID, now.Add(-statsWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count authentications: %w", err)
+	}
+
+	uc.store(input.AccountID, output, now)
+
+	return output, nil
+}
+
+// cached returns the cached stats for an account if they have not expired
+func (uc *GetAccountStats) cached(accountID uuid.UUID, now time.Time) *GetAccountStatsOutput {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	entry, ok := uc.cache[accountID]
+	if !ok || now.After(entry.expiresAt) {
+		return nil
+	}
+	return entry.output
+}
+
+// store caches stats for an account, evicting expired entries
+func (uc *GetAccountStats) store(accountID uuid.UUID, output *GetAccountStatsOutput, now time.Time) {
+	if uc.cacheTTL <= 0 {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for id, entry := range uc.cache {
+		if now.After(entry.expiresAt) {
+			delete(uc.cache, id)
+		}
+	}
+	uc.cache[accountID] = accountStatsEntry{output: output, expiresAt: now.Add(uc.cacheTTL)}
+}
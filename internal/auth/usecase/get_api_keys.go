@@ -46,12 +46,8 @@ func (uc *GetAPIKeys) Execute(ctx context.Context, input GetAPIKeysInput) (*GetA
 	}
 
 	// Verify account exists and is active
-	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get account: %w", err)
-	}
-	if account == nil || !account.IsValid() {
-		return nil, fmt.Errorf("account not found or inactive")
+	if err := uc.CheckAccount(ctx, input.AccountID); err != nil {
+		return nil, err
 	}
 
 	// Get API keys for the account
@@ -77,6 +73,39 @@ func (uc *GetAPIKeys) Execute(ctx context.Context, input GetAPIKeysInput) (*GetA
 	return output, nil
 }
 
+// streamPageSize is how many keys Stream reads from the store at a time
+const streamPageSize = 100
+
+// Stream passes every API key of an account to fn, reading them from the store a
+// page at a time instead of loading the whole list. It does not check the account;
+// call CheckAccount first, before any part of the response has been written.
+func (uc *GetAPIKeys) Stream(ctx context.Context, accountID uuid.UUID, fn func(*domain.ApiKey) error) error {
+	if accountID == uuid.Nil {
+		return fmt.Errorf("invalid input: account_id is required")
+	}
+
+	return uc.apiKeyRepo.ForEachByAccountID(ctx, accountID, streamPageSize, func(apiKeys []*domain.ApiKey) error {
+		for _, apiKey := range apiKeys {
+			if err := fn(apiKey); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CheckAccount verifies the account exists and is active
+func (uc *GetAPIKeys) CheckAccount(ctx context.Context, accountID uuid.UUID) error {
+	account, err := uc.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || !account.IsValid() {
+		return fmt.Errorf("account not found or inactive")
+	}
+	return nil
+}
+
 // validateInput validates the get API keys input
 func (uc *GetAPIKeys) validateInput(input GetAPIKeysInput) error {
 	if input.AccountID == uuid.Nil {
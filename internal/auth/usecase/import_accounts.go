@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// MaxImportAccounts is the largest number of accounts accepted in one import
+const MaxImportAccounts = 500
+
+// ImportAccountRow is a single account to import. IDs are kept so references
+// held by other systems stay valid.
+type ImportAccountRow struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status,omitempty"`
+	WebhookURL *string   `json:"webhook_url,omitempty"`
+	// OwnerID defaults to ID (a root account); otherwise it must be an existing
+	// account or another account in the same import
+	OwnerID *uuid.UUID `json:"owner_id,omitempty"`
+	// CreatedAt keeps the original creation time; defaults to now
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ImportAccountsInput represents the input for importing accounts
+type ImportAccountsInput struct {
+	Accounts []ImportAccountRow `json:"accounts" validate:"required"`
+}
+
+// ImportAccountResult reports the outcome for one imported row
+type ImportAccountResult struct {
+	Index     int       `json:"index"`
+	AccountID uuid.UUID `json:"account_id"`
+	Name      string    `json:"name"`
+	Created   bool      `json:"created"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ImportAccountsOutput represents the output of an account import
+type ImportAccountsOutput struct {
+	Results []ImportAccountResult `json:"results"`
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+}
+
+// ImportAccounts handles the business logic for bulk-importing accounts
+type ImportAccounts struct {
+	appRepo       repository.AppRepository
+	webhookPolicy domain.WebhookURLPolicy
+	namePolicy    domain.AccountNamePolicy
+}
+
+// NewImportAccounts creates a new ImportAccounts use case
+func NewImportAccounts(appRepo repository.AppRepository, webhookPolicy domain.WebhookURLPolicy, namePolicy domain.AccountNamePolicy) *ImportAccounts {
+	return &ImportAccounts{
+		appRepo:       appRepo,
+		webhookPolicy: webhookPolicy,
+		namePolicy:    namePolicy,
+	}
+}
+
+// Execute validates every row, then inserts the valid ones in one transaction.
+// Invalid rows and rows rejected by the database are reported without failing the import.
+func (uc *ImportAccounts) Execute(ctx context.Context, input ImportAccountsInput) (*ImportAccountsOutput, error) {
+	// Validate input
+	if len(input.Accounts) == 0 {
+		return nil, fmt.Errorf("invalid input: at least one account is required")
+	}
+	if len(input.Accounts) > MaxImportAccounts {
+		return nil, fmt.Errorf("invalid input: at most %d accounts can be imported at once, got %d", MaxImportAccounts, len(input.Accounts))
+	}
+
+	// IDs in this import, so owners can reference accounts imported alongside them
+	batchIDs := make(map[uuid.UUID]bool, len(input.Accounts))
+	for _, row := range input.Accounts {
+		batchIDs[row.ID] = true
+	}
+
+	results := make([]ImportAccountResult, len(input.Accounts))
+	seenIDs := make(map[uuid.UUID]bool, len(input.Accounts))
+	seenNames := make(map[string]bool, len(input.Accounts))
+	var accounts []*domain.Account
+	var accountRows []int
+
+	for i, row := range input.Accounts {
+		results[i] = ImportAccountResult{Index: i, AccountID: row.ID, Name: row.Name}
+
+		account, err := uc.buildAccount(ctx, row, batchIDs, seenIDs, seenNames)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		accounts = append(accounts, account)
+		accountRows = append(accountRows, i)
+	}
+
+	// Drop rows whose owner is in this import but failed validation
+	validIDs := make(map[uuid.UUID]bool, len(accounts))
+	for _, account := range accounts {
+		validIDs[account.ID] = true
+	}
+	kept, keptRows := accounts[:0], accountRows[:0]
+	for j, account := range accounts {
+		if account.OwnerID != account.ID && batchIDs[account.OwnerID] && !validIDs[account.OwnerID] {
+			results[accountRows[j]].Error = "owner account failed to import"
+			continue
+		}
+		kept = append(kept, account)
+		keptRows = append(keptRows, accountRows[j])
+	}
+	accounts, accountRows = kept, keptRows
+
+	// Owners go in before the accounts they own, so the repository can skip the
+	// accounts of an owner whose insert fails
+	accounts, accountRows = ownersFirst(accounts, accountRows, results)
+
+	// Insert the valid rows
+	if len(accounts) > 0 {
+		rowErrs, err := uc.appRepo.CreateMany(ctx, accounts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import accounts: %w", err)
+		}
+		for j, rowErr := range rowErrs {
+			i := accountRows[j]
+			if rowErr != nil {
+				results[i].Error = rowErr.Error()
+				continue
+			}
+			results[i].Created = true
+		}
+	}
+
+	output := &ImportAccountsOutput{Results: results}
+	for _, result := range results {
+		if result.Created {
+			output.Created++
+		} else {
+			output.Failed++
+		}
+	}
+
+	return output, nil
+}
+
+// ownersFirst orders accounts so every account owned by another account of the
+// batch comes after its owner, keeping the input order otherwise. Accounts whose
+// owners form a cycle can never be placed; they are dropped and reported in results.
+func ownersFirst(accounts []*domain.Account, accountRows []int, results []ImportAccountResult) ([]*domain.Account, []int) {
+	inBatch := make(map[uuid.UUID]bool, len(accounts))
+	for _, account := range accounts {
+		inBatch[account.ID] = true
+	}
+
+	placed := make(map[uuid.UUID]bool, len(accounts))
+	ordered := make([]*domain.Account, 0, len(accounts))
+	orderedRows := make([]int, 0, len(accounts))
+	remaining := make([]int, len(accounts))
+	for j := range accounts {
+		remaining[j] = j
+	}
+
+	for len(remaining) > 0 {
+		var next []int
+		for _, j := range remaining {
+			account := accounts[j]
+			if account.OwnerID == account.ID || !inBatch[account.OwnerID] || placed[account.OwnerID] {
+				placed[account.ID] = true
+				ordered = append(ordered, account)
+				orderedRows = append(orderedRows, accountRows[j])
+				continue
+			}
+			next = append(next, j)
+		}
+		if len(next) == len(remaining) {
+			for _, j := range next {
+				results[accountRows[j]].Error = "owner accounts form a cycle"
+			}
+			break
+		}
+		remaining = next
+	}
+
+	return ordered, orderedRows
+}
+
+// buildAccount validates a row against the rest of the import and existing accounts
+func (uc *ImportAccounts) buildAccount(
+	ctx context.Context,
+	row ImportAccountRow,
+	batchIDs map[uuid.UUID]bool,
+	seenIDs map[uuid.UUID]bool,
+	seenNames map[string]bool,
+) (*domain.Account, error) {
+	if row.ID == uuid.Nil {
+		return nil, fmt.Errorf("id is required")
+	}
+	if len(row.Name) < 3 || len(row.Name) > 100 {
+		return nil, fmt.Errorf("name must be between 3 and 100 characters")
+	}
+	if err := uc.namePolicy.Check(row.Name); err != nil {
+		return nil, err
+	}
+
+	status := domain.AccountStatusActive
+	if row.Status != "" {
+		status = domain.AccountStatus(row.Status)
+	}
+	if status != domain.AccountStatusActive && status != domain.AccountStatusSuspended {
+		return nil, fmt.Errorf("status must be '%s' or '%s'", domain.AccountStatusActive, domain.AccountStatusSuspended)
+	}
+
+	if row.WebhookURL != nil {
+		if err := uc.webhookPolicy.Validate(*row.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reject duplicates within the import
+	if seenIDs[row.ID] {
+		return nil, fmt.Errorf("duplicate id in import")
+	}
+	if seenNames[row.Name] {
+		return nil, fmt.Errorf("duplicate name in import")
+	}
+	seenIDs[row.ID] = true
+	seenNames[row.Name] = true
+
+	// Reject duplicates of existing accounts
+	existing, err := uc.appRepo.GetByID(ctx, row.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check account ID: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("account with this id already exists")
+	}
+	existing, err = uc.appRepo.GetByName(ctx, row.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check account name: %w", err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("account with this name already exists")
+	}
+
+	// Resolve the owning tenant
+	ownerID := row.ID
+	if row.OwnerID != nil && *row.OwnerID != row.ID {
+		ownerID = *row.OwnerID
+		if !batchIDs[ownerID] {
+			owner, err := uc.appRepo.GetByID(ctx, ownerID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get owner account: %w", err)
+			}
+			if owner == nil {
+				return nil, fmt.Errorf("owner account not found")
+			}
+		}
+	}
+
+	account := &domain.Account{
+		ID:         row.ID,
+		Name:       row.Name,
+		Status:     status,
+		WebhookURL: row.WebhookURL,
+		OwnerID:    ownerID,
+	}
+	if row.CreatedAt != nil {
+		account.CreatedAt = *row.CreatedAt
+	}
+
+	return account, nil
+}
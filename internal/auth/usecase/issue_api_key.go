@@ -2,11 +2,15 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws-payment-gateway/internal/auth/domain"
 	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/aws-payment-gateway/internal/common/featureflags"
 	"github.com/aws-payment-gateway/pkg/auth"
 	"github.com/google/uuid"
 )
@@ -17,6 +21,10 @@ type IssueApiKeyInput struct {
 	Name        string    `json:"name" validate:"required,min=3,max=100"`
 	Permissions []string  `json:"permissions" validate:"required,dive,keys,required,min=1"`
 	ExpiresIn   *int      `json:"expires_in,omitempty" validate:"omitempty,min=1,max=8760"` // hours
+	// ExternalID is an optional client reference; it must be unique within the account
+	ExternalID string `json:"external_id,omitempty" validate:"omitempty,max=128"`
+	// ResourceScopes optionally restricts the key to these resources, e.g. account IDs
+	ResourceScopes []string `json:"resource_scopes,omitempty" validate:"omitempty,max=100,dive,required,max=128"`
 }
 
 // IssueApiKeyOutput represents the output of API key issuance
@@ -30,24 +38,63 @@ type IssueApiKeyOutput struct {
 	Status      string    `json:"status"`
 	ExpiresAt   time.Time `json:"expires_at"`
 	CreatedAt   time.Time `json:"created_at"`
+	ExternalID  string    `json:"external_id,omitempty"`
+	// ResourceScopes are the resources the key is restricted to; empty means unrestricted
+	ResourceScopes []string `json:"resource_scopes,omitempty"`
+}
+
+// IssueApiKeyConfig defines configurable behaviour for API key issuance
+type IssueApiKeyConfig struct {
+	// DefaultPermissions are granted when a request omits permissions.
+	// Empty keeps the strict behaviour of requiring at least one permission.
+	DefaultPermissions []string
+	// KeyGenerator creates key secrets; nil uses auth.RandomKeyGenerator
+	KeyGenerator auth.KeyGenerator
+	// Flags gates behaviour still being rolled out (featureflags.StrictNames)
+	Flags featureflags.Flags
+	// MaxActiveKeys caps the live (not revoked) keys per account; 0 is unlimited.
+	// The repository enforces it atomically with the write.
+	MaxActiveKeys int
+	// PermissionAliases are resolved to canonical permissions before validation,
+	// so keys always store canonical names
+	PermissionAliases domain.PermissionAliases
+	// NameScope is where key names must be unique; empty means domain.KeyNameScopeNone.
+	// The check is not atomic with the write, so concurrent issuance can still race.
+	NameScope domain.KeyNameScope
 }
 
 // IssueApiKey handles the business logic for issuing a new API key
 type IssueApiKey struct {
 	accountRepo repository.AppRepository
 	apiKeyRepo  repository.ApiKeyRepository
+	config      IssueApiKeyConfig
+	keyGen      auth.KeyGenerator
 }
 
 // NewIssueApiKey creates a new IssueApiKey use case
-func NewIssueApiKey(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository) *IssueApiKey {
+func NewIssueApiKey(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository, config IssueApiKeyConfig) *IssueApiKey {
+	keyGen := config.KeyGenerator
+	if keyGen == nil {
+		keyGen = auth.RandomKeyGenerator{}
+	}
+
 	return &IssueApiKey{
 		accountRepo: accountRepo,
 		apiKeyRepo:  apiKeyRepo,
+		config:      config,
+		keyGen:      keyGen,
 	}
 }
 
 // Execute issues a new API key and returns the result
 func (uc *IssueApiKey) Execute(ctx context.Context, input IssueApiKeyInput) (*IssueApiKeyOutput, error) {
+	input.Permissions = uc.CanonicalPermissions(input.Permissions)
+
+	// Apply the configured defaults when no permissions were requested
+	if len(input.Permissions) == 0 && len(uc.config.DefaultPermissions) > 0 {
+		input.Permissions = append([]string(nil), uc.config.DefaultPermissions...)
+	}
+
 	// Validate input
 	if err := uc.validateInput(input); err != nil {
 		return nil, fmt.Errorf("invalid input: %w", err)
@@ -62,8 +109,32 @@ func (uc *IssueApiKey) Execute(ctx context.Context, input IssueApiKeyInput) (*Is
 		return nil, fmt.Errorf("account not found or inactive")
 	}
 
+	// A key can never carry more than its account is allowed
+	if outside := account.PermissionsOutsideScope(input.Permissions); len(outside) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPermissionOutsideAccountScope, strings.Join(outside, ", "))
+	}
+
+	// External IDs must be unique within the account
+	if input.ExternalID != "" {
+		existing, err := uc.apiKeyRepo.GetByExternalID(ctx, input.AccountID, input.ExternalID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check external ID: %w", err)
+		}
+		if existing != nil {
+			return nil, domain.ErrExternalIDExists
+		}
+	}
+
+	if err := uc.checkNameAvailable(ctx, input.AccountID, input.Name); err != nil {
+		return nil, err
+	}
+
 	// Generate API key and hash
-	apiKey, hashedKey, err := auth.GenerateAPIKeyWithHash()
+	keyGen, err := keyGeneratorFor(uc.keyGen, account)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, hashedKey, err := keyGen.Generate()
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate API key: %w", err)
 	}
@@ -75,67 +146,121 @@ func (uc *IssueApiKey) Execute(ctx context.Context, input IssueApiKeyInput) (*Is
 	}
 
 	// Create API key entity
-	apiKeyEntity := &domain.ApiKey{
-		ID:          uuid.New(),
-		AccountID:   input.AccountID,
-		Name:        input.Name,
-		KeyHash:     string(hashedKey),
-		Permissions: domain.ApiKeyPermissions(input.Permissions),
-		Status:      domain.ApiKeyStatusActive,
-		ExpiresAt:   expiresAt,
-		CreatedAt:   time.Now(),
-	}
-
-	// Save to repository
-	if err := uc.apiKeyRepo.Create(ctx, apiKeyEntity); err != nil {
+	apiKeyEntity, err := domain.NewApiKey(input.AccountID, input.Name, input.Permissions, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyEntity.KeyHash = hashedKey
+	apiKeyEntity.ExternalID = input.ExternalID
+	apiKeyEntity.ResourceScopes = uniqueResourceScopes(input.ResourceScopes)
+	apiKeyEntity.KeyHint = keyHintFor(keyGen, apiKey)
+
+	// Save to repository, enforcing the per-account quota
+	if err := uc.apiKeyRepo.CreateWithinQuota(ctx, apiKeyEntity, uc.config.MaxActiveKeys); err != nil {
+		if errors.Is(err, domain.ErrKeyQuotaExceeded) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create API key: %w", err)
 	}
 
 	// Create output
 	output := &IssueApiKeyOutput{
-		APIKeyID:    apiKeyEntity.ID,
-		APIKey:      apiKey, // Only return the actual key once during creation
-		KeyHash:     hashedKey,
-		AccountID:   input.AccountID,
-		Name:        input.Name,
-		Permissions: input.Permissions,
-		Status:      string(apiKeyEntity.Status),
-		ExpiresAt:   apiKeyEntity.ExpiresAt,
-		CreatedAt:   apiKeyEntity.CreatedAt,
+		APIKeyID:       apiKeyEntity.ID,
+		APIKey:         apiKey, // Only return the actual key once during creation
+		KeyHash:        hashedKey,
+		AccountID:      input.AccountID,
+		Name:           input.Name,
+		Permissions:    input.Permissions,
+		Status:         string(apiKeyEntity.Status),
+		ExpiresAt:      apiKeyEntity.ExpiresAt,
+		CreatedAt:      apiKeyEntity.CreatedAt,
+		ExternalID:     apiKeyEntity.ExternalID,
+		ResourceScopes: apiKeyEntity.ResourceScopes,
 	}
 
 	return output, nil
 }
 
+// CanonicalPermissions resolves configured permission aliases and expands
+// wildcards such as "read:*". Callers that check permissions before Execute
+// (e.g. who may grant admin:keys) must check the result.
+func (uc *IssueApiKey) CanonicalPermissions(permissions []string) []string {
+	return domain.ApiKeyPermissions(uc.config.PermissionAliases.CanonicalList(permissions)).Expand()
+}
+
+// checkNameAvailable returns domain.ErrKeyNameExists when a live key in the
+// configured scope already has the name. Revoked keys free their names.
+func (uc *IssueApiKey) checkNameAvailable(ctx context.Context, accountID uuid.UUID, name string) error {
+	var scope uuid.UUID
+	switch uc.config.NameScope {
+	case domain.KeyNameScopeAccount:
+		scope = accountID
+	case domain.KeyNameScopeGlobal:
+		scope = uuid.Nil
+	default:
+		return nil
+	}
+
+	existing, err := uc.apiKeyRepo.FindLiveByName(ctx, scope, name)
+	if err != nil {
+		return fmt.Errorf("failed to check key name: %w", err)
+	}
+	if existing != nil {
+		return domain.ErrKeyNameExists
+	}
+	return nil
+}
+
 // validateInput validates the API key issuance input
 func (uc *IssueApiKey) validateInput(input IssueApiKeyInput) error {
-	if len(input.Permissions) == 0 {
-		return fmt.Errorf("at least one permission is required")
+	if uc.config.Flags.Enabled(featureflags.StrictNames) {
+		if err := domain.CheckStrictName(input.Name); err != nil {
+			return err
+		}
 	}
 
-	for _, perm := range input.Permissions {
-		if !isValidPermission(perm) {
-			return fmt.Errorf("invalid permission: %s", perm)
-		}
+	if len(input.Permissions) == 0 {
+		return domain.ErrPermissionsRequired
 	}
 
-	return nil
+	return domain.ApiKeyPermissions(input.Permissions).Validate()
+}
+
+// keyGeneratorFor applies the account's key prefix, if it has one, to the generator.
+// The prefix is separated from the secret by an underscore, so "acme" yields "acme_...".
+func keyGeneratorFor(keyGen auth.KeyGenerator, account *domain.Account) (auth.KeyGenerator, error) {
+	if account.KeyPrefix == "" {
+		return keyGen, nil
+	}
+	prefixed, ok := keyGen.(auth.PrefixedKeyGenerator)
+	if !ok {
+		return nil, fmt.Errorf("key generator does not support per-account prefixes")
+	}
+	return prefixed.WithPrefix(account.KeyPrefix + "_"), nil
 }
 
-// isValidPermission checks if a permission is valid
-func isValidPermission(permission string) bool {
-	validPermissions := []string{
-		domain.PermissionReadAccounts,
-		domain.PermissionWriteAccounts,
-		domain.PermissionReadKeys,
-		domain.PermissionWriteKeys,
-		domain.PermissionManageWebhooks,
+// keyHintFor masks a freshly generated key for storage. Only generators that
+// report their prefix keep it in the hint; for others just the last characters show.
+func keyHintFor(keyGen auth.KeyGenerator, rawKey string) string {
+	prefix := ""
+	if prefixed, ok := keyGen.(auth.PrefixedKeyGenerator); ok {
+		prefix = prefixed.KeyPrefix()
 	}
+	return auth.MaskAPIKey(rawKey, prefix)
+}
 
-	for _, valid := range validPermissions {
-		if permission == valid {
-			return true
+// uniqueResourceScopes puts scopes in canonical form and drops repeats, keeping
+// the first of each; nil stays nil
+func uniqueResourceScopes(scopes []string) []string {
+	if len(scopes) == 0 {
+		return nil
+	}
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = domain.CanonicalResourceScope(scope)
+		if !slices.Contains(unique, scope) {
+			unique = append(unique, scope)
 		}
 	}
-	return false
+	return unique
 }
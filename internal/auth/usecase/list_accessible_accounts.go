@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// Account access scopes reported by ListAccessibleAccounts
+const (
+	AccountScopeAll = "all"
+	AccountScopeOwn = "own"
+)
+
+// ListAccessibleAccountsInput represents the input for listing the accounts a key can act on
+type ListAccessibleAccountsInput struct {
+	CallerAccountID uuid.UUID `json:"caller_account_id" validate:"required"`
+	// CrossAccount is set for keys with admin:accounts, which manage every account
+	CrossAccount bool `json:"cross_account"`
+	Limit        int  `json:"limit" validate:"min=1,max=100"`
+	Offset       int  `json:"offset" validate:"min=0"`
+}
+
+// ListAccessibleAccountsOutput represents the accounts a key can act on
+type ListAccessibleAccountsOutput struct {
+	Accounts []*domain.Account `json:"accounts"`
+	Scope    string            `json:"scope"`
+}
+
+// ListAccessibleAccounts handles the business logic for listing the accounts the
+// authenticated key can access
+type ListAccessibleAccounts struct {
+	accountRepo repository.AppRepository
+}
+
+// NewListAccessibleAccounts creates a new ListAccessibleAccounts use case
+func NewListAccessibleAccounts(accountRepo repository.AppRepository) *ListAccessibleAccounts {
+	return &ListAccessibleAccounts{
+		accountRepo: accountRepo,
+	}
+}
+
+// Execute returns every account for cross-account keys and only the key's own
+// account otherwise
+func (uc *ListAccessibleAccounts) Execute(ctx context.Context, input ListAccessibleAccountsInput) (*ListAccessibleAccountsOutput, error) {
+	// Validate input
+	if input.CallerAccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: caller_account_id is required")
+	}
+
+	if input.CrossAccount {
+		accounts, err := uc.accountRepo.List(ctx, input.Limit, input.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		return &ListAccessibleAccountsOutput{Accounts: accounts, Scope: AccountScopeAll}, nil
+	}
+
+	account, err := uc.accountRepo.GetByID(ctx, input.CallerAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	// The own account is a single-item list; later pages are empty
+	output := &ListAccessibleAccountsOutput{Accounts: []*domain.Account{}, Scope: AccountScopeOwn}
+	if input.Offset == 0 {
+		output.Accounts = append(output.Accounts, account)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// ListAccountsInput represents the input for listing accounts
+type ListAccountsInput struct {
+	CallerAccountID uuid.UUID `json:"caller_account_id" validate:"required"`
+	// AllTenants lists every account instead of only the caller's tenant (admins only)
+	AllTenants bool `json:"all_tenants"`
+	// ResourceScopes, when set, narrows the list to the accounts whose IDs it holds
+	ResourceScopes []string `json:"resource_scopes,omitempty"`
+	Limit          int      `json:"limit" validate:"min=1,max=100"`
+	Offset         int      `json:"offset" validate:"min=0"`
+}
+
+// ListAccountsOutput represents the output of listing accounts
+type ListAccountsOutput struct {
+	Accounts []*domain.Account `json:"accounts"`
+}
+
+// ListAccounts handles the business logic for listing accounts scoped to a tenant
+type ListAccounts struct {
+	accountRepo repository.AppRepository
+}
+
+// NewListAccounts creates a new ListAccounts use case
+func NewListAccounts(accountRepo repository.AppRepository) *ListAccounts {
+	return &ListAccounts{
+		accountRepo: accountRepo,
+	}
+}
+
+// Execute lists the accounts visible to the caller
+func (uc *ListAccounts) Execute(ctx context.Context, input ListAccountsInput) (*ListAccountsOutput, error) {
+	// Validate input
+	if input.CallerAccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: caller_account_id is required")
+	}
+
+	if len(input.ResourceScopes) > 0 {
+		return uc.listScoped(ctx, input)
+	}
+
+	if input.AllTenants {
+		accounts, err := uc.accountRepo.List(ctx, input.Limit, input.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		return &ListAccountsOutput{Accounts: accounts}, nil
+	}
+
+	// Resolve the caller's tenant
+	caller, err := uc.accountRepo.GetByID(ctx, input.CallerAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller account: %w", err)
+	}
+	if caller == nil || !caller.IsValid() {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	accounts, err := uc.accountRepo.ListByOwner(ctx, caller.OwnerID, input.Limit, input.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return &ListAccountsOutput{Accounts: accounts}, nil
+}
+
+// listScoped lists the accounts named by the caller's resource scopes that the
+// caller could otherwise see, newest first like the unscoped lists. Scopes hold
+// at most 100 entries, so the accounts are loaded one by one and paged in memory.
+func (uc *ListAccounts) listScoped(ctx context.Context, input ListAccountsInput) (*ListAccountsOutput, error) {
+	var ownerID uuid.UUID
+	if !input.AllTenants {
+		caller, err := uc.accountRepo.GetByID(ctx, input.CallerAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get caller account: %w", err)
+		}
+		if caller == nil || !caller.IsValid() {
+			return nil, fmt.Errorf("account not found or inactive")
+		}
+		ownerID = caller.OwnerID
+	}
+
+	var accounts []*domain.Account
+	for _, scope := range input.ResourceScopes {
+		accountID, err := uuid.Parse(scope)
+		if err != nil {
+			continue // Not an account scope
+		}
+		account, err := uc.accountRepo.GetByID(ctx, accountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account: %w", err)
+		}
+		if account == nil || (!input.AllTenants && account.OwnerID != ownerID) {
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+
+	sort.SliceStable(accounts, func(i, j int) bool {
+		return accounts[i].CreatedAt.After(accounts[j].CreatedAt)
+	})
+
+	if input.Offset >= len(accounts) {
+		return &ListAccountsOutput{Accounts: []*domain.Account{}}, nil
+	}
+	accounts = accounts[input.Offset:]
+	if len(accounts) > input.Limit {
+		accounts = accounts[:input.Limit]
+	}
+
+	return &ListAccountsOutput{Accounts: accounts}, nil
+}
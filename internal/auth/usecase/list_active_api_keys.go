@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// DefaultActiveKeysWindow is how recent a key's last use must be to be listed when no window is given
+const DefaultActiveKeysWindow = 24 * time.Hour
+
+// ListActiveApiKeysInput represents the input for listing recently used API keys
+type ListActiveApiKeysInput struct {
+	AccountID uuid.UUID     `json:"account_id" validate:"required"`
+	Within    time.Duration `json:"within" validate:"required"`
+	Limit     int           `json:"limit" validate:"min=1,max=100"`
+	Offset    int           `json:"offset" validate:"min=0"`
+}
+
+// ListActiveApiKeysOutput represents a page of recently used API keys, most recently used first
+type ListActiveApiKeysOutput struct {
+	APIKeys []*domain.ApiKey `json:"api_keys"`
+	Since   time.Time        `json:"since"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	Total   int              `json:"total"`
+}
+
+// ListActiveApiKeys handles the business logic for listing the keys an account
+// has used recently, i.e. its currently active integrations
+type ListActiveApiKeys struct {
+	accountRepo repository.AppRepository
+	apiKeyRepo  repository.ApiKeyRepository
+}
+
+// NewListActiveApiKeys creates a new ListActiveApiKeys use case
+func NewListActiveApiKeys(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository) *ListActiveApiKeys {
+	return &ListActiveApiKeys{
+		accountRepo: accountRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+// Execute returns one page of the account's keys whose last_used_at falls within input.Within
+func (uc *ListActiveApiKeys) Execute(ctx context.Context, input ListActiveApiKeysInput) (*ListActiveApiKeysOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if input.Within <= 0 {
+		return nil, fmt.Errorf("invalid input: within must be positive")
+	}
+	if input.Limit < 1 || input.Limit > 100 {
+		return nil, fmt.Errorf("invalid input: limit must be between 1 and 100")
+	}
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("invalid input: offset must not be negative")
+	}
+
+	// Verify account exists and is active
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || !account.IsValid() {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	allApiKeys, err := uc.apiKeyRepo.GetByAccountID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API keys: %w", err)
+	}
+
+	since := time.Now().UTC().Add(-input.Within)
+	apiKeys := []*domain.ApiKey{}
+	for _, apiKey := range allApiKeys {
+		if apiKey.LastUsedAt != nil && apiKey.LastUsedAt.After(since) {
+			apiKeys = append(apiKeys, apiKey)
+		}
+	}
+	sort.Slice(apiKeys, func(i, j int) bool {
+		return apiKeys[i].LastUsedAt.After(*apiKeys[j].LastUsedAt)
+	})
+
+	output := &ListActiveApiKeysOutput{
+		APIKeys: []*domain.ApiKey{},
+		Since:   since,
+		Limit:   input.Limit,
+		Offset:  input.Offset,
+		Total:   len(apiKeys),
+	}
+	if input.Offset < len(apiKeys) {
+		end := input.Offset + input.Limit
+		if end > len(apiKeys) {
+			end = len(apiKeys)
+		}
+		output.APIKeys = apiKeys[input.Offset:end]
+	}
+
+	return output, nil
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/google/uuid"
+)
+
+// MaxAuditWindow is the longest time range one audit query may cover. Each day
+// in the window is a separate partition read, so the range is capped.
+const MaxAuditWindow = 31 * 24 * time.Hour
+
+// ListAuditEventsInput represents the input for listing audit events
+type ListAuditEventsInput struct {
+	EventType string `json:"event_type" validate:"required"`
+	// AccountID restricts the results to one account; nil lists every account
+	AccountID *uuid.UUID `json:"account_id,omitempty"`
+	Since     time.Time  `json:"since"`
+	Until     time.Time  `json:"until"`
+	Limit     int        `json:"limit" validate:"min=1,max=100"`
+}
+
+// ListAuditEventsOutput represents the output of listing audit events
+type ListAuditEventsOutput struct {
+	Events []*audit.AuditEvent `json:"events"`
+}
+
+// ListAuditEvents handles the business logic for reading the audit log
+type ListAuditEvents struct {
+	reader audit.EventReader
+}
+
+// NewListAuditEvents creates a new ListAuditEvents use case
+func NewListAuditEvents(reader audit.EventReader) *ListAuditEvents {
+	return &ListAuditEvents{
+		reader: reader,
+	}
+}
+
+// Execute lists audit events newest first. Access scoping is the caller's job:
+// the use case returns whatever AccountID selects.
+func (uc *ListAuditEvents) Execute(ctx context.Context, input ListAuditEventsInput) (*ListAuditEventsOutput, error) {
+	// Validate input
+	if err := uc.validateInput(input); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	events, err := uc.reader.ListEvents(ctx, audit.EventQuery{
+		EventType: input.EventType,
+		AccountID: input.AccountID,
+		Since:     input.Since.UTC(),
+		Until:     input.Until.UTC(),
+		Limit:     input.Limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	return &ListAuditEventsOutput{
+		Events: events,
+	}, nil
+}
+
+// validateInput validates the list audit events input
+func (uc *ListAuditEvents) validateInput(input ListAuditEventsInput) error {
+	if !audit.IsKnownEventType(input.EventType) {
+		return fmt.Errorf("unknown event_type '%s'", input.EventType)
+	}
+
+	if input.Limit <= 0 || input.Limit > 100 {
+		return fmt.Errorf("limit must be between 1 and 100")
+	}
+
+	if input.Until.Before(input.Since) {
+		return fmt.Errorf("until must not be before since")
+	}
+
+	if input.Until.Sub(input.Since) > MaxAuditWindow {
+		return fmt.Errorf("time range must not exceed %d days", int(MaxAuditWindow.Hours()/24))
+	}
+
+	return nil
+}
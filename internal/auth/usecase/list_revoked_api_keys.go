@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// DefaultRevokedKeysWindow is how far back revoked keys are listed when no since is given
+const DefaultRevokedKeysWindow = 7 * 24 * time.Hour
+
+// ListRevokedApiKeysInput represents the input for listing recently revoked API keys
+type ListRevokedApiKeysInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	Since     time.Time `json:"since" validate:"required"`
+	Limit     int       `json:"limit" validate:"min=1,max=100"`
+	Offset    int       `json:"offset" validate:"min=0"`
+}
+
+// ListRevokedApiKeysOutput represents a page of revoked API keys, most recently revoked first
+type ListRevokedApiKeysOutput struct {
+	APIKeys []*domain.ApiKey `json:"api_keys"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	Total   int              `json:"total"`
+}
+
+// ListRevokedApiKeys handles the business logic for listing an account's recently revoked keys
+type ListRevokedApiKeys struct {
+	accountRepo repository.AppRepository
+	apiKeyRepo  repository.ApiKeyRepository
+}
+
+// NewListRevokedApiKeys creates a new ListRevokedApiKeys use case
+func NewListRevokedApiKeys(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository) *ListRevokedApiKeys {
+	return &ListRevokedApiKeys{
+		accountRepo: accountRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+// Execute returns one page of the account's keys revoked after input.Since
+func (uc *ListRevokedApiKeys) Execute(ctx context.Context, input ListRevokedApiKeysInput) (*ListRevokedApiKeysOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if input.Limit < 1 || input.Limit > 100 {
+		return nil, fmt.Errorf("invalid input: limit must be between 1 and 100")
+	}
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("invalid input: offset must not be negative")
+	}
+
+	// Verify account exists
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	apiKeys, err := uc.apiKeyRepo.ListRevokedSince(ctx, input.AccountID, input.Since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revoked API keys: %w", err)
+	}
+
+	output := &ListRevokedApiKeysOutput{
+		APIKeys: []*domain.ApiKey{},
+		Limit:   input.Limit,
+		Offset:  input.Offset,
+		Total:   len(apiKeys),
+	}
+	if input.Offset < len(apiKeys) {
+		end := input.Offset + input.Limit
+		if end > len(apiKeys) {
+			end = len(apiKeys)
+		}
+		output.APIKeys = apiKeys[input.Offset:end]
+	}
+
+	return output, nil
+}
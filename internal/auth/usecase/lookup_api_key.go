@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// LookupApiKeyByHashInput represents the input for looking up an API key by hash
+type LookupApiKeyByHashInput struct {
+	KeyHash string `json:"key_hash" validate:"required"`
+}
+
+// LookupApiKeyByHashOutput represents the API key that owns a hash
+type LookupApiKeyByHashOutput struct {
+	APIKey *domain.ApiKey `json:"api_key"`
+}
+
+// LookupApiKeyByHash finds which key and account a key hash belongs to, e.g. when
+// investigating a leaked hash. It never records the lookup as key usage.
+type LookupApiKeyByHash struct {
+	apiKeyRepo repository.ApiKeyRepository
+}
+
+// NewLookupApiKeyByHash creates a new LookupApiKeyByHash use case
+func NewLookupApiKeyByHash(apiKeyRepo repository.ApiKeyRepository) *LookupApiKeyByHash {
+	return &LookupApiKeyByHash{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute looks up the API key by hash
+func (uc *LookupApiKeyByHash) Execute(ctx context.Context, input LookupApiKeyByHashInput) (*LookupApiKeyByHashOutput, error) {
+	// Validate input
+	if input.KeyHash == "" {
+		return nil, fmt.Errorf("invalid input: key_hash is required")
+	}
+
+	// Pure read: must not bump last_used_at
+	apiKey, err := uc.apiKeyRepo.FindByKeyHash(ctx, input.KeyHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	return &LookupApiKeyByHashOutput{APIKey: apiKey}, nil
+}
+
+// LookupApiKeyByRawKeyInput represents the input for looking up an API key by its raw value
+type LookupApiKeyByRawKeyInput struct {
+	RawKey string `json:"raw_key" validate:"required"`
+}
+
+// LookupApiKeyByRawKeyOutput reports which key a raw key maps to and whether it is active
+type LookupApiKeyByRawKeyOutput struct {
+	Active    bool      `json:"active"`
+	AccountID uuid.UUID `json:"account_id"`
+	APIKeyID  uuid.UUID `json:"api_key_id"`
+}
+
+// LookupApiKeyByRawKey checks whether a raw key, e.g. one suspected of being
+// reused, maps to a key. Unlike validation it never records the lookup as key usage.
+type LookupApiKeyByRawKey struct {
+	apiKeyRepo repository.ApiKeyRepository
+}
+
+// NewLookupApiKeyByRawKey creates a new LookupApiKeyByRawKey use case
+func NewLookupApiKeyByRawKey(apiKeyRepo repository.ApiKeyRepository) *LookupApiKeyByRawKey {
+	return &LookupApiKeyByRawKey{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute looks up the API key by raw key. Active means the key itself is active
+// and unexpired; the account's state is not considered.
+func (uc *LookupApiKeyByRawKey) Execute(ctx context.Context, input LookupApiKeyByRawKeyInput) (*LookupApiKeyByRawKeyOutput, error) {
+	// Validate input
+	if input.RawKey == "" {
+		return nil, fmt.Errorf("invalid input: raw_key is required")
+	}
+
+	apiKey, err := uc.apiKeyRepo.FindByKey(ctx, input.RawKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	return &LookupApiKeyByRawKeyOutput{
+		Active:    apiKey.IsValid(),
+		AccountID: apiKey.AccountID,
+		APIKeyID:  apiKey.ID,
+	}, nil
+}
+
+// LookupApiKeyByExternalIDInput represents the input for looking up an API key by external ID
+type LookupApiKeyByExternalIDInput struct {
+	// AccountID is the caller's account; external IDs are only unique within an account
+	AccountID  uuid.UUID `json:"account_id" validate:"required"`
+	ExternalID string    `json:"external_id" validate:"required"`
+}
+
+// LookupApiKeyByExternalID finds one of the caller's API keys by the reference
+// the client stored on it at issuance
+type LookupApiKeyByExternalID struct {
+	apiKeyRepo repository.ApiKeyRepository
+}
+
+// NewLookupApiKeyByExternalID creates a new LookupApiKeyByExternalID use case
+func NewLookupApiKeyByExternalID(apiKeyRepo repository.ApiKeyRepository) *LookupApiKeyByExternalID {
+	return &LookupApiKeyByExternalID{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute looks up the API key by external ID within the caller's account
+func (uc *LookupApiKeyByExternalID) Execute(ctx context.Context, input LookupApiKeyByExternalIDInput) (*domain.ApiKey, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if input.ExternalID == "" {
+		return nil, fmt.Errorf("invalid input: external_id is required")
+	}
+
+	apiKey, err := uc.apiKeyRepo.GetByExternalID(ctx, input.AccountID, input.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/aws-payment-gateway/internal/auth/webhook"
+)
+
+// NotifyExpiringKeysOutput represents the output of an expiry-warning pass
+type NotifyExpiringKeysOutput struct {
+	Notified int `json:"notified"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+}
+
+// ExpiringKeyData is the payload of an api_key.expiring webhook
+type ExpiringKeyData struct {
+	APIKeyID  uuid.UUID `json:"api_key_id"`
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NotifyExpiringKeys sends an expiry-warning webhook once for each key nearing expiry
+type NotifyExpiringKeys struct {
+	apiKeyRepo  repository.ApiKeyRepository
+	accountRepo repository.AppRepository
+	notifier    webhook.Notifier
+	leadTime    time.Duration
+}
+
+// NewNotifyExpiringKeys creates a new NotifyExpiringKeys use case
+func NewNotifyExpiringKeys(apiKeyRepo repository.ApiKeyRepository, accountRepo repository.AppRepository, notifier webhook.Notifier, leadTime time.Duration) *NotifyExpiringKeys {
+	return &NotifyExpiringKeys{
+		apiKeyRepo:  apiKeyRepo,
+		accountRepo: accountRepo,
+		notifier:    notifier,
+		leadTime:    leadTime,
+	}
+}
+
+// Execute finds keys expiring within the lead time and notifies their accounts
+func (uc *NotifyExpiringKeys) Execute(ctx context.Context) (*NotifyExpiringKeysOutput, error) {
+	now := time.Now()
+
+	apiKeys, err := uc.apiKeyRepo.ListExpiringBefore(ctx, now.Add(uc.leadTime))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring API keys: %w", err)
+	}
+
+	output := &NotifyExpiringKeysOutput{}
+	accounts := make(map[uuid.UUID]*domain.Account)
+
+	for _, apiKey := range apiKeys {
+		// Already expired keys and keys that were already warned are skipped
+		if apiKey.IsExpired() || apiKey.ExpiryWarningSentAt != nil {
+			output.Skipped++
+			continue
+		}
+
+		account, ok := accounts[apiKey.AccountID]
+		if !ok {
+			account, err = uc.accountRepo.GetByID(ctx, apiKey.AccountID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get account: %w", err)
+			}
+			accounts[apiKey.AccountID] = account
+		}
+		if account == nil || !account.IsValid() || account.WebhookURL == nil {
+			output.Skipped++
+			continue
+		}
+
+		event := &webhook.Event{
+			ID:        uuid.New(),
+			Type:      webhook.EventTypeAPIKeyExpiring,
+			AccountID: apiKey.AccountID,
+			CreatedAt: now,
+			Data: ExpiringKeyData{
+				APIKeyID:  apiKey.ID,
+				Name:      apiKey.Name,
+				ExpiresAt: apiKey.ExpiresAt,
+			},
+		}
+
+		if err := uc.notifier.Notify(ctx, *account.WebhookURL, event); err != nil {
+			// Leave the key unmarked so the next run retries delivery
+			log.Printf("Failed to send expiry warning for API key %s: %v", apiKey.ID, err)
+			output.Failed++
+			continue
+		}
+
+		// Record the warning so the key is not notified again
+		sentAt := now
+		apiKey.ExpiryWarningSentAt = &sentAt
+		if err := uc.apiKeyRepo.Update(ctx, apiKey); err != nil {
+			log.Printf("Failed to record expiry warning for API key %s: %v", apiKey.ID, err)
+		}
+
+		output.Notified++
+	}
+
+	return output, nil
+}
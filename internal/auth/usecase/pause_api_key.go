@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// SetApiKeyPausedInput represents the input for pausing or resuming an API key
+type SetApiKeyPausedInput struct {
+	APIKeyID uuid.UUID `json:"api_key_id" validate:"required"`
+	// AccountID is the caller's account; keys belonging to other accounts are reported as not found
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	// ExpectedVersion rejects the update with domain.ErrVersionConflict when it
+	// does not match the stored version; nil skips the check
+	ExpectedVersion *int `json:"-"`
+}
+
+// SetApiKeyPausedOutput represents the output of pausing or resuming an API key
+type SetApiKeyPausedOutput struct {
+	APIKey *domain.ApiKey `json:"api_key"`
+}
+
+// PauseApiKey handles the business logic for temporarily suspending an API key
+type PauseApiKey struct {
+	apiKeyRepo repository.ApiKeyRepository
+}
+
+// NewPauseApiKey creates a new PauseApiKey use case
+func NewPauseApiKey(apiKeyRepo repository.ApiKeyRepository) *PauseApiKey {
+	return &PauseApiKey{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute pauses an active API key
+func (uc *PauseApiKey) Execute(ctx context.Context, input SetApiKeyPausedInput) (*SetApiKeyPausedOutput, error) {
+	return transitionApiKey(ctx, uc.apiKeyRepo, input, (*domain.ApiKey).Pause)
+}
+
+// ResumeApiKey handles the business logic for reactivating a paused API key
+type ResumeApiKey struct {
+	apiKeyRepo repository.ApiKeyRepository
+}
+
+// NewResumeApiKey creates a new ResumeApiKey use case
+func NewResumeApiKey(apiKeyRepo repository.ApiKeyRepository) *ResumeApiKey {
+	return &ResumeApiKey{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute resumes a paused API key
+func (uc *ResumeApiKey) Execute(ctx context.Context, input SetApiKeyPausedInput) (*SetApiKeyPausedOutput, error) {
+	return transitionApiKey(ctx, uc.apiKeyRepo, input, (*domain.ApiKey).Resume)
+}
+
+// transitionApiKey loads the caller's API key, applies the status transition and saves it
+func transitionApiKey(
+	ctx context.Context,
+	apiKeyRepo repository.ApiKeyRepository,
+	input SetApiKeyPausedInput,
+	transition func(*domain.ApiKey) error,
+) (*SetApiKeyPausedOutput, error) {
+	// Validate input
+	if input.APIKeyID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: api_key_id is required")
+	}
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+
+	// Get API key and make sure it belongs to the caller
+	apiKey, err := apiKeyRepo.GetByID(ctx, input.APIKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil || apiKey.AccountID != input.AccountID {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	// Reject writes based on a stale read; the repository's version condition
+	// covers changes made after this read
+	if input.ExpectedVersion != nil && *input.ExpectedVersion != apiKey.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	// Apply the transition
+	if err := transition(apiKey); err != nil {
+		return nil, err
+	}
+
+	// Save the API key (version-checked)
+	if err := apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return &SetApiKeyPausedOutput{APIKey: apiKey}, nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws-payment-gateway/internal/auth/audit"
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// PurgeAccountDataInput represents the input for purging an account's data
+type PurgeAccountDataInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	// IncludeAuditEvents also deletes the account's audit events
+	IncludeAuditEvents bool `json:"include_audit_events"`
+}
+
+// PurgeAccountDataOutput reports what was removed
+type PurgeAccountDataOutput struct {
+	AccountID              uuid.UUID `json:"account_id"`
+	APIKeysDeleted         int       `json:"api_keys_deleted"`
+	IdempotencyKeysDeleted int       `json:"idempotency_keys_deleted"`
+	AuditEventsDeleted     int       `json:"audit_events_deleted"`
+	AuditEventsPurged      bool      `json:"audit_events_purged"`
+	PurgedAt               time.Time `json:"purged_at"`
+}
+
+// PurgeAccountData handles the business logic for hard-deleting everything stored
+// about an account, for data-deletion requests
+type PurgeAccountData struct {
+	accountRepo     repository.AppRepository
+	apiKeyRepo      repository.ApiKeyRepository
+	idempotencyRepo repository.IdempotencyKeyRepository
+	eventPurger     audit.EventPurger
+}
+
+// NewPurgeAccountData creates a new PurgeAccountData use case.
+// A nil eventPurger makes IncludeAuditEvents an error.
+func NewPurgeAccountData(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository, idempotencyRepo repository.IdempotencyKeyRepository, eventPurger audit.EventPurger) *PurgeAccountData {
+	return &PurgeAccountData{
+		accountRepo:     accountRepo,
+		apiKeyRepo:      apiKeyRepo,
+		idempotencyRepo: idempotencyRepo,
+		eventPurger:     eventPurger,
+	}
+}
+
+// Execute deletes the account's API keys, idempotency keys and, when asked, audit
+// events, then the account itself. The account goes last so a purge that fails
+// part-way can simply be retried. Accounts with ledger postings cannot be
+// deleted from PostgreSQL, so they are refused before anything is removed.
+func (uc *PurgeAccountData) Execute(ctx context.Context, input PurgeAccountDataInput) (*PurgeAccountDataOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if input.IncludeAuditEvents && uc.eventPurger == nil {
+		return nil, fmt.Errorf("invalid input: audit event purging is not available")
+	}
+
+	// Soft-deleted accounts can still be purged
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	hasPostings, err := uc.accountRepo.HasLedgerPostings(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ledger postings: %w", err)
+	}
+	if hasPostings {
+		return nil, domain.ErrAccountHasLedgerPostings
+	}
+
+	output := &PurgeAccountDataOutput{AccountID: input.AccountID}
+
+	output.APIKeysDeleted, err = uc.apiKeyRepo.PurgeByAccountID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge API keys: %w", err)
+	}
+
+	output.IdempotencyKeysDeleted, err = uc.idempotencyRepo.PurgeByAccountID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	if input.IncludeAuditEvents {
+		output.AuditEventsDeleted, err = uc.eventPurger.PurgeAccountEvents(ctx, input.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to purge audit events: %w", err)
+		}
+		output.AuditEventsPurged = true
+	}
+
+	if err := uc.accountRepo.Purge(ctx, input.AccountID); err != nil {
+		return nil, fmt.Errorf("failed to purge account: %w", err)
+	}
+
+	output.PurgedAt = time.Now()
+
+	return output, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// reconcilePageSize is how many accounts are loaded per page while reconciling
+const reconcilePageSize = 100
+
+// ReconcileKeyCountersOutput represents the output of a reconciliation pass
+type ReconcileKeyCountersOutput struct {
+	Reconciled int `json:"reconciled"`
+	Failed     int `json:"failed"`
+}
+
+// ReconcileKeyCounters rebuilds every account's live key counter from its keys.
+// Counters only drift when keys expire or a write fails between the key and the
+// counter, so this is a repair pass, not something the request path depends on.
+type ReconcileKeyCounters struct {
+	accountRepo repository.AppRepository
+	apiKeyRepo  repository.ApiKeyRepository
+}
+
+// NewReconcileKeyCounters creates a new ReconcileKeyCounters use case
+func NewReconcileKeyCounters(accountRepo repository.AppRepository, apiKeyRepo repository.ApiKeyRepository) *ReconcileKeyCounters {
+	return &ReconcileKeyCounters{
+		accountRepo: accountRepo,
+		apiKeyRepo:  apiKeyRepo,
+	}
+}
+
+// Execute recounts the keys of every account. A failure on one account is logged
+// and counted; only failing to list accounts aborts the pass.
+func (uc *ReconcileKeyCounters) Execute(ctx context.Context) (*ReconcileKeyCountersOutput, error) {
+	output := &ReconcileKeyCountersOutput{}
+	seen := make(map[uuid.UUID]bool)
+
+	for offset := 0; ; offset += reconcilePageSize {
+		accounts, err := uc.accountRepo.List(ctx, reconcilePageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		added := 0
+		for _, account := range accounts {
+			if seen[account.ID] {
+				continue
+			}
+			seen[account.ID] = true
+			added++
+
+			if _, err := uc.apiKeyRepo.RecountActiveKeys(ctx, account.ID); err != nil {
+				log.Printf("Failed to reconcile key counter for account %s: %v", account.ID, err)
+				output.Failed++
+				continue
+			}
+			output.Reconciled++
+		}
+
+		// Stores that don't support offsets return the same page again
+		if len(accounts) < reconcilePageSize || added == 0 {
+			return output, nil
+		}
+	}
+}
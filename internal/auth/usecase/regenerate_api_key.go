@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/aws-payment-gateway/pkg/auth"
+	"github.com/google/uuid"
+)
+
+// RegenerateApiKeyInput represents the input for regenerating an API key secret
+type RegenerateApiKeyInput struct {
+	APIKeyID uuid.UUID `json:"api_key_id" validate:"required"`
+	// AccountID is the caller's account; keys belonging to other accounts are reported as not found
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	// ExpectedVersion rejects the update with domain.ErrVersionConflict when it
+	// does not match the stored version; nil skips the check
+	ExpectedVersion *int `json:"-"`
+}
+
+// RegenerateApiKeyOutput represents the output of regenerating an API key secret
+type RegenerateApiKeyOutput struct {
+	APIKey *domain.ApiKey `json:"api_key"`
+	RawKey string         `json:"raw_key"` // The new secret (only returned once)
+}
+
+// RegenerateApiKey handles the business logic for replacing an API key's secret
+type RegenerateApiKey struct {
+	apiKeyRepo repository.ApiKeyRepository
+	appRepo    repository.AppRepository
+	keyGen     auth.KeyGenerator
+}
+
+// NewRegenerateApiKey creates a new RegenerateApiKey use case.
+// A nil keyGen falls back to auth.RandomKeyGenerator defaults.
+func NewRegenerateApiKey(apiKeyRepo repository.ApiKeyRepository, appRepo repository.AppRepository, keyGen auth.KeyGenerator) *RegenerateApiKey {
+	if keyGen == nil {
+		keyGen = auth.RandomKeyGenerator{}
+	}
+
+	return &RegenerateApiKey{
+		apiKeyRepo: apiKeyRepo,
+		appRepo:    appRepo,
+		keyGen:     keyGen,
+	}
+}
+
+// Execute generates a new secret for the caller's API key and stores its hash.
+// The old secret stops validating as soon as the update is written.
+func (uc *RegenerateApiKey) Execute(ctx context.Context, input RegenerateApiKeyInput) (*RegenerateApiKeyOutput, error) {
+	// Validate input
+	if input.APIKeyID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: api_key_id is required")
+	}
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+
+	// Get API key and make sure it belongs to the caller
+	apiKey, err := uc.apiKeyRepo.GetByID(ctx, input.APIKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil || apiKey.AccountID != input.AccountID {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	// Reject writes based on a stale read; the repository's version condition
+	// covers changes made after this read
+	if input.ExpectedVersion != nil && *input.ExpectedVersion != apiKey.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	// Generate the new secret with the account's current key prefix
+	account, err := uc.appRepo.GetByID(ctx, apiKey.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+	keyGen, err := keyGeneratorFor(uc.keyGen, account)
+	if err != nil {
+		return nil, err
+	}
+	rawKey, hashedKey, err := keyGen.Generate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	if err := apiKey.Rehash(hashedKey); err != nil {
+		return nil, err
+	}
+	apiKey.KeyHint = keyHintFor(keyGen, rawKey)
+
+	// Save the API key (version-checked)
+	if err := uc.apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return &RegenerateApiKeyOutput{
+		APIKey: apiKey,
+		RawKey: rawKey,
+	}, nil
+}
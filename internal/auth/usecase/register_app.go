@@ -7,6 +7,8 @@ import (
 
 	"github.com/aws-payment-gateway/internal/auth/domain"
 	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/aws-payment-gateway/internal/auth/security"
+	"github.com/aws-payment-gateway/internal/common/featureflags"
 	"github.com/google/uuid"
 )
 
@@ -14,27 +16,56 @@ import (
 type RegisterAppInput struct {
 	Name       string  `json:"name" validate:"required,min=3,max=100"`
 	WebhookURL *string `json:"webhook_url,omitempty" validate:"omitempty,url"`
+	// ChallengeToken is the anti-bot challenge response supplied by the client
+	ChallengeToken string `json:"-"`
+	// CallerAccountID is the authenticated account registering a sub-account, if any.
+	// The new account joins the caller's tenant; otherwise it becomes its own tenant.
+	CallerAccountID *uuid.UUID `json:"-"`
+	// KeyPrefix is prepended to keys issued for the account instead of the
+	// service default; empty uses the default
+	KeyPrefix string `json:"key_prefix,omitempty"`
+	// Upsert returns an existing active account with the same name in the caller's
+	// tenant instead of failing. Anonymous callers cannot upsert.
+	Upsert bool `json:"-"`
 }
 
 // RegisterAppOutput represents the output of app registration
 type RegisterAppOutput struct {
 	AccountID uuid.UUID `json:"account_id"`
+	OwnerID   uuid.UUID `json:"owner_id"`
 	Name      string    `json:"name"`
 	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"created_at"`
+	KeyPrefix string    `json:"key_prefix,omitempty"`
+	// Created is false when an upsert returned an existing account
+	Created bool `json:"created"`
+}
+
+// RegisterAppConfig defines configurable behaviour for app registration
+type RegisterAppConfig struct {
+	// WebhookPolicy is enforced on the webhook URL, if one is provided
+	WebhookPolicy domain.WebhookURLPolicy
+	// Challenge verifies the pre-registration challenge; nil disables the check
+	Challenge security.ChallengeVerifier
+	// Flags gates behaviour still being rolled out (featureflags.StrictNames)
+	Flags featureflags.Flags
+	// NamePolicy restricts the characters of new account names
+	NamePolicy domain.AccountNamePolicy
 }
 
 // RegisterApp handles the business logic for registering a new app
 type RegisterApp struct {
 	appRepo     repository.AppRepository
 	accountRepo repository.ApiKeyRepository
+	config      RegisterAppConfig
 }
 
 // NewRegisterApp creates a new RegisterApp use case
-func NewRegisterApp(appRepo repository.AppRepository, accountRepo repository.ApiKeyRepository) *RegisterApp {
+func NewRegisterApp(appRepo repository.AppRepository, accountRepo repository.ApiKeyRepository, config RegisterAppConfig) *RegisterApp {
 	return &RegisterApp{
 		appRepo:     appRepo,
 		accountRepo: accountRepo,
+		config:      config,
 	}
 }
 
@@ -45,21 +76,57 @@ func (uc *RegisterApp) Execute(ctx context.Context, input RegisterAppInput) (*Re
 		return nil, fmt.Errorf("invalid input: %w", err)
 	}
 
+	// Verify the anti-bot challenge before touching storage
+	if uc.config.Challenge != nil {
+		if err := uc.config.Challenge.Verify(ctx, input.Name, input.ChallengeToken); err != nil {
+			return nil, err
+		}
+	}
+
+	// Resolve the owning tenant
+	accountID := uuid.New()
+	ownerID := accountID
+	if input.CallerAccountID != nil {
+		caller, err := uc.appRepo.GetByID(ctx, *input.CallerAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get caller account: %w", err)
+		}
+		if caller == nil || !caller.IsValid() {
+			return nil, fmt.Errorf("account not found or inactive")
+		}
+		ownerID = caller.OwnerID
+	}
+
 	// Check if app name already exists
 	existing, err := uc.appRepo.GetByName(ctx, input.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing app: %w", err)
 	}
 	if existing != nil {
+		// Only hand back an account the caller's tenant already owns, so an
+		// upsert cannot be used to discover other tenants' account IDs
+		if input.Upsert && input.CallerAccountID != nil && existing.IsValid() && existing.OwnerID == ownerID {
+			return &RegisterAppOutput{
+				AccountID: existing.ID,
+				OwnerID:   existing.OwnerID,
+				Name:      existing.Name,
+				Status:    string(existing.Status),
+				CreatedAt: existing.CreatedAt,
+				KeyPrefix: existing.KeyPrefix,
+				Created:   false,
+			}, nil
+		}
 		return nil, fmt.Errorf("app with name '%s' already exists", input.Name)
 	}
 
 	// Create new account
 	account := &domain.Account{
-		ID:         uuid.New(),
+		ID:         accountID,
+		OwnerID:    ownerID,
 		Name:       input.Name,
 		Status:     domain.AccountStatusActive,
 		WebhookURL: input.WebhookURL,
+		KeyPrefix:  input.KeyPrefix,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -71,9 +138,12 @@ func (uc *RegisterApp) Execute(ctx context.Context, input RegisterAppInput) (*Re
 	// Create output
 	output := &RegisterAppOutput{
 		AccountID: account.ID,
+		OwnerID:   account.OwnerID,
 		Name:      account.Name,
 		Status:    string(account.Status),
 		CreatedAt: account.CreatedAt,
+		KeyPrefix: account.KeyPrefix,
+		Created:   true,
 	}
 
 	return output, nil
@@ -89,15 +159,27 @@ func (uc *RegisterApp) validateInput(input RegisterAppInput) error {
 		return fmt.Errorf("name must be at least 3 characters")
 	}
 
-	if input.WebhookURL != nil && !isValidURL(*input.WebhookURL) {
-		return fmt.Errorf("invalid webhook URL format")
+	if uc.config.Flags.Enabled(featureflags.StrictNames) {
+		if err := domain.CheckStrictName(input.Name); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
+	if err := uc.config.NamePolicy.Check(input.Name); err != nil {
+		return err
+	}
+
+	if input.WebhookURL != nil {
+		if err := uc.config.WebhookPolicy.Validate(*input.WebhookURL); err != nil {
+			return err
+		}
+	}
 
-// isValidURL performs basic URL validation
-func isValidURL(url string) bool {
-	// Basic URL validation - in production, use proper URL validation library
-	return len(url) > 10 && (url[:7] == "http://" || url[:8] == "https://")
+	if input.KeyPrefix != "" {
+		if err := domain.ValidateKeyPrefix(input.KeyPrefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
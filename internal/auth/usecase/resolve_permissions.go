@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+)
+
+// ResolvePermissionsInput represents a raw permission list to preview
+type ResolvePermissionsInput struct {
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+// ResolvedPermission reports how one entry of the raw list was interpreted
+type ResolvedPermission struct {
+	Input string `json:"input"`
+	// Canonical is the entry after alias resolution
+	Canonical string `json:"canonical"`
+	// Permissions are what Canonical grants once wildcards are expanded
+	Permissions []string `json:"permissions"`
+	// Error is set when the entry would be rejected at issuance
+	Error string `json:"error,omitempty"`
+}
+
+// ResolvePermissionsOutput represents the interpreted permission list
+type ResolvePermissionsOutput struct {
+	Entries []ResolvedPermission `json:"entries"`
+	// Permissions is the deduplicated list a key would be issued with
+	Permissions domain.ApiKeyPermissions `json:"permissions"`
+	Valid       bool                     `json:"valid"`
+}
+
+// ResolvePermissions handles previewing how a permission list will be interpreted
+// at issuance, without issuing anything
+type ResolvePermissions struct {
+	aliases domain.PermissionAliases
+}
+
+// NewResolvePermissions creates a new ResolvePermissions use case
+func NewResolvePermissions(aliases domain.PermissionAliases) *ResolvePermissions {
+	return &ResolvePermissions{
+		aliases: aliases,
+	}
+}
+
+// Execute resolves each entry the same way IssueApiKey does: aliases first, then
+// wildcards, then validation. Invalid entries are reported rather than failing the call.
+func (uc *ResolvePermissions) Execute(input ResolvePermissionsInput) (*ResolvePermissionsOutput, error) {
+	// Validate input
+	if len(input.Permissions) == 0 {
+		return nil, fmt.Errorf("invalid input: permissions is required")
+	}
+
+	output := &ResolvePermissionsOutput{
+		Entries:     make([]ResolvedPermission, 0, len(input.Permissions)),
+		Permissions: domain.ApiKeyPermissions{},
+		Valid:       true,
+	}
+
+	for _, raw := range input.Permissions {
+		entry := ResolvedPermission{
+			Input:       raw,
+			Canonical:   uc.aliases.Canonical(raw),
+			Permissions: []string{},
+		}
+
+		switch expanded := (domain.ApiKeyPermissions{entry.Canonical}).Expand(); {
+		case raw == "":
+			entry.Error = "permission cannot be empty"
+		case expanded.Validate() != nil:
+			entry.Error = fmt.Sprintf("unknown permission '%s'", raw)
+		default:
+			entry.Permissions = expanded
+			output.Permissions.Add(expanded...)
+		}
+
+		if entry.Error != "" {
+			output.Valid = false
+		}
+		output.Entries = append(output.Entries, entry)
+	}
+
+	return output, nil
+}
@@ -11,6 +11,9 @@ import (
 // RevokeApiKeyInput represents the input for revoking an API key
 type RevokeApiKeyInput struct {
 	APIKeyID uuid.UUID `json:"api_key_id" validate:"required"`
+	// ExpectedVersion rejects the revocation with domain.ErrVersionConflict when
+	// it does not match the stored version; nil skips the check
+	ExpectedVersion *int `json:"-"`
 }
 
 // RevokeApiKeyOutput represents the output of API key revocation
@@ -46,8 +49,13 @@ func (uc *RevokeApiKey) Execute(ctx context.Context, input RevokeApiKeyInput) (*
 		return nil, fmt.Errorf("API key not found")
 	}
 
-	// Revoke the API key
-	if err := uc.apiKeyRepo.Revoke(ctx, input.APIKeyID); err != nil {
+	// Revoke the API key, only at the expected version when one is given
+	if input.ExpectedVersion != nil {
+		err = uc.apiKeyRepo.RevokeVersion(ctx, input.APIKeyID, *input.ExpectedVersion)
+	} else {
+		err = uc.apiKeyRepo.Revoke(ctx, input.APIKeyID)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to revoke API key: %w", err)
 	}
 
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// RevokeApiKeysInput represents the input for revoking several API keys at once
+type RevokeApiKeysInput struct {
+	// AccountID is the caller's account; only its keys are revoked
+	AccountID uuid.UUID   `json:"account_id" validate:"required"`
+	APIKeyIDs []uuid.UUID `json:"api_key_ids" validate:"required"`
+}
+
+// RevokeApiKeyResult reports the outcome for one key of a batch
+type RevokeApiKeyResult struct {
+	APIKeyID uuid.UUID `json:"api_key_id"`
+	Revoked  bool      `json:"revoked"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// RevokeApiKeysOutput represents the output of a batch revocation, one result
+// per distinct key ID in request order
+type RevokeApiKeysOutput struct {
+	Results []RevokeApiKeyResult `json:"results"`
+	Revoked int                  `json:"revoked"`
+	Failed  int                  `json:"failed"`
+}
+
+// RevokeApiKeys handles revoking a list of specific API keys, e.g. those named
+// in a leak report. Each key is handled on its own, so one failure does not
+// stop the rest.
+type RevokeApiKeys struct {
+	apiKeyRepo repository.ApiKeyRepository
+}
+
+// NewRevokeApiKeys creates a new RevokeApiKeys use case
+func NewRevokeApiKeys(apiKeyRepo repository.ApiKeyRepository) *RevokeApiKeys {
+	return &RevokeApiKeys{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute revokes each key owned by input.AccountID. Keys that do not exist and
+// keys of other accounts are both reported as not found, so a batch cannot be
+// used to probe which IDs exist.
+func (uc *RevokeApiKeys) Execute(ctx context.Context, input RevokeApiKeysInput) (*RevokeApiKeysOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if len(input.APIKeyIDs) == 0 {
+		return nil, fmt.Errorf("invalid input: api_key_ids is required")
+	}
+	if len(input.APIKeyIDs) > domain.MaxRevokeBatchSize {
+		return nil, fmt.Errorf("invalid input: at most %d API keys can be revoked at once", domain.MaxRevokeBatchSize)
+	}
+
+	output := &RevokeApiKeysOutput{
+		Results: make([]RevokeApiKeyResult, 0, len(input.APIKeyIDs)),
+	}
+
+	seen := make(map[uuid.UUID]bool, len(input.APIKeyIDs))
+	for _, apiKeyID := range input.APIKeyIDs {
+		if seen[apiKeyID] {
+			continue
+		}
+		seen[apiKeyID] = true
+
+		result := RevokeApiKeyResult{APIKeyID: apiKeyID}
+		if err := uc.revoke(ctx, input.AccountID, apiKeyID); err != nil {
+			result.Error = err.Error()
+			output.Failed++
+		} else {
+			result.Revoked = true
+			output.Revoked++
+		}
+		output.Results = append(output.Results, result)
+	}
+
+	return output, nil
+}
+
+// revoke revokes one key after checking that the account owns it
+func (uc *RevokeApiKeys) revoke(ctx context.Context, accountID, apiKeyID uuid.UUID) error {
+	apiKey, err := uc.apiKeyRepo.GetByID(ctx, apiKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil || apiKey.AccountID != accountID {
+		return fmt.Errorf("API key not found")
+	}
+
+	if err := uc.apiKeyRepo.Revoke(ctx, apiKeyID); err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return nil
+}
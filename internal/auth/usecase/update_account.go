@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// UpdateAccountInput represents the input for updating an account
+type UpdateAccountInput struct {
+	AccountID  uuid.UUID `json:"account_id" validate:"required"`
+	WebhookURL *string   `json:"webhook_url,omitempty"`
+	// KeyPrefix sets the account's key prefix; an empty string restores the default
+	KeyPrefix *string `json:"key_prefix,omitempty"`
+	// KeysDisabled turns the account-wide key switch on or off
+	KeysDisabled *bool `json:"keys_disabled,omitempty"`
+	// RequireClientCert turns the client certificate requirement on or off
+	RequireClientCert *bool `json:"require_client_cert,omitempty"`
+	// ClientCertFingerprints replaces the certificate allowlist
+	ClientCertFingerprints *[]string `json:"client_cert_fingerprints,omitempty"`
+	// AllowedPermissions replaces the cap on key permissions; an empty list removes it.
+	// Wildcards such as "read:*" are expanded before storing.
+	AllowedPermissions *[]string `json:"allowed_permissions,omitempty"`
+	// ExpectedVersion rejects the update with domain.ErrVersionConflict when it
+	// does not match the stored version; nil skips the check
+	ExpectedVersion *int `json:"-"`
+}
+
+// UpdateAccountOutput represents the output of an account update
+type UpdateAccountOutput struct {
+	Account *domain.Account `json:"account"`
+	// PreviousWebhookURL is the webhook URL before the update, for auditing
+	PreviousWebhookURL *string `json:"previous_webhook_url,omitempty"`
+}
+
+// UpdateAccount handles the business logic for updating an account
+type UpdateAccount struct {
+	accountRepo   repository.AppRepository
+	webhookPolicy domain.WebhookURLPolicy
+}
+
+// NewUpdateAccount creates a new UpdateAccount use case
+func NewUpdateAccount(accountRepo repository.AppRepository, webhookPolicy domain.WebhookURLPolicy) *UpdateAccount {
+	return &UpdateAccount{
+		accountRepo:   accountRepo,
+		webhookPolicy: webhookPolicy,
+	}
+}
+
+// Execute updates the account and returns it with its new version
+func (uc *UpdateAccount) Execute(ctx context.Context, input UpdateAccountInput) (*UpdateAccountOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if input.WebhookURL != nil && *input.WebhookURL != "" {
+		if err := uc.webhookPolicy.Validate(*input.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+	if input.KeyPrefix != nil && *input.KeyPrefix != "" {
+		if err := domain.ValidateKeyPrefix(*input.KeyPrefix); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.ClientCertFingerprints != nil {
+		for _, fingerprint := range *input.ClientCertFingerprints {
+			if err := domain.ValidateCertFingerprint(fingerprint); err != nil {
+				return nil, fmt.Errorf("%w: '%s'", err, fingerprint)
+			}
+		}
+	}
+
+	var allowedPermissions domain.ApiKeyPermissions
+	if input.AllowedPermissions != nil {
+		allowedPermissions = domain.ApiKeyPermissions(*input.AllowedPermissions).Expand()
+		if err := allowedPermissions.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Verify account exists and is active
+	account, err := uc.accountRepo.GetByID(ctx, input.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || !account.IsValid() {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+
+	// Reject writes based on a stale read
+	if input.ExpectedVersion != nil && *input.ExpectedVersion != account.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	previousWebhookURL := account.WebhookURL
+
+	// An empty webhook URL clears it
+	if input.WebhookURL != nil {
+		if *input.WebhookURL == "" {
+			account.WebhookURL = nil
+		} else {
+			account.WebhookURL = input.WebhookURL
+		}
+	}
+
+	// Only keys issued from now on use the new prefix
+	if input.KeyPrefix != nil {
+		account.KeyPrefix = *input.KeyPrefix
+	}
+
+	if input.KeysDisabled != nil {
+		account.KeysDisabled = *input.KeysDisabled
+	}
+
+	if input.ClientCertFingerprints != nil {
+		fingerprints := []string{}
+		for _, fingerprint := range *input.ClientCertFingerprints {
+			fingerprint = domain.NormalizeCertFingerprint(fingerprint)
+			if !domain.MatchCertFingerprint(fingerprints, fingerprint) {
+				fingerprints = append(fingerprints, fingerprint)
+			}
+		}
+		account.ClientCertFingerprints = fingerprints
+	}
+	// Existing keys keep their permissions; the cap applies from the next issuance
+	if input.AllowedPermissions != nil {
+		account.AllowedPermissions = []string(allowedPermissions)
+	}
+	if input.RequireClientCert != nil {
+		account.RequireClientCert = *input.RequireClientCert
+	}
+	if account.RequireClientCert && len(account.ClientCertFingerprints) == 0 {
+		return nil, domain.ErrClientCertAllowlistEmpty
+	}
+
+	// The repository re-checks the version, catching writes that land in between
+	if err := uc.accountRepo.Update(ctx, account); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update account: %w", err)
+	}
+
+	return &UpdateAccountOutput{Account: account, PreviousWebhookURL: previousWebhookURL}, nil
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws-payment-gateway/internal/auth/domain"
+	"github.com/aws-payment-gateway/internal/auth/repository"
+	"github.com/google/uuid"
+)
+
+// UpdateApiKeyPermissionsInput represents the input for replacing an API key's permissions
+type UpdateApiKeyPermissionsInput struct {
+	APIKeyID uuid.UUID `json:"api_key_id" validate:"required"`
+	// AccountID is the caller's account; keys belonging to other accounts are reported as not found
+	AccountID   uuid.UUID `json:"account_id" validate:"required"`
+	Permissions []string  `json:"permissions" validate:"required"`
+	// ExpectedVersion rejects the update with domain.ErrVersionConflict when it
+	// does not match the stored version; nil skips the check
+	ExpectedVersion *int `json:"-"`
+}
+
+// UpdateApiKeyPermissionsOutput represents the output of replacing an API key's permissions
+type UpdateApiKeyPermissionsOutput struct {
+	APIKey *domain.ApiKey `json:"api_key"`
+	// PreviousPermissions are the permissions the key held before the change
+	PreviousPermissions []string `json:"previous_permissions"`
+	Added               []string `json:"added"`
+	Removed             []string `json:"removed"`
+}
+
+// UpdateApiKeyPermissions handles replacing the permissions of an existing API key
+type UpdateApiKeyPermissions struct {
+	apiKeyRepo repository.ApiKeyRepository
+	appRepo    repository.AppRepository
+	aliases    domain.PermissionAliases
+}
+
+// NewUpdateApiKeyPermissions creates a new UpdateApiKeyPermissions use case
+func NewUpdateApiKeyPermissions(apiKeyRepo repository.ApiKeyRepository, appRepo repository.AppRepository, aliases domain.PermissionAliases) *UpdateApiKeyPermissions {
+	return &UpdateApiKeyPermissions{
+		apiKeyRepo: apiKeyRepo,
+		appRepo:    appRepo,
+		aliases:    aliases,
+	}
+}
+
+// CanonicalPermissions resolves aliases and wildcards the same way IssueApiKey
+// does, so callers can check who may grant the result before Execute
+func (uc *UpdateApiKeyPermissions) CanonicalPermissions(permissions []string) []string {
+	return domain.ApiKeyPermissions(uc.aliases.CanonicalList(permissions)).Expand()
+}
+
+// Execute replaces the caller's API key permissions with the given list. The same
+// rules as issuance apply: at least one known permission, within the account's
+// allowed permissions. The change takes effect on the key's next request.
+func (uc *UpdateApiKeyPermissions) Execute(ctx context.Context, input UpdateApiKeyPermissionsInput) (*UpdateApiKeyPermissionsOutput, error) {
+	permissions := domain.ApiKeyPermissions(uc.CanonicalPermissions(input.Permissions))
+
+	// Validate input
+	if input.APIKeyID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: api_key_id is required")
+	}
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if len(permissions) == 0 {
+		return nil, domain.ErrPermissionsRequired
+	}
+	if err := permissions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	// Get API key and make sure it belongs to the caller
+	apiKey, err := uc.apiKeyRepo.GetByID(ctx, input.APIKeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+	if apiKey == nil || apiKey.AccountID != input.AccountID {
+		return nil, fmt.Errorf("API key not found")
+	}
+
+	// Reject writes based on a stale read; the repository's version condition
+	// covers changes made after this read
+	if input.ExpectedVersion != nil && *input.ExpectedVersion != apiKey.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	// A key can never carry more than its account is allowed
+	account, err := uc.appRepo.GetByID(ctx, apiKey.AccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil || !account.IsValid() {
+		return nil, fmt.Errorf("account not found or inactive")
+	}
+	if outside := account.PermissionsOutsideScope(permissions); len(outside) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPermissionOutsideAccountScope, strings.Join(outside, ", "))
+	}
+
+	previous := append(domain.ApiKeyPermissions(nil), apiKey.Permissions...)
+	if err := apiKey.SetPermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	// Save the API key (version-checked)
+	if err := uc.apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return &UpdateApiKeyPermissionsOutput{
+		APIKey:              apiKey,
+		PreviousPermissions: previous,
+		Added:               permissionsMissingFrom(permissions, previous),
+		Removed:             permissionsMissingFrom(previous, permissions),
+	}, nil
+}
+
+// permissionsMissingFrom returns the permissions in list that other lacks, in list's order
+func permissionsMissingFrom(list, other domain.ApiKeyPermissions) []string {
+	missing := []string{}
+	for _, perm := range list {
+		if !other.Contains(perm) {
+			missing = append(missing, perm)
+		}
+	}
+	return missing
+}
@@ -16,6 +16,9 @@ type ValidateApiKeyInput struct {
 	RawKey string `json:"raw_key" validate:"required"`
 	// KeyHash is the pre-hashed API key (deprecated, use RawKey instead)
 	KeyHash string `json:"key_hash,omitempty"`
+	// ClientCertFingerprint is the fingerprint of the client certificate the
+	// key was presented with, if any; accounts with RequireClientCert need it
+	ClientCertFingerprint string `json:"client_cert_fingerprint,omitempty"`
 }
 
 // ValidateApiKeyOutput represents the output of API key validation
@@ -29,19 +32,48 @@ type ValidateApiKeyOutput struct {
 	ExpiresAt     *time.Time               `json:"expires_at,omitempty"`
 	AccountName   *string                  `json:"account_name,omitempty"`
 	AccountStatus *string                  `json:"account_status,omitempty"`
+	// Restricted is set when the account is suspended and Permissions has been
+	// narrowed to the configured suspended-account allowlist
+	Restricted bool `json:"restricted,omitempty"`
+	// InGracePeriod is set when the key has expired but is still accepted
+	// because it is within the configured expiry grace period
+	InGracePeriod bool `json:"in_grace_period,omitempty"`
+	// KeysDisabled is set when the account has disabled all of its keys
+	KeysDisabled bool `json:"keys_disabled,omitempty"`
+	// ResourceScopes are the resources the key is restricted to; empty means unrestricted
+	ResourceScopes []string `json:"resource_scopes,omitempty"`
+	// ClientCertRejected is set when the key would be valid but its account
+	// requires a client certificate and ClientCertFingerprint is not allowlisted
+	ClientCertRejected bool `json:"client_cert_rejected,omitempty"`
+}
+
+// ValidateApiKeyConfig defines configurable behaviour for API key validation
+type ValidateApiKeyConfig struct {
+	// SuspendedAllowedPermissions lets keys of suspended accounts keep validating,
+	// limited to these permissions, so the account can still read its own status.
+	// Empty (the default) means suspended accounts' keys always fail validation.
+	// This weakens suspension: anything the listed permissions can read stays
+	// reachable with a key that would otherwise be cut off, so keep it read-only.
+	SuspendedAllowedPermissions []string
+	// ExpiryGracePeriod keeps expired keys validating for this long after
+	// ExpiresAt, flagged with InGracePeriod, so clients that missed the expiry
+	// can still rotate. Zero (the default) fails keys as soon as they expire.
+	ExpiryGracePeriod time.Duration
 }
 
 // ValidateApiKey handles the business logic for validating API keys
 type ValidateApiKey struct {
 	apiKeyRepo repository.ApiKeyRepository
 	appRepo    repository.AppRepository
+	config     ValidateApiKeyConfig
 }
 
 // NewValidateApiKey creates a new ValidateApiKey use case
-func NewValidateApiKey(apiKeyRepo repository.ApiKeyRepository, appRepo repository.AppRepository) *ValidateApiKey {
+func NewValidateApiKey(apiKeyRepo repository.ApiKeyRepository, appRepo repository.AppRepository, config ValidateApiKeyConfig) *ValidateApiKey {
 	return &ValidateApiKey{
 		apiKeyRepo: apiKeyRepo,
 		appRepo:    appRepo,
+		config:     config,
 	}
 }
 
@@ -74,10 +106,16 @@ func (uc *ValidateApiKey) Execute(ctx context.Context, input ValidateApiKeyInput
 
 	// Create output
 	output := &ValidateApiKeyOutput{
-		Valid:       apiKey != nil && apiKey.IsValid() && !apiKey.IsExpired(),
+		Valid:       apiKey != nil && apiKey.Status == domain.ApiKeyStatusActive,
 		Permissions: domain.ApiKeyPermissions{}, // Initialize with empty permissions
 	}
 
+	// Expired keys only stay valid during the grace period
+	if output.Valid && apiKey.IsExpired() {
+		output.InGracePeriod = apiKey.InGracePeriod(uc.config.ExpiryGracePeriod)
+		output.Valid = output.InGracePeriod
+	}
+
 	if apiKey != nil {
 		output.AccountID = &apiKey.AccountID
 		output.APIKeyID = &apiKey.ID
@@ -85,6 +123,7 @@ func (uc *ValidateApiKey) Execute(ctx context.Context, input ValidateApiKeyInput
 		output.Permissions = apiKey.Permissions
 		output.LastUsedAt = apiKey.LastUsedAt
 		output.ExpiresAt = &apiKey.ExpiresAt
+		output.ResourceScopes = apiKey.ResourceScopes
 
 		// Get account information from PostgreSQL
 		account, err := uc.appRepo.GetByID(ctx, apiKey.AccountID)
@@ -98,8 +137,27 @@ func (uc *ValidateApiKey) Execute(ctx context.Context, input ValidateApiKeyInput
 			output.AccountName = &accountName
 			output.AccountStatus = &accountStatus
 
-			// Account must be active for API key to be valid
+			// Account must be active for API key to be valid, except for the
+			// allowlisted permissions of suspended accounts
 			if !account.IsValid() {
+				if output.Valid && account.Status == domain.AccountStatusSuspended {
+					output.Permissions = uc.suspendedPermissions(apiKey.Permissions)
+					output.Restricted = true
+					output.Valid = len(output.Permissions) > 0
+				} else {
+					output.Valid = false
+				}
+			}
+
+			// The account-wide switch overrides each key's own status
+			if account.KeysDisabled {
+				output.KeysDisabled = true
+				output.Valid = false
+			}
+
+			// Accounts requiring mutual TLS also need an allowlisted client certificate
+			if output.Valid && !account.AllowsClientCert(input.ClientCertFingerprint) {
+				output.ClientCertRejected = true
 				output.Valid = false
 			}
 		}
@@ -108,6 +166,18 @@ func (uc *ValidateApiKey) Execute(ctx context.Context, input ValidateApiKeyInput
 	return output, nil
 }
 
+// suspendedPermissions returns the key's permissions that remain usable while its account is suspended
+func (uc *ValidateApiKey) suspendedPermissions(permissions domain.ApiKeyPermissions) domain.ApiKeyPermissions {
+	allowlist := domain.ApiKeyPermissions(uc.config.SuspendedAllowedPermissions)
+	allowed := domain.ApiKeyPermissions{}
+	for _, perm := range permissions {
+		if allowlist.Contains(perm) {
+			allowed.Add(perm)
+		}
+	}
+	return allowed
+}
+
 // validateInput validates the API key validation input
 func (uc *ValidateApiKey) validateInput(input ValidateApiKeyInput) error {
 	if input.RawKey == "" && input.KeyHash == "" {
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Reasons an account+key pair failed validation
+const (
+	PairMismatchInvalidKey      = "invalid_key"
+	PairMismatchAccountMismatch = "account_mismatch"
+	PairMismatchClientCert      = "client_cert_required"
+)
+
+// ValidateApiKeyPairInput represents the input for validating an account+key pair
+type ValidateApiKeyPairInput struct {
+	AccountID uuid.UUID `json:"account_id" validate:"required"`
+	RawKey    string    `json:"raw_key" validate:"required"`
+	// ClientCertFingerprint is passed on to key validation
+	ClientCertFingerprint string `json:"client_cert_fingerprint,omitempty"`
+}
+
+// ValidateApiKeyPairOutput represents the output of account+key pair validation
+type ValidateApiKeyPairOutput struct {
+	Valid bool `json:"valid"`
+	// Reason explains why an invalid pair was rejected; empty when valid
+	Reason string `json:"reason,omitempty"`
+	// Key is the underlying key validation result. Only set when Valid, so a
+	// mismatched pair never reveals which account the key belongs to.
+	Key *ValidateApiKeyOutput `json:"key,omitempty"`
+}
+
+// ValidateApiKeyPair checks that an API key is valid and belongs to the given account
+type ValidateApiKeyPair struct {
+	validateApiKey *ValidateApiKey
+}
+
+// NewValidateApiKeyPair creates a new ValidateApiKeyPair use case
+func NewValidateApiKeyPair(validateApiKey *ValidateApiKey) *ValidateApiKeyPair {
+	return &ValidateApiKeyPair{
+		validateApiKey: validateApiKey,
+	}
+}
+
+// Execute validates the key and compares its owning account with the one supplied
+func (uc *ValidateApiKeyPair) Execute(ctx context.Context, input ValidateApiKeyPairInput) (*ValidateApiKeyPairOutput, error) {
+	// Validate input
+	if input.AccountID == uuid.Nil {
+		return nil, fmt.Errorf("invalid input: account_id is required")
+	}
+	if input.RawKey == "" {
+		return nil, fmt.Errorf("invalid input: raw_key is required")
+	}
+
+	keyOutput, err := uc.validateApiKey.Execute(ctx, ValidateApiKeyInput{
+		RawKey:                input.RawKey,
+		ClientCertFingerprint: input.ClientCertFingerprint,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only reported for the right account, so it never hints at another's policy
+	if keyOutput.ClientCertRejected && keyOutput.AccountID != nil && *keyOutput.AccountID == input.AccountID {
+		return &ValidateApiKeyPairOutput{Reason: PairMismatchClientCert}, nil
+	}
+
+	if !keyOutput.Valid || keyOutput.AccountID == nil {
+		return &ValidateApiKeyPairOutput{Reason: PairMismatchInvalidKey}, nil
+	}
+	if *keyOutput.AccountID != input.AccountID {
+		return &ValidateApiKeyPairOutput{Reason: PairMismatchAccountMismatch}, nil
+	}
+
+	return &ValidateApiKeyPairOutput{Valid: true, Key: keyOutput}, nil
+}
@@ -0,0 +1,69 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DisableFunc is called when deliveries to an account's webhook URL have failed
+// too many times in a row
+type DisableFunc func(ctx context.Context, accountID uuid.UUID, url string, failures int) error
+
+// failureKey identifies a webhook; a new URL starts with a clean record
+type failureKey struct {
+	accountID uuid.UUID
+	url       string
+}
+
+// FailureLimitNotifier wraps a Notifier and counts consecutive failed deliveries
+// per account and URL. Once a webhook reaches the limit, disable is called and
+// the count starts over. Counts are kept in memory, so they reset on restart.
+type FailureLimitNotifier struct {
+	next    Notifier
+	limit   int
+	disable DisableFunc
+
+	mu       sync.Mutex
+	failures map[failureKey]int
+}
+
+// NewFailureLimitNotifier creates a new FailureLimitNotifier
+func NewFailureLimitNotifier(next Notifier, limit int, disable DisableFunc) *FailureLimitNotifier {
+	return &FailureLimitNotifier{
+		next:     next,
+		limit:    limit,
+		disable:  disable,
+		failures: make(map[failureKey]int),
+	}
+}
+
+// Notify delivers the event and records the outcome. The delivery error is
+// returned unchanged; a failure to disable the webhook is only logged.
+func (n *FailureLimitNotifier) Notify(ctx context.Context, url string, event *Event) error {
+	err := n.next.Notify(ctx, url, event)
+
+	key := failureKey{accountID: event.AccountID, url: url}
+	n.mu.Lock()
+	if err == nil {
+		delete(n.failures, key)
+		n.mu.Unlock()
+		return nil
+	}
+	n.failures[key]++
+	failures := n.failures[key]
+	if failures >= n.limit {
+		delete(n.failures, key)
+	}
+	n.mu.Unlock()
+
+	if failures >= n.limit {
+		if disableErr := n.disable(ctx, event.AccountID, url, failures); disableErr != nil {
+			log.Printf("Failed to disable webhook for account %s after %d failed deliveries: %v", event.AccountID, failures, disableErr)
+		}
+	}
+
+	return err
+}
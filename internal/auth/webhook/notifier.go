@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	EventTypeAPIKeyExpiring = "api_key.expiring"
+)
+
+// Event represents a webhook event delivered to an account
+type Event struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	AccountID uuid.UUID   `json:"account_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Notifier defines the interface for delivering webhook events
+type Notifier interface {
+	Notify(ctx context.Context, url string, event *Event) error
+}
+
+// HTTPNotifier delivers webhook events as JSON POST requests
+type HTTPNotifier struct {
+	client        *http.Client
+	signingSecret []byte
+}
+
+// NewHTTPNotifier creates a new HTTPNotifier
+func NewHTTPNotifier(timeout time.Duration) *HTTPNotifier {
+	return &HTTPNotifier{
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// WithSigningSecret returns a copy of the notifier that signs each delivery with
+// an HMAC-SHA256 of the body. An empty secret leaves deliveries unsigned.
+func (n *HTTPNotifier) WithSigningSecret(secret string) *HTTPNotifier {
+	signed := *n
+	signed.signingSecret = []byte(secret)
+	return &signed
+}
+
+// Notify posts the event to the webhook URL
+func (n *HTTPNotifier) Notify(ctx context.Context, url string, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event.Type)
+	req.Header.Set("X-Webhook-ID", event.ID.String())
+	if len(n.signingSecret) > 0 {
+		mac := hmac.New(sha256.New, n.signingSecret)
+		mac.Write(body)
+		req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
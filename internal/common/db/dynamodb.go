@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,36 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrConditionFailed is returned when a conditional write's condition does not hold
+var ErrConditionFailed = errors.New("conditional check failed")
+
+// TransactionConditionError is returned when a transaction is cancelled because
+// one or more of its conditions did not hold. It matches ErrConditionFailed.
+type TransactionConditionError struct {
+	// Failed holds the indexes of the items whose condition failed
+	Failed []int
+}
+
+// Error implements the error interface
+func (e *TransactionConditionError) Error() string {
+	return fmt.Sprintf("transaction condition failed for items %v", e.Failed)
+}
+
+// Is makes errors.Is(err, ErrConditionFailed) true
+func (e *TransactionConditionError) Is(target error) bool {
+	return target == ErrConditionFailed
+}
+
+// ItemFailed reports whether the item at index i failed its condition
+func (e *TransactionConditionError) ItemFailed(i int) bool {
+	for _, failed := range e.Failed {
+		if failed == i {
+			return true
+		}
+	}
+	return false
+}
+
 // DynamoDBClient wraps the AWS DynamoDB client
 type DynamoDBClient struct {
 	client *dynamodb.Client
@@ -26,12 +57,18 @@ func (d *DynamoDBClient) GetTableName() string {
 
 // NewDynamoDBClient creates a new DynamoDB client
 func NewDynamoDBClient(ctx context.Context, region, table string) (*DynamoDBClient, error) {
+	return NewDynamoDBClientWithEndpoint(ctx, region, table, "")
+}
+
+// NewDynamoDBClientWithEndpoint creates a new DynamoDB client that talks to a
+// custom endpoint, such as DynamoDB Local. An empty endpoint uses AWS.
+func NewDynamoDBClientWithEndpoint(ctx context.Context, region, table, endpoint string) (*DynamoDBClient, error) {
 	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	client := dynamodb.NewFromConfig(cfg)
+	client := dynamodb.NewFromConfig(cfg, WithEndpoint(endpoint))
 
 	// Test connection
 	_, err = client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
@@ -47,6 +84,23 @@ func NewDynamoDBClient(ctx context.Context, region, table string) (*DynamoDBClie
 	}, nil
 }
 
+// NewDynamoDBClientFromAPI wraps an existing DynamoDB client without checking the table exists
+func NewDynamoDBClientFromAPI(client *dynamodb.Client, table string) *DynamoDBClient {
+	return &DynamoDBClient{
+		client: client,
+		table:  table,
+	}
+}
+
+// WithEndpoint overrides the DynamoDB endpoint. An empty endpoint leaves the default.
+func WithEndpoint(endpoint string) func(*dynamodb.Options) {
+	return func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	}
+}
+
 // PutItem puts an item into DynamoDB
 func (d *DynamoDBClient) PutItem(ctx context.Context, item interface{}) error {
 	av, err := attributevalue.MarshalMap(item)
@@ -65,6 +119,45 @@ func (d *DynamoDBClient) PutItem(ctx context.Context, item interface{}) error {
 	return nil
 }
 
+// TransactWriteItems writes items in a single all-or-nothing transaction. Items
+// without a TableName are written to the client's table. A cancellation caused by
+// failed conditions is reported as a *TransactionConditionError.
+func (d *DynamoDBClient) TransactWriteItems(ctx context.Context, items []types.TransactWriteItem) error {
+	for i := range items {
+		switch {
+		case items[i].Put != nil && items[i].Put.TableName == nil:
+			items[i].Put.TableName = aws.String(d.table)
+		case items[i].Update != nil && items[i].Update.TableName == nil:
+			items[i].Update.TableName = aws.String(d.table)
+		case items[i].Delete != nil && items[i].Delete.TableName == nil:
+			items[i].Delete.TableName = aws.String(d.table)
+		case items[i].ConditionCheck != nil && items[i].ConditionCheck.TableName == nil:
+			items[i].ConditionCheck.TableName = aws.String(d.table)
+		}
+	}
+
+	_, err := d.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		var cancelled *types.TransactionCanceledException
+		if errors.As(err, &cancelled) {
+			conditionErr := &TransactionConditionError{}
+			for i, reason := range cancelled.CancellationReasons {
+				if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+					conditionErr.Failed = append(conditionErr.Failed, i)
+				}
+			}
+			if len(conditionErr.Failed) > 0 {
+				return conditionErr
+			}
+		}
+		return fmt.Errorf("failed to write transaction: %w", err)
+	}
+
+	return nil
+}
+
 // GetItem gets an item from DynamoDB by key
 func (d *DynamoDBClient) GetItem(ctx context.Context, key map[string]types.AttributeValue, result interface{}) error {
 	resp, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
@@ -119,6 +212,73 @@ func (d *DynamoDBClient) UpdateItem(ctx context.Context, key map[string]types.At
 	return nil
 }
 
+// UpdateItemWithCondition updates an item only if the condition expression holds.
+// A failed condition is reported as ErrConditionFailed.
+func (d *DynamoDBClient) UpdateItemWithCondition(ctx context.Context, key map[string]types.AttributeValue, updateExpr, conditionExpr string, exprAttrNames map[string]string, exprAttrValues map[string]types.AttributeValue, result interface{}) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName:           aws.String(d.table),
+		Key:                 key,
+		UpdateExpression:    aws.String(updateExpr),
+		ConditionExpression: aws.String(conditionExpr),
+		ReturnValues:        types.ReturnValueUpdatedNew,
+	}
+
+	if exprAttrNames != nil {
+		input.ExpressionAttributeNames = exprAttrNames
+	}
+
+	if exprAttrValues != nil {
+		input.ExpressionAttributeValues = exprAttrValues
+	}
+
+	resp, err := d.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return ErrConditionFailed
+		}
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+
+	if result != nil {
+		err = attributevalue.UnmarshalMap(resp.Attributes, result)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal updated item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateAndGetItem updates an item only if the condition expression holds, like
+// UpdateItemWithCondition, and unmarshals the whole item as it is after the
+// update into result, so a read-modify-read can be done in one request.
+func (d *DynamoDBClient) UpdateAndGetItem(ctx context.Context, key map[string]types.AttributeValue, updateExpr, conditionExpr string, exprAttrNames map[string]string, exprAttrValues map[string]types.AttributeValue, result interface{}) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(d.table),
+		Key:                       key,
+		UpdateExpression:          aws.String(updateExpr),
+		ConditionExpression:       aws.String(conditionExpr),
+		ExpressionAttributeNames:  exprAttrNames,
+		ExpressionAttributeValues: exprAttrValues,
+		ReturnValues:              types.ReturnValueAllNew,
+	}
+
+	resp, err := d.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return ErrConditionFailed
+		}
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+
+	if err := attributevalue.UnmarshalMap(resp.Attributes, result); err != nil {
+		return fmt.Errorf("failed to unmarshal updated item: %w", err)
+	}
+	return nil
+}
+
 // QueryItems queries items from DynamoDB
 func (d *DynamoDBClient) QueryItems(ctx context.Context, input *dynamodb.QueryInput, results interface{}) error {
 	resp, err := d.client.Query(ctx, input)
@@ -134,6 +294,75 @@ func (d *DynamoDBClient) QueryItems(ctx context.Context, input *dynamodb.QueryIn
 	return nil
 }
 
+// QueryAllItems queries items from DynamoDB, following LastEvaluatedKey until all pages are read
+func (d *DynamoDBClient) QueryAllItems(ctx context.Context, input *dynamodb.QueryInput, results interface{}) error {
+	var items []map[string]types.AttributeValue
+
+	for {
+		resp, err := d.client.Query(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to query items: %w", err)
+		}
+
+		items = append(items, resp.Items...)
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+
+	err := attributevalue.UnmarshalListOfMaps(items, results)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal query results: %w", err)
+	}
+
+	return nil
+}
+
+// QueryPages queries items from DynamoDB one page at a time, passing each page to fn.
+// Unlike QueryAllItems only one page is held in memory; an error from fn stops the query.
+func (d *DynamoDBClient) QueryPages(ctx context.Context, input *dynamodb.QueryInput, fn func(items []map[string]types.AttributeValue) error) error {
+	for {
+		resp, err := d.client.Query(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to query items: %w", err)
+		}
+
+		if len(resp.Items) > 0 {
+			if err := fn(resp.Items); err != nil {
+				return err
+			}
+		}
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
+// QueryCount returns how many items match a query, following LastEvaluatedKey
+// until all pages are counted. Only counts are read, no items.
+func (d *DynamoDBClient) QueryCount(ctx context.Context, input *dynamodb.QueryInput) (int, error) {
+	input.Select = types.SelectCount
+	count := 0
+
+	for {
+		resp, err := d.client.Query(ctx, input)
+		if err != nil {
+			return 0, fmt.Errorf("failed to query items: %w", err)
+		}
+
+		count += int(resp.Count)
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			return count, nil
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+}
+
 // ScanItems scans items from DynamoDB
 func (d *DynamoDBClient) ScanItems(ctx context.Context, input *dynamodb.ScanInput, results interface{}) error {
 	resp, err := d.client.Scan(ctx, input)
@@ -149,6 +378,32 @@ func (d *DynamoDBClient) ScanItems(ctx context.Context, input *dynamodb.ScanInpu
 	return nil
 }
 
+// ScanAllItems scans items from DynamoDB, following LastEvaluatedKey until all pages are read
+func (d *DynamoDBClient) ScanAllItems(ctx context.Context, input *dynamodb.ScanInput, results interface{}) error {
+	var items []map[string]types.AttributeValue
+
+	for {
+		resp, err := d.client.Scan(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to scan items: %w", err)
+		}
+
+		items = append(items, resp.Items...)
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+
+	err := attributevalue.UnmarshalListOfMaps(items, results)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal scan results: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteItem deletes an item from DynamoDB
 func (d *DynamoDBClient) DeleteItem(ctx context.Context, key map[string]types.AttributeValue) error {
 	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
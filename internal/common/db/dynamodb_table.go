@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TableAPI is the subset of the DynamoDB client used to manage tables,
+// so callers can substitute a stub
+type TableAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+}
+
+// tableActiveTimeout bounds how long EnsureTable waits for a new table
+const tableActiveTimeout = 2 * time.Minute
+
+// Index names used by the repositories
+const (
+	GSI1IndexName = "gsi1"
+	GSI2IndexName = "gsi2"
+	GSI3IndexName = "gsi3"
+	GSI4IndexName = "gsi4"
+)
+
+// AuthTableDefinition returns the CreateTable input for the auth service table:
+// a pk/sk primary key plus the gsi1 (gsi1pk), gsi2 (gsi2pk), sparse gsi3
+// (gsi3pk) and sparse gsi4 (gsi4pk) lookup indexes, billed on demand
+func AuthTableDefinition(table string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(table),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("gsi1pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("gsi2pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("gsi3pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("gsi4pk"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(GSI1IndexName),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("gsi1pk"), KeyType: types.KeyTypeHash},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+			{
+				IndexName: aws.String(GSI2IndexName),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("gsi2pk"), KeyType: types.KeyTypeHash},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+			{
+				IndexName: aws.String(GSI3IndexName),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("gsi3pk"), KeyType: types.KeyTypeHash},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+			{
+				IndexName: aws.String(GSI4IndexName),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("gsi4pk"), KeyType: types.KeyTypeHash},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	}
+}
+
+// AuditTableDefinition returns the CreateTable input for the audit log table:
+// a pk/sk primary key plus the sparse, keys-only gsi1 (gsi1pk/gsi1sk) index
+// used to count an account's authentications, billed on demand
+func AuditTableDefinition(table string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(table),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("gsi1pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("gsi1sk"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String(GSI1IndexName),
+				KeySchema: []types.KeySchemaElement{
+					{AttributeName: aws.String("gsi1pk"), KeyType: types.KeyTypeHash},
+					{AttributeName: aws.String("gsi1sk"), KeyType: types.KeyTypeRange},
+				},
+				Projection: &types.Projection{ProjectionType: types.ProjectionTypeKeysOnly},
+			},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	}
+}
+
+// EnsureTable creates the table described by spec if it does not exist and
+// waits for it to become active. It is idempotent: an existing table is left
+// untouched, but an error is returned if it lacks any of spec's global
+// secondary indexes. Reports whether the table was created.
+func EnsureTable(ctx context.Context, api TableAPI, spec *dynamodb.CreateTableInput) (bool, error) {
+	table := aws.ToString(spec.TableName)
+
+	existing, err := api.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: spec.TableName})
+	if err == nil {
+		return false, checkIndexes(table, existing.Table, spec)
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return false, fmt.Errorf("failed to describe table %s: %w", table, err)
+	}
+
+	if _, err := api.CreateTable(ctx, spec); err != nil {
+		// Another process may have created it in the meantime
+		var inUse *types.ResourceInUseException
+		if !errors.As(err, &inUse) {
+			return false, fmt.Errorf("failed to create table %s: %w", table, err)
+		}
+	}
+
+	waiter := dynamodb.NewTableExistsWaiter(api)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: spec.TableName}, tableActiveTimeout); err != nil {
+		return true, fmt.Errorf("table %s did not become active: %w", table, err)
+	}
+
+	return true, nil
+}
+
+// checkIndexes reports any of spec's global secondary indexes missing from an existing table
+func checkIndexes(table string, description *types.TableDescription, spec *dynamodb.CreateTableInput) error {
+	present := make(map[string]bool)
+	if description != nil {
+		for _, index := range description.GlobalSecondaryIndexes {
+			present[aws.ToString(index.IndexName)] = true
+		}
+	}
+
+	var missing []string
+	for _, index := range spec.GlobalSecondaryIndexes {
+		if name := aws.ToString(index.IndexName); !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("table %s exists but is missing global secondary indexes: %s", table, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// Package dynamodbtest provisions throwaway tables in DynamoDB Local for
+// integration tests. Tests using it should be gated behind the "integration"
+// build tag so `go test ./...` stays fast and needs no running database.
+package dynamodbtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/google/uuid"
+
+	"github.com/aws-payment-gateway/internal/common/db"
+)
+
+// DefaultEndpoint is where DynamoDB Local listens in docker-compose.yml
+const DefaultEndpoint = "http://localhost:8000"
+
+// Endpoint returns the DynamoDB Local endpoint from DYNAMODB_LOCAL_ENDPOINT,
+// falling back to DefaultEndpoint
+func Endpoint() string {
+	if endpoint := os.Getenv("DYNAMODB_LOCAL_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return DefaultEndpoint
+}
+
+// NewLocalTable creates a uniquely named auth table (with gsi1 and gsi2) in
+// DynamoDB Local and returns a client for it. Call the returned cleanup
+// function to drop the table.
+func NewLocalTable(ctx context.Context, endpoint string) (*db.DynamoDBClient, func(), error) {
+	client := dynamodb.New(dynamodb.Options{
+		Region:      "us-west-2",
+		Credentials: credentials.NewStaticCredentialsProvider("local", "local", ""),
+	}, db.WithEndpoint(endpoint))
+
+	table := fmt.Sprintf("auth-test-%s", uuid.New().String())
+	if _, err := db.EnsureTable(ctx, client, db.AuthTableDefinition(table)); err != nil {
+		return nil, nil, fmt.Errorf("failed to set up table at %s: %w", endpoint, err)
+	}
+
+	cleanup := func() {
+		_, _ = client.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(table)})
+	}
+
+	return db.NewDynamoDBClientFromAPI(client, table), cleanup, nil
+}
@@ -0,0 +1,67 @@
+package featureflags
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Flag names. Flags gate behaviour changes that could break existing clients,
+// so they can be rolled out one deployment at a time; all are off by default.
+const (
+	// StrictNames rejects account and API key names with leading or trailing
+	// whitespace or control characters instead of storing them as sent
+	StrictNames = "strict_names"
+)
+
+// known lists every flag that can be enabled
+var known = []string{
+	StrictNames,
+}
+
+// Flags is the set of enabled feature flags. The zero value has every flag off.
+type Flags struct {
+	enabled map[string]bool
+}
+
+// New returns Flags with the named flags enabled. Unknown names are an error so
+// a typo in configuration doesn't silently leave a flag off.
+func New(names ...string) (Flags, error) {
+	flags := Flags{enabled: make(map[string]bool, len(names))}
+	for _, name := range names {
+		if !isKnown(name) {
+			return Flags{}, fmt.Errorf("unknown feature flag '%s'", name)
+		}
+		flags.enabled[name] = true
+	}
+	return flags, nil
+}
+
+// Enabled reports whether the named flag is on
+func (f Flags) Enabled(name string) bool {
+	return f.enabled[name]
+}
+
+// Names returns the enabled flags in sorted order
+func (f Flags) Names() []string {
+	names := make([]string, 0, len(f.enabled))
+	for name := range f.enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Known returns every flag that can be enabled
+func Known() []string {
+	return append([]string(nil), known...)
+}
+
+// isKnown checks if a flag name is defined
+func isKnown(name string) bool {
+	for _, k := range known {
+		if name == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// SecretsManagerAPI is the subset of the Secrets Manager client used here,
+// so callers can substitute a stub
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SecretsManagerClient wraps the AWS Secrets Manager client
+type SecretsManagerClient struct {
+	api SecretsManagerAPI
+}
+
+// NewSecretsManagerClient creates a new Secrets Manager client from the default AWS config
+func NewSecretsManagerClient(ctx context.Context, region string) (*SecretsManagerClient, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return NewSecretsManagerClientWithAPI(secretsmanager.NewFromConfig(cfg)), nil
+}
+
+// NewSecretsManagerClientWithAPI creates a client around an existing API implementation
+func NewSecretsManagerClientWithAPI(api SecretsManagerAPI) *SecretsManagerClient {
+	return &SecretsManagerClient{
+		api: api,
+	}
+}
+
+// GetString returns the string value of a secret by ARN or name
+func (c *SecretsManagerClient) GetString(ctx context.Context, secretID string) (string, error) {
+	output, err := c.api.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", secretID, err)
+	}
+
+	if output.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+
+	return *output.SecretString, nil
+}
+
+// GetJSON unmarshals a JSON secret by ARN or name into v
+func (c *SecretsManagerClient) GetJSON(ctx context.Context, secretID string, v interface{}) error {
+	value, err := c.GetString(ctx, secretID)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return fmt.Errorf("failed to parse secret %s: %w", secretID, err)
+	}
+
+	return nil
+}
+
+// DatabaseCredentials is the JSON layout of an RDS-style database secret.
+// Empty fields are left to the environment configuration.
+type DatabaseCredentials struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Host     string      `json:"host"`
+	Port     json.Number `json:"port"`
+	DBName   string      `json:"dbname"`
+}
+
+// GetDatabaseCredentials fetches database credentials by secret ARN or name
+func (c *SecretsManagerClient) GetDatabaseCredentials(ctx context.Context, secretID string) (*DatabaseCredentials, error) {
+	var creds DatabaseCredentials
+	if err := c.GetJSON(ctx, secretID, &creds); err != nil {
+		return nil, err
+	}
+
+	if creds.Username == "" || creds.Password == "" {
+		return nil, fmt.Errorf("secret %s is missing username or password", secretID)
+	}
+
+	return &creds, nil
+}
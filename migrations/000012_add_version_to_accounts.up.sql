@@ -0,0 +1,3 @@
+-- +migrate Up
+-- Version is incremented on every update and used for optimistic concurrency (ETag / If-Match)
+ALTER TABLE accounts ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
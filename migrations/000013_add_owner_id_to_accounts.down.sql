@@ -0,0 +1,3 @@
+-- +migrate Down
+DROP INDEX IF EXISTS idx_accounts_owner_id;
+ALTER TABLE accounts DROP COLUMN IF EXISTS owner_id;
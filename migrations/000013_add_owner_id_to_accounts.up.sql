@@ -0,0 +1,8 @@
+-- +migrate Up
+-- owner_id is the tenant that owns the account; root accounts own themselves
+ALTER TABLE accounts ADD COLUMN owner_id UUID NULL;
+UPDATE accounts SET owner_id = id WHERE owner_id IS NULL;
+ALTER TABLE accounts ALTER COLUMN owner_id SET NOT NULL;
+
+-- Create index for owner-scoped listing
+CREATE INDEX idx_accounts_owner_id ON accounts(owner_id);
@@ -0,0 +1,2 @@
+-- +migrate Down
+ALTER TABLE accounts DROP COLUMN IF EXISTS key_prefix;
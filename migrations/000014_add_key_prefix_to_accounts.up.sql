@@ -0,0 +1,3 @@
+-- +migrate Up
+-- key_prefix overrides the service-wide API key prefix for the account; empty uses the default
+ALTER TABLE accounts ADD COLUMN key_prefix VARCHAR(16) NOT NULL DEFAULT '';
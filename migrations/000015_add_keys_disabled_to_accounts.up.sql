@@ -0,0 +1,3 @@
+-- +migrate Up
+-- keys_disabled rejects every API key of the account without revoking them; clearing it restores the keys
+ALTER TABLE accounts ADD COLUMN keys_disabled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+-- +migrate Down
+ALTER TABLE accounts DROP COLUMN IF EXISTS client_cert_fingerprints;
+ALTER TABLE accounts DROP COLUMN IF EXISTS require_client_cert;
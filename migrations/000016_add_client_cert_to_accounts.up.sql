@@ -0,0 +1,5 @@
+-- +migrate Up
+-- require_client_cert rejects the account's keys unless the proxy reports an allowlisted client certificate
+ALTER TABLE accounts ADD COLUMN require_client_cert BOOLEAN NOT NULL DEFAULT FALSE;
+-- client_cert_fingerprints holds lowercase hex SHA-256 fingerprints without separators
+ALTER TABLE accounts ADD COLUMN client_cert_fingerprints TEXT[] NOT NULL DEFAULT '{}';
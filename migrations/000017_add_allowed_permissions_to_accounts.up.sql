@@ -0,0 +1,3 @@
+-- +migrate Up
+-- allowed_permissions caps the permissions the account's keys may be issued with; empty means no cap
+ALTER TABLE accounts ADD COLUMN allowed_permissions TEXT[] NOT NULL DEFAULT '{}';
@@ -2,10 +2,11 @@ package auth
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
-
-	"golang.org/x/crypto/bcrypt"
+	"strings"
 )
 
 // GenerateAPIKey generates a new secure API key
@@ -22,31 +23,110 @@ func GenerateAPIKey() (string, error) {
 	return apiKey, nil
 }
 
-// HashAPIKey creates a bcrypt hash of the API key for secure storage
+// HashAPIKey returns the hex-encoded SHA-256 of the API key for storage. The
+// hash is deterministic so the repository can look a raw key up by it (see
+// DynamoDBApiKeyRepository.queryByRawKey, which computes the same hash); keys
+// carry 256 bits of randomness, so a slow password hash adds nothing.
 func HashAPIKey(apiKey string) (string, error) {
-	hashedKey, err := bcrypt.GenerateFromPassword([]byte(apiKey), bcrypt.DefaultCost)
-	if err != nil {
-		return "", fmt.Errorf("failed to hash API key: %w", err)
-	}
-
-	return string(hashedKey), nil
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:]), nil
 }
 
-// ValidateAPIKey compares a raw API key with its hash
+// ValidateAPIKey compares a raw API key with its hash in constant time
 func ValidateAPIKey(apiKey, hashedKey string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedKey), []byte(apiKey))
+	keyHash, err := HashAPIKey(apiKey)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(keyHash), []byte(hashedKey)) != 1 {
+		return fmt.Errorf("API key does not match hash")
+	}
+
+	return nil
 }
 
 // GenerateAPIKeyWithHash generates a new API key and returns both the key and its hash
 func GenerateAPIKeyWithHash() (apiKey string, keyHash string, err error) {
-	// Generate API key
-	apiKey, err = GenerateAPIKey()
-	if err != nil {
-		return "", "", err
+	return RandomKeyGenerator{}.Generate()
+}
+
+// KeyGenerator produces a new raw API key together with the hash that is stored
+type KeyGenerator interface {
+	Generate() (raw string, hash string, err error)
+}
+
+// PrefixedKeyGenerator is a KeyGenerator that can produce keys with a different
+// prefix, e.g. one chosen per account
+type PrefixedKeyGenerator interface {
+	KeyGenerator
+	WithPrefix(prefix string) KeyGenerator
+	// KeyPrefix returns the prefix generated keys start with
+	KeyPrefix() string
+}
+
+// KeyHintLength is how many trailing characters of a key MaskAPIKey keeps
+const KeyHintLength = 4
+
+// minMaskedSecretLength is the shortest secret MaskAPIKey will reveal the end of
+const minMaskedSecretLength = 16
+
+// MaskAPIKey returns a hint identifying apiKey without exposing it: the known,
+// non-secret prefix followed by "…" and the last KeyHintLength characters, e.g.
+// "pk_live_…9f3a". Keys too short for that to be safe get no trailing characters.
+func MaskAPIKey(apiKey, prefix string) string {
+	if !strings.HasPrefix(apiKey, prefix) {
+		prefix = ""
+	}
+	secret := apiKey[len(prefix):]
+	if len(secret) < minMaskedSecretLength {
+		return prefix + "…"
+	}
+	return prefix + "…" + secret[len(secret)-KeyHintLength:]
+}
+
+// defaultKeyBytes is the amount of randomness in a generated key
+const defaultKeyBytes = 32
+
+// RandomKeyGenerator is the default KeyGenerator: crypto-random bytes, hex
+// encoded by default, with an optional prefix, hashed with HashAPIKey
+type RandomKeyGenerator struct {
+	// Prefix is prepended to every key, e.g. "pk_live_", to make keys recognizable
+	Prefix string
+	// Bytes is the number of random bytes; defaults to 32
+	Bytes int
+	// Encode renders the random bytes; defaults to hex.EncodeToString
+	Encode func([]byte) string
+}
+
+// WithPrefix returns a copy of the generator that uses prefix instead of g.Prefix
+func (g RandomKeyGenerator) WithPrefix(prefix string) KeyGenerator {
+	g.Prefix = prefix
+	return g
+}
+
+// KeyPrefix returns g.Prefix
+func (g RandomKeyGenerator) KeyPrefix() string {
+	return g.Prefix
+}
+
+// Generate creates a new key and its hash
+func (g RandomKeyGenerator) Generate() (string, string, error) {
+	n := g.Bytes
+	if n <= 0 {
+		n = defaultKeyBytes
+	}
+	encode := g.Encode
+	if encode == nil {
+		encode = hex.EncodeToString
+	}
+
+	keyBytes := make([]byte, n)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", "", fmt.Errorf("failed to generate random bytes: %w", err)
 	}
+	apiKey := g.Prefix + encode(keyBytes)
 
-	// Hash the API key
-	keyHash, err = HashAPIKey(apiKey)
+	keyHash, err := HashAPIKey(apiKey)
 	if err != nil {
 		return "", "", err
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/aws-payment-gateway/internal/auth/adapter/http/dto"
+	"github.com/aws-payment-gateway/internal/auth/domain"
 	"github.com/aws-payment-gateway/internal/auth/repository"
 )
 
@@ -41,7 +42,7 @@ func (m *SharedAuthMiddleware) RequireAuth() fiber.Handler {
 
 		if apiKey == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "missing_api_key",
+				Error:   domain.ErrCodeMissingAPIKey,
 				Message: "API key is required",
 			})
 		}
@@ -51,15 +52,15 @@ func (m *SharedAuthMiddleware) RequireAuth() fiber.Handler {
 		validatedKey, err := m.apiKeyRepo.ValidateByKey(ctx, apiKey)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   "validation_error",
+				Error:   domain.ErrCodeValidationFailed,
 				Message: "Failed to validate API key",
 				Details: err.Error(),
 			})
 		}
 
-		if validatedKey == nil {
+		if validatedKey == nil || validatedKey.IsExpired() {
 			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "invalid_api_key",
+				Error:   domain.ErrCodeInvalidAPIKey,
 				Message: "API key is invalid or expired",
 			})
 		}
@@ -82,7 +83,7 @@ func (m *SharedAuthMiddleware) RequirePermission(permission string) fiber.Handle
 		permissions := c.Locals("permissions")
 		if permissions == nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "not_authenticated",
+				Error:   domain.ErrCodeNotAuthenticated,
 				Message: "Authentication required",
 			})
 		}
@@ -91,21 +92,19 @@ func (m *SharedAuthMiddleware) RequirePermission(permission string) fiber.Handle
 		userPermissions, ok := permissions.([]string)
 		if !ok {
 			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   "internal_error",
+				Error:   domain.ErrCodeInternalError,
 				Message: "Invalid permissions format",
 			})
 		}
 
-		for _, p := range userPermissions {
-			if p == permission {
-				// User has required permission, continue
-				return c.Next()
-			}
+		if domain.ApiKeyPermissions(userPermissions).Contains(permission) {
+			// User has required permission, continue
+			return c.Next()
 		}
 
 		// User doesn't have required permission
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
-			Error:   "insufficient_permissions",
+			Error:   domain.ErrCodeInsufficientPermissions,
 			Message: fmt.Sprintf("Permission '%s' is required", permission),
 		})
 	}
@@ -118,7 +117,7 @@ func (m *SharedAuthMiddleware) RequireAnyPermission(permissions ...string) fiber
 		userPermissions := c.Locals("permissions")
 		if userPermissions == nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
-				Error:   "not_authenticated",
+				Error:   domain.ErrCodeNotAuthenticated,
 				Message: "Authentication required",
 			})
 		}
@@ -127,23 +126,21 @@ func (m *SharedAuthMiddleware) RequireAnyPermission(permissions ...string) fiber
 		userPermList, ok := userPermissions.([]string)
 		if !ok {
 			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
-				Error:   "internal_error",
+				Error:   domain.ErrCodeInternalError,
 				Message: "Invalid permissions format",
 			})
 		}
 
 		for _, requiredPerm := range permissions {
-			for _, userPerm := range userPermList {
-				if userPerm == requiredPerm {
-					// User has required permission, continue
-					return c.Next()
-				}
+			if domain.ApiKeyPermissions(userPermList).Contains(requiredPerm) {
+				// User has required permission, continue
+				return c.Next()
 			}
 		}
 
 		// User doesn't have any of the required permissions
 		return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
-			Error:   "insufficient_permissions",
+			Error:   domain.ErrCodeInsufficientPermissions,
 			Message: fmt.Sprintf("One of these permissions is required: %v", permissions),
 		})
 	}
@@ -216,11 +213,5 @@ func HasPermission(c *fiber.Ctx, permission string) bool {
 		return false
 	}
 
-	for _, p := range permissions {
-		if p == permission {
-			return true
-		}
-	}
-
-	return false
+	return domain.ApiKeyPermissions(permissions).Contains(permission)
 }